@@ -5,6 +5,8 @@ import (
 
 	"github.com/rs/zerolog/log"
 	"github.com/urfave/cli/v2"
+	"github.com/youkoulayley/pet-reminder-bot/cmd/migrate"
+	"github.com/youkoulayley/pet-reminder-bot/cmd/outbox"
 	"github.com/youkoulayley/pet-reminder-bot/cmd/run"
 )
 
@@ -13,6 +15,8 @@ func main() {
 		Name: "Pet Reminder Bot",
 		Commands: []*cli.Command{
 			run.Command(),
+			migrate.Command(),
+			outbox.Command(),
 		},
 	}
 
@@ -0,0 +1,91 @@
+package migrate
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/logger"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/migration"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/store"
+)
+
+func status(ctx *cli.Context) error {
+	return withMigrator(ctx, func(m *migration.Migrator) error {
+		statuses, err := m.Status(ctx.Context)
+		if err != nil {
+			return fmt.Errorf("status: %w", err)
+		}
+
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied " + s.AppliedAt.Format(time.RFC3339)
+			}
+
+			fmt.Fprintf(ctx.App.Writer, "%4d  %-30s  %s\n", s.Migration.Version, state, s.Migration.Description)
+		}
+
+		return nil
+	})
+}
+
+func up(ctx *cli.Context) error {
+	return withMigrator(ctx, func(m *migration.Migrator) error {
+		applied, err := m.Up(ctx.Context)
+		for _, mig := range applied {
+			fmt.Fprintf(ctx.App.Writer, "%4d  applied  %s\n", mig.Version, mig.Description)
+		}
+
+		if err != nil {
+			return fmt.Errorf("up: %w", err)
+		}
+
+		if len(applied) == 0 {
+			fmt.Fprintln(ctx.App.Writer, "No pending migration")
+		}
+
+		return nil
+	})
+}
+
+func dryRun(ctx *cli.Context) error {
+	return withMigrator(ctx, func(m *migration.Migrator) error {
+		pending, err := m.Pending(ctx.Context)
+		if err != nil {
+			return fmt.Errorf("pending: %w", err)
+		}
+
+		if len(pending) == 0 {
+			fmt.Fprintln(ctx.App.Writer, "No pending migration")
+
+			return nil
+		}
+
+		for _, mig := range pending {
+			fmt.Fprintf(ctx.App.Writer, "%4d  would apply  %s\n", mig.Version, mig.Description)
+		}
+
+		return nil
+	})
+}
+
+func withMigrator(ctx *cli.Context, fn func(m *migration.Migrator) error) error {
+	if err := logger.Setup(ctx.String(flagLogLevel), ctx.String(flagLogFormat)); err != nil {
+		return fmt.Errorf("setup logger: %w", err)
+	}
+
+	client, err := store.NewMongoClient(ctx.Context, ctx.String(flagMongoURI))
+	if err != nil {
+		return fmt.Errorf("new MongoDB client: %w", err)
+	}
+
+	defer func() { _ = client.Disconnect(ctx.Context) }()
+
+	m, err := migration.New(client.Database(store.DatabaseName), migration.All())
+	if err != nil {
+		return fmt.Errorf("new migrator: %w", err)
+	}
+
+	return fn(m)
+}
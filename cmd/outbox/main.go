@@ -0,0 +1,53 @@
+package outbox
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/logger"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/store"
+)
+
+func requeue(ctx *cli.Context) error {
+	return withStore(ctx, func(s store.Store) error {
+		n, err := s.RequeueDeadOutboxMessages(ctx.Context, time.Now())
+		if err != nil {
+			return fmt.Errorf("requeue: %w", err)
+		}
+
+		fmt.Fprintf(ctx.App.Writer, "%d message(s) requeued\n", n)
+
+		return nil
+	})
+}
+
+func withStore(ctx *cli.Context, fn func(s store.Store) error) error {
+	if err := logger.Setup(ctx.String(flagLogLevel), ctx.String(flagLogFormat)); err != nil {
+		return fmt.Errorf("setup logger: %w", err)
+	}
+
+	switch driver := ctx.String(flagStoreDriver); driver {
+	case storeDriverMongo:
+		client, err := store.NewMongoClient(ctx.Context, ctx.String(flagMongoURI))
+		if err != nil {
+			return fmt.Errorf("new MongoDB client: %w", err)
+		}
+
+		defer func() { _ = client.Disconnect(ctx.Context) }()
+
+		return fn(store.NewMongo(client, store.DatabaseName))
+	case storeDriverBolt:
+		// The file is locked by the running bot, which has to be stopped first.
+		s, err := store.NewBolt(ctx.String(flagStorePath))
+		if err != nil {
+			return fmt.Errorf("open bolt store: %w", err)
+		}
+
+		defer func() { _ = s.Close() }()
+
+		return fn(s)
+	default:
+		return fmt.Errorf("unknown store driver %q", driver)
+	}
+}
@@ -1,7 +1,12 @@
 package run
 
 import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -9,17 +14,24 @@ import (
 
 	"github.com/rs/zerolog/log"
 	"github.com/skwair/harmony"
+	"github.com/skwair/harmony/discord"
 	"github.com/urfave/cli/v2"
 	"github.com/youkoulayley/pet-reminder-bot/pkg/bot"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/clock"
 	"github.com/youkoulayley/pet-reminder-bot/pkg/handlers"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/i18n"
 	"github.com/youkoulayley/pet-reminder-bot/pkg/logger"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/migration"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/outbox"
 	"github.com/youkoulayley/pet-reminder-bot/pkg/reminder"
 	"github.com/youkoulayley/pet-reminder-bot/pkg/store"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-const databaseName = "pet-reminder-bot"
+const (
+	storeDriverMongo  = "mongo"
+	storeDriverBolt   = "bolt"
+	storeDriverMemory = "memory"
+)
 
 func run(ctx *cli.Context) error {
 	if err := logger.Setup(ctx.String(flagLogLevel), ctx.String(flagLogFormat)); err != nil {
@@ -33,52 +45,79 @@ func run(ctx *cli.Context) error {
 
 	channel := discordClient.Channel(ctx.String(flagBotChannelID))
 
-	opts := options.Client().
-		ApplyURI(ctx.String(flagMongoURI)).
-		SetSocketTimeout(2 * time.Second).
-		SetConnectTimeout(10 * time.Second).
-		SetServerSelectionTimeout(10 * time.Second)
-
-	client, err := mongo.NewClient(opts)
+	s, closeStore, err := newStore(ctx)
 	if err != nil {
-		return fmt.Errorf("create MongoDB client: %w", err)
+		return fmt.Errorf("new store: %w", err)
 	}
 
-	if err = client.Connect(ctx.Context); err != nil {
-		return fmt.Errorf("connect db: %w", err)
+	defer closeStore()
+
+	// The catalog is upserted before anything reads the store, so the catch-up uses the current food durations.
+	pets, err := loadPets(ctx.String(flagPetsFile))
+	if err != nil {
+		return fmt.Errorf("load pets: %w", err)
 	}
 
-	defer func() { _ = client.Disconnect(ctx.Context) }()
+	if err = s.Bootstrap(ctx.Context, pets); err != nil {
+		return fmt.Errorf("bootstrap: %w", err)
+	}
 
-	s := store.New(client, databaseName)
+	clk := clock.New()
 
-	r, err := reminder.New(s, channel)
+	policy, err := reminder.ParseCatchUpPolicy(ctx.String(flagCatchUp))
 	if err != nil {
-		return fmt.Errorf("new reminder: %w", err)
+		return fmt.Errorf("parse %q: %w", flagCatchUp, err)
 	}
 
-	go r.Run(ctx.Context)
-
-	if err = s.Bootstrap(ctx.Context); err != nil {
-		return fmt.Errorf("bootstrap: %w", err)
+	// Buttons can only be handled when the interactions are received.
+	channels := func(channelID string) outbox.Discord { return discordClient.Channel(channelID) }
+	if ctx.String(flagInteractions) != "" {
+		channels = func(channelID string) outbox.Discord {
+			return handlers.NewChannelSender(discordClient.Channel(channelID), http.DefaultClient, handlers.DiscordAPIURL, ctx.String(flagBotToken), channelID)
+		}
 	}
 
+	direct := handlers.NewDirectSender(discordClient.User("@me"), channels)
+
+	o := outbox.New(s, channels, ctx.String(flagBotChannelID), direct, clk)
+	go o.Run(ctx.Context)
+
 	tz, err := time.LoadLocation(ctx.String(flagBotTimezone))
 	if err != nil {
 		return fmt.Errorf("load location %q: %w", flagBotTimezone, err)
 	}
 
+	r, err := reminder.New(s, o, clk, policy, tz)
+	if err != nil {
+		return fmt.Errorf("new reminder: %w", err)
+	}
+
+	go r.Run(ctx.Context)
+
 	botUser, err := discordClient.User("@me").Get(ctx.Context)
 	if err != nil {
 		return fmt.Errorf("get bot user: %w", err)
 	}
 
-	b := bot.New(channel, s, r, tz)
-	h := handlers.New(b, *botUser)
+	newChannelBot := func(channelID string, l i18n.Locale) handlers.Bot {
+		return bot.New(discordClient.Channel(channelID), s, r, tz, l, clk)
+	}
+
+	h := handlers.New(newChannelBot, guilds{client: discordClient}, s, *botUser)
 
 	discordClient.OnMessageCreate(h.MessageCreate)
 	discordClient.OnMessageReactionAdd(h.ReactionAdd)
 
+	if addr := ctx.String(flagInteractions); addr != "" {
+		newBot := func(d bot.Discord, l i18n.Locale) handlers.Bot { return bot.New(d, s, r, tz, l, clk) }
+
+		stop, err := serveInteractions(ctx, discordClient, newBot, channel, s, s, addr)
+		if err != nil {
+			return fmt.Errorf("serve interactions: %w", err)
+		}
+		defer stop()
+	}
+
 	if err = discordClient.Connect(ctx.Context); err != nil {
 		return fmt.Errorf("discord client connect: %w", err)
 	}
@@ -91,3 +130,92 @@ func run(ctx *cli.Context) error {
 
 	return nil
 }
+
+func newStore(ctx *cli.Context) (store.Store, func(), error) {
+	switch driver := ctx.String(flagStoreDriver); driver {
+	case storeDriverMongo:
+		client, err := store.NewMongoClient(ctx.Context, ctx.String(flagMongoURI))
+		if err != nil {
+			return nil, nil, fmt.Errorf("new MongoDB client: %w", err)
+		}
+
+		closeClient := func() { _ = client.Disconnect(ctx.Context) }
+
+		m, err := migration.New(client.Database(store.DatabaseName), migration.All())
+		if err != nil {
+			closeClient()
+
+			return nil, nil, fmt.Errorf("new migrator: %w", err)
+		}
+
+		if _, err = m.Up(ctx.Context); err != nil {
+			closeClient()
+
+			return nil, nil, fmt.Errorf("migrate: %w", err)
+		}
+
+		return store.NewMongo(client, store.DatabaseName), closeClient, nil
+	case storeDriverBolt:
+		s, err := store.NewBolt(ctx.String(flagStorePath))
+		if err != nil {
+			return nil, nil, fmt.Errorf("open bolt store: %w", err)
+		}
+
+		return s, func() { _ = s.Close() }, nil
+	case storeDriverMemory:
+		log.Warn().Msg("Using the memory store, reminds will be lost on restart")
+
+		return store.NewMemory(), func() {}, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown store driver %q", driver)
+	}
+}
+
+func loadPets(path string) (store.Pets, error) {
+	if path == "" {
+		return store.DefaultPets()
+	}
+
+	return store.LoadPets(path)
+}
+
+// serveInteractions registers the slash commands, and serves the endpoint receiving them.
+func serveInteractions(ctx *cli.Context, client *harmony.Client, newBot func(d bot.Discord, l i18n.Locale) handlers.Bot, d bot.Discord, p handlers.PetLister, settings handlers.Settings, addr string) (func(), error) {
+	app, err := client.GetApplicationInfo(ctx.Context)
+	if err != nil {
+		return nil, fmt.Errorf("get application info: %w", err)
+	}
+
+	publicKey, err := hex.DecodeString(app.VerifyKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid application public key %q", app.VerifyKey)
+	}
+
+	if err = handlers.RegisterCommands(ctx.Context, http.DefaultClient, handlers.DiscordAPIURL, app.ID, ctx.String(flagBotToken)); err != nil {
+		return nil, fmt.Errorf("register commands: %w", err)
+	}
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handlers.NewInteractions(newBot, d, p, settings, publicKey),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error().Err(err).Msg("Unable to serve interactions")
+		}
+	}()
+
+	return func() { _ = server.Close() }, nil
+}
+
+// guilds gets the guilds through the Discord client.
+type guilds struct {
+	client *harmony.Client
+}
+
+// Guild gets the guild with the given ID.
+func (g guilds) Guild(ctx context.Context, id string) (*discord.Guild, error) {
+	return g.client.Guild(id).Get(ctx)
+}
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/skwair/harmony/discord"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/clock"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/i18n"
 	"github.com/youkoulayley/pet-reminder-bot/pkg/store"
 )
 
@@ -15,15 +17,20 @@ type Bot struct {
 	reminder Reminder
 
 	timezone *time.Location
+	locale   i18n.Locale
+	clock    clock.Clock
 }
 
 // New creates a bot.
-func New(d Discord, s Storer, r Reminder, tz *time.Location) *Bot {
+// Its messages are sent in the given locale, which is the one of the user it replies to.
+func New(d Discord, s Storer, r Reminder, tz *time.Location, l i18n.Locale, c clock.Clock) *Bot {
 	return &Bot{
 		discord:  d,
 		store:    s,
 		reminder: r,
 		timezone: tz,
+		locale:   l,
+		clock:    c,
 	}
 }
 
@@ -36,11 +43,20 @@ type Storer interface {
 	GetRemind(ctx context.Context, id string) (store.Remind, error)
 	RemoveRemind(ctx context.Context, id string) error
 	ListRemindsByID(ctx context.Context, id string) ([]store.Remind, error)
+	CreateRemindEvent(ctx context.Context, event store.RemindEvent) error
+	ListRemindEvents(ctx context.Context, remindID string, skip, limit int) ([]store.RemindEvent, error)
+	CreateRemindMessage(ctx context.Context, msg store.RemindMessage) error
+	GetRemindMessage(ctx context.Context, messageID string) (store.RemindMessage, error)
+	GetUserSettings(ctx context.Context, userID string) (store.UserSettings, error)
+	UpsertUserSettings(ctx context.Context, settings store.UserSettings) error
+	GetGuildSettings(ctx context.Context, guildID string) (store.GuildSettings, error)
+	UpsertGuildSettings(ctx context.Context, settings store.GuildSettings) error
 }
 
 // Reminder is capable of interacting with the reminder.
 type Reminder interface {
-	SetUpdate()
+	Upsert(remind store.Remind)
+	Remove(id store.ID)
 }
 
 // Discord is capable of interacting with Discord.
@@ -4,11 +4,17 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/clock"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/i18n"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/store"
 )
 
 const testLocation = "Europe/Paris"
 
+var testNow = time.Date(2022, 1, 18, 10, 0, 0, 0, time.UTC)
+
 func setupBot(t *testing.T, b Bot) Bot {
 	t.Helper()
 
@@ -16,6 +22,22 @@ func setupBot(t *testing.T, b Bot) Bot {
 	require.NoError(t, err)
 
 	b.timezone = tz
+	b.locale = i18n.French
+	b.clock = clock.NewFake(testNow)
+
+	// Users have no timezone by default, so the times are displayed in the timezone of the bot.
+	if s, ok := b.store.(*storeMock); ok {
+		s.On("GetUserSettings", mock.Anything).Return(store.UserSettings{}, nil).Maybe()
+	}
 
 	return b
 }
+
+func formatTestTime(t *testing.T, tm time.Time) string {
+	t.Helper()
+
+	tz, err := time.LoadLocation(testLocation)
+	require.NoError(t, err)
+
+	return tm.In(tz).Format(time.RFC1123)
+}
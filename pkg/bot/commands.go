@@ -4,19 +4,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/rs/zerolog/log"
+	"github.com/skwair/harmony/discord"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/i18n"
 	"github.com/youkoulayley/pet-reminder-bot/pkg/store"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-const helpMessage = `Commandes disponible:
-  - ` + "`!familiers`" + `
-  - ` + "`!list`" + `
-  - ` + "`!remind <Familier> <Personnage>`" + `
-  - ` + "`!remove <ID>` "
+// MaxHistoryLimit is the maximum number of meals listed by the history command.
+const MaxHistoryLimit = 25
+
+const historyPageSize = 50
+
+// maxSuggestions is the maximum number of pets suggested for an unknown pet name.
+const maxSuggestions = 3
 
 // ListPets handles the familiers command for the bot.
 // Call it with `!familiers`.
@@ -36,10 +41,15 @@ func (b *Bot) ListPets(ctx context.Context) {
 }
 
 // RemindConfig represents remind command config.
+// GuildID and ChannelID are where the command has been sent, the notifications of the remind are sent there.
+// FedAt is when the pet has last been fed, like `2h` ago or at `14:30`, the first cycle starts now when it is empty.
 type RemindConfig struct {
 	AuthorID  string
+	GuildID   string
+	ChannelID string
 	Pet       string
 	Character string
+	FedAt     string
 }
 
 // Validate ensures that all fields are valid.
@@ -60,24 +70,26 @@ func (c RemindConfig) Validate() error {
 }
 
 // Remind handles the remind command for the bot.
-// Call it with `!remind <PetName> <CharacterName>`
+// Call it with `!remind <PetName> <CharacterName> [FedAt]`
 // PetName can be found with the ListPets command.
 func (b *Bot) Remind(ctx context.Context, cfg RemindConfig) {
 	if err := cfg.Validate(); err != nil {
-		b.Help(ctx)
+		b.Usage(ctx, "remind")
 
 		return
 	}
 
-	petDuration, err := b.store.GetPet(ctx, cfg.Pet)
+	fedAt, err := b.parseFedAt(ctx, cfg.AuthorID, cfg.FedAt)
 	if err != nil {
-		if errors.As(err, &store.NotFoundError{}) {
-			message := fmt.Sprintf("%q n'existe pas. `!familiers` pour connaître la liste des familiers gérés.", cfg.Pet)
-			if _, err = b.discord.SendMessage(ctx, message); err != nil {
-				log.Error().Err(err).Msg("Unable to send message")
+		b.Usage(ctx, "remind")
 
-				return
-			}
+		return
+	}
+
+	pet, suggestions, err := b.findPet(ctx, cfg.Pet)
+	if err != nil {
+		if errors.As(err, &store.NotFoundError{}) {
+			b.sendUnknownPet(ctx, cfg.Pet, suggestions)
 
 			return
 		}
@@ -87,14 +99,21 @@ func (b *Bot) Remind(ctx context.Context, cfg RemindConfig) {
 		return
 	}
 
-	id := primitive.NewObjectID()
+	if b.tooOld(ctx, cfg.AuthorID, pet, fedAt) {
+		return
+	}
+
+	id := store.NewID()
 	remind := store.Remind{
 		ID:            id,
 		DiscordUserID: cfg.AuthorID,
-		PetName:       cfg.Pet,
+		GuildID:       cfg.GuildID,
+		ChannelID:     cfg.ChannelID,
+		PetName:       pet.Name,
 		Character:     cfg.Character,
-		NextRemind:    time.Now().Add(petDuration.FoodMinDuration),
-		TimeoutRemind: time.Now().Add(petDuration.FoodMaxDuration),
+		CycleStart:    fedAt,
+		NextRemind:    fedAt.Add(pet.FoodMinDuration),
+		TimeoutRemind: fedAt.Add(pet.FoodMaxDuration),
 	}
 
 	if err = b.store.CreateRemind(ctx, remind); err != nil {
@@ -103,21 +122,70 @@ func (b *Bot) Remind(ctx context.Context, cfg RemindConfig) {
 		return
 	}
 
-	b.reminder.SetUpdate()
+	b.recordEvent(ctx, remind, store.EventCreated, cfg.AuthorID)
 
-	message := fmt.Sprintf(
-		"<@%s> Rappel activé pour familier %q sur %s\nProchain rappel: %s\nID: %s\n",
+	b.reminder.Upsert(remind)
+
+	message := i18n.T(
+		b.locale,
+		"remind.created",
 		cfg.AuthorID,
-		cfg.Pet,
+		pet.Name,
 		cfg.Character,
-		remind.NextRemind.In(b.timezone).Format(time.RFC1123),
-		id.Hex(),
+		remind.NextRemind.In(b.location(ctx, cfg.AuthorID)).Format(time.RFC1123),
+		id,
 	)
-	if _, err = b.discord.SendMessage(ctx, message); err != nil {
+
+	sent, err := b.discord.SendMessage(ctx, message)
+	if err != nil {
 		log.Error().Err(err).Msg("Unable to send message")
 
 		return
 	}
+
+	b.recordMessage(ctx, sent, id)
+}
+
+// findPet returns the pet with the given name, looked up ignoring the case, the accents and the separators.
+// The closest pets are returned along with the not found error when no pet has this name.
+func (b *Bot) findPet(ctx context.Context, name string) (store.Pet, store.Pets, error) {
+	pet, err := b.store.GetPet(ctx, name)
+	if !errors.As(err, &store.NotFoundError{}) {
+		return pet, nil, err
+	}
+
+	pets, err := b.store.ListPets(ctx)
+	if err != nil {
+		return store.Pet{}, nil, fmt.Errorf("list pets: %w", err)
+	}
+
+	if pet, ok := pets.Find(name); ok {
+		return pet, nil, nil
+	}
+
+	return store.Pet{}, pets.Suggest(name, maxSuggestions), store.NotFoundError{Err: fmt.Errorf("pet %q not found", name)}
+}
+
+// sendUnknownPet tells the user the pet does not exist, and suggests the closest ones.
+func (b *Bot) sendUnknownPet(ctx context.Context, name string, suggestions store.Pets) {
+	message := i18n.T(b.locale, "pet.unknown", name)
+	if len(suggestions) > 0 {
+		message = i18n.T(b.locale, "pet.suggest", name, formatPetNames(suggestions))
+	}
+
+	if _, err := b.discord.SendMessage(ctx, message); err != nil {
+		log.Error().Err(err).Msg("Unable to send message")
+	}
+}
+
+// formatPetNames formats the names of the pets as a list of commands arguments.
+func formatPetNames(pets store.Pets) string {
+	names := make([]string, 0, len(pets))
+	for _, pet := range pets {
+		names = append(names, "`"+pet.Name+"`")
+	}
+
+	return strings.Join(names, ", ")
 }
 
 // RemoveRemindConfig represents remove remind command config.
@@ -132,8 +200,8 @@ func (c RemoveRemindConfig) Validate() error {
 		return errors.New("author id cannot be empty")
 	}
 
-	if _, err := primitive.ObjectIDFromHex(c.ID); err != nil {
-		return fmt.Errorf("object id from hex: %w", err)
+	if _, err := store.ParseID(c.ID); err != nil {
+		return fmt.Errorf("parse id: %w", err)
 	}
 
 	return nil
@@ -143,7 +211,7 @@ func (c RemoveRemindConfig) Validate() error {
 // Call it with `!remove <RemindID>`.
 func (b *Bot) RemoveRemind(ctx context.Context, cfg RemoveRemindConfig) {
 	if err := cfg.Validate(); err != nil {
-		b.Help(ctx)
+		b.Usage(ctx, "remove")
 
 		return
 	}
@@ -158,7 +226,7 @@ func (b *Bot) RemoveRemind(ctx context.Context, cfg RemoveRemindConfig) {
 	}
 
 	if remind.DiscordUserID != cfg.AuthorID {
-		message := fmt.Sprintf("<@%s> Vous ne pouvez pas supprimer un rappel qui ne vous appartient pas.", cfg.AuthorID)
+		message := i18n.T(b.locale, "remove.notOwner", cfg.AuthorID)
 		if _, err = b.discord.SendMessage(ctx, message); err != nil {
 			logger.Error().Err(err).Msg("Unable to send message")
 
@@ -174,7 +242,7 @@ func (b *Bot) RemoveRemind(ctx context.Context, cfg RemoveRemindConfig) {
 		if errors.As(err, &store.NotFoundError{}) {
 			logger.Debug().Err(err).Msg("Unable to find reminder")
 
-			message := fmt.Sprintf("<@%s> Pas de rappel avec l'id: %q", cfg.AuthorID, cfg.ID)
+			message := i18n.T(b.locale, "remind.notFound", cfg.AuthorID, cfg.ID)
 			if _, err = b.discord.SendMessage(ctx, message); err != nil {
 				logger.Error().Err(err).Msg("Unable to send message")
 
@@ -189,9 +257,11 @@ func (b *Bot) RemoveRemind(ctx context.Context, cfg RemoveRemindConfig) {
 		return
 	}
 
-	b.reminder.SetUpdate()
+	b.recordEvent(ctx, remind, store.EventRemoved, cfg.AuthorID)
+
+	b.reminder.Remove(remind.ID)
 
-	message := fmt.Sprintf("<@%s> Rappel %q supprimé", cfg.AuthorID, cfg.ID)
+	message := i18n.T(b.locale, "remove.done", cfg.AuthorID, cfg.ID)
 	if _, err = b.discord.SendMessage(ctx, message); err != nil {
 		logger.Error().Err(err).Msg("Unable to send message")
 
@@ -199,9 +269,126 @@ func (b *Bot) RemoveRemind(ctx context.Context, cfg RemoveRemindConfig) {
 	}
 }
 
+// EditConfig represents edit command config.
+// Pet and Character are left unchanged when they are empty.
+type EditConfig struct {
+	AuthorID  string
+	ID        string
+	Pet       string
+	Character string
+}
+
+// Validate ensures that all fields are valid.
+func (c EditConfig) Validate() error {
+	if c.AuthorID == "" {
+		return errors.New("author id cannot be empty")
+	}
+
+	if _, err := store.ParseID(c.ID); err != nil {
+		return fmt.Errorf("parse id: %w", err)
+	}
+
+	if c.Pet == "" && c.Character == "" {
+		return errors.New("pet or character must be given")
+	}
+
+	return nil
+}
+
+// Edit handles the edit command for the bot.
+// Call it with `!edit <RemindID> pet=<PetName> character=<CharacterName>`, each change being optional.
+// Changing the pet keeps the start of the cycle, its feeding window is computed again with the durations of the new pet.
+func (b *Bot) Edit(ctx context.Context, cfg EditConfig) {
+	if err := cfg.Validate(); err != nil {
+		b.Usage(ctx, "edit")
+
+		return
+	}
+
+	logger := log.With().Str("id", cfg.ID).Logger()
+
+	reminds, ok := b.ownedReminds(ctx, cfg.AuthorID, cfg.ID, "edit.notOwner")
+	if !ok {
+		return
+	}
+
+	remind := reminds[0]
+
+	if remind.Paused {
+		b.sendPaused(ctx, cfg.AuthorID, remind)
+
+		return
+	}
+
+	if cfg.Pet != "" {
+		pet, suggestions, err := b.findPet(ctx, cfg.Pet)
+		if err != nil {
+			if errors.As(err, &store.NotFoundError{}) {
+				b.sendUnknownPet(ctx, cfg.Pet, suggestions)
+
+				return
+			}
+
+			logger.Error().Err(err).Msg("Unable to get pet")
+
+			return
+		}
+
+		start, err := b.cycleStart(ctx, remind)
+		if err != nil {
+			logger.Error().Err(err).Msg("Unable to get cycle start")
+
+			return
+		}
+
+		if b.tooOld(ctx, cfg.AuthorID, pet, start) {
+			return
+		}
+
+		remind.PetName = pet.Name
+		remind.CycleStart = start
+		remind.NextRemind = start.Add(pet.FoodMinDuration)
+		remind.TimeoutRemind = start.Add(pet.FoodMaxDuration)
+
+		if remind.NextRemind.After(b.clock.Now()) {
+			remind.ReminderSent = false
+			remind.SnoozedUntil = time.Time{}
+			remind.Warnings = nil
+		}
+	}
+
+	if cfg.Character != "" {
+		remind.Character = cfg.Character
+	}
+
+	if err := b.store.UpdateRemind(ctx, remind); err != nil {
+		logger.Error().Err(err).Msg("Unable to update remind")
+
+		return
+	}
+
+	b.recordEvent(ctx, remind, store.EventEdited, cfg.AuthorID)
+
+	b.reminder.Upsert(remind)
+
+	message := i18n.T(b.locale, "edit.done", cfg.AuthorID, remind.ID, remind.PetName, remind.Character, remind.NextRemind.In(b.location(ctx, cfg.AuthorID)).Format(time.RFC1123))
+	if _, err := b.discord.SendMessage(ctx, message); err != nil {
+		logger.Error().Err(err).Msg("Unable to send message")
+	}
+}
+
 // Help handles all other commands.
 func (b *Bot) Help(ctx context.Context) {
-	if _, err := b.discord.SendMessage(ctx, helpMessage); err != nil {
+	if _, err := b.discord.SendMessage(ctx, i18n.T(b.locale, "help")); err != nil {
+		log.Error().Err(err).Msg("Unable to send message")
+
+		return
+	}
+}
+
+// Usage sends how to use the given command, when it has been called with invalid arguments.
+func (b *Bot) Usage(ctx context.Context, command string) {
+	if _, err := b.discord.SendMessage(ctx, i18n.T(b.locale, "usage."+command)); err != nil {
 		log.Error().Err(err).Msg("Unable to send message")
 
 		return
@@ -209,9 +396,13 @@ func (b *Bot) Help(ctx context.Context) {
 }
 
 // NewCycleConfig represents new cycle config.
+// The remind is identified by its ID, or by the message the user reacted to.
+// FedAt is when the pet has been fed, like `2h` ago or at `14:30`, the cycle starts now when it is empty.
 type NewCycleConfig struct {
 	AuthorID  string
 	MessageID string
+	ID        string
+	FedAt     string
 }
 
 // Validate ensures that all fields are valid.
@@ -220,6 +411,14 @@ func (c *NewCycleConfig) Validate() error {
 		return errors.New("author id cannot be empty")
 	}
 
+	if c.ID != "" {
+		if _, err := store.ParseID(c.ID); err != nil {
+			return fmt.Errorf("parse id: %w", err)
+		}
+
+		return nil
+	}
+
 	if c.MessageID == "" {
 		return errors.New("message id cannot be empty")
 	}
@@ -227,59 +426,87 @@ func (c *NewCycleConfig) Validate() error {
 	return nil
 }
 
-// NewCycle starts a new cycle when the user add a reaction to a message.
+// NewCycle starts a new cycle when the user add a reaction to a message, clicks its fed button,
+// or calls `!fed <RemindID> [FedAt]`.
+// Reactions are silent: reactions from other users are ignored, and the cycle starts without confirmation.
 func (b *Bot) NewCycle(ctx context.Context, cfg NewCycleConfig) {
 	if err := cfg.Validate(); err != nil {
-		b.Help(ctx)
+		b.Usage(ctx, "fed")
 
 		return
 	}
 
-	message, err := b.discord.Message(ctx, cfg.MessageID)
+	reaction := cfg.ID == ""
+
+	id := store.ID(cfg.ID)
+	if id == "" {
+		var err error
+		if id, err = b.remindIDFromMessage(ctx, cfg.MessageID); err != nil {
+			log.Debug().Err(err).Str("messageId", cfg.MessageID).Msg("Unable to find the remind of the message")
+
+			return
+		}
+	}
+
+	remind, err := b.store.GetRemind(ctx, id.String())
 	if err != nil {
-		log.Error().Err(err).Msg("Unable to find message")
+		log.Error().Err(err).Msg("Unable to get remind")
 
 		return
 	}
 
-	parts := strings.Split(message.Content, "ID:")
-	if len(parts) != 2 {
-		log.Debug().Msg("ID invalid")
+	if remind.DiscordUserID != cfg.AuthorID {
+		log.Debug().Msg("Unable to start a new cycle: wrong discordUserID")
+
+		if reaction {
+			return
+		}
+
+		message := i18n.T(b.locale, "fed.notOwner", cfg.AuthorID)
+		if _, err = b.discord.SendMessage(ctx, message); err != nil {
+			log.Error().Err(err).Msg("Unable to send message")
+		}
 
 		return
 	}
 
-	id := strings.TrimSpace(parts[1])
-	if _, err = primitive.ObjectIDFromHex(id); err != nil {
-		log.Debug().Msg("ID invalid")
+	if remind.Paused {
+		log.Debug().Msg("Unable to start a new cycle: remind paused")
+
+		if !reaction {
+			b.sendPaused(ctx, cfg.AuthorID, remind)
+		}
 
 		return
 	}
 
-	remind, err := b.store.GetRemind(ctx, id)
+	pet, err := b.store.GetPet(ctx, remind.PetName)
 	if err != nil {
-		log.Error().Err(err).Msg("Unable to get remind")
+		log.Error().Err(err).Msg("Invalid pet")
 
 		return
 	}
 
-	if remind.DiscordUserID != cfg.AuthorID {
-		log.Error().Msg("Invalid user")
+	fedAt, err := b.parseFedAt(ctx, cfg.AuthorID, cfg.FedAt)
+	if err != nil {
+		b.Usage(ctx, "fed")
 
 		return
 	}
 
-	pet, err := b.store.GetPet(ctx, remind.PetName)
-	if err != nil {
-		log.Error().Err(err).Msg("Invalid pet")
-
+	if b.tooOld(ctx, cfg.AuthorID, pet, fedAt) {
 		return
 	}
 
+	fed := remind
+
 	remind.MissedReminder = 0
 	remind.ReminderSent = false
-	remind.NextRemind = time.Now().Add(pet.FoodMinDuration)
-	remind.TimeoutRemind = time.Now().Add(pet.FoodMaxDuration)
+	remind.CycleStart = fedAt
+	remind.NextRemind = fedAt.Add(pet.FoodMinDuration)
+	remind.TimeoutRemind = fedAt.Add(pet.FoodMaxDuration)
+	remind.SnoozedUntil = time.Time{}
+	remind.Warnings = nil
 
 	if err = b.store.UpdateRemind(ctx, remind); err != nil {
 		log.Error().Err(err).Msg("Unable to update remind")
@@ -287,40 +514,1125 @@ func (b *Bot) NewCycle(ctx context.Context, cfg NewCycleConfig) {
 		return
 	}
 
-	b.reminder.SetUpdate()
+	b.recordEventAt(ctx, fed, store.EventFed, cfg.AuthorID, fedAt)
+
+	b.reminder.Upsert(remind)
+
+	if reaction {
+		return
+	}
+
+	message := i18n.T(b.locale, "fed.done", cfg.AuthorID, remind.PetName, remind.Character, remind.NextRemind.In(b.location(ctx, cfg.AuthorID)).Format(time.RFC1123))
+	if _, err = b.discord.SendMessage(ctx, message); err != nil {
+		log.Error().Err(err).Msg("Unable to send message")
+	}
 }
 
-// ListReminds lists all reminds set for the user identified by the given id.
-func (b *Bot) ListReminds(ctx context.Context, id string) {
-	logger := log.With().Str("id", id).Logger()
+// SnoozeConfig represents snooze command config.
+type SnoozeConfig struct {
+	AuthorID string
+	ID       string
+	Duration time.Duration
+}
 
-	reminds, err := b.store.ListRemindsByID(ctx, id)
+// Validate ensures that all fields are valid.
+func (c SnoozeConfig) Validate() error {
+	if c.AuthorID == "" {
+		return errors.New("author id cannot be empty")
+	}
+
+	if _, err := store.ParseID(c.ID); err != nil {
+		return fmt.Errorf("parse id: %w", err)
+	}
+
+	if c.Duration <= 0 {
+		return errors.New("duration must be positive")
+	}
+
+	return nil
+}
+
+// Snooze sends the reminder again after the given duration.
+// The feeding window is left untouched, so the reminder cannot be snoozed until the end of the window:
+// it would be sent along with the missed meal. The warnings falling during the snooze are still sent.
+func (b *Bot) Snooze(ctx context.Context, cfg SnoozeConfig) {
+	if err := cfg.Validate(); err != nil {
+		b.Usage(ctx, "snooze")
+
+		return
+	}
+
+	logger := log.With().Str("id", cfg.ID).Logger()
+
+	remind, err := b.store.GetRemind(ctx, cfg.ID)
 	if err != nil {
-		logger.Error().Err(err).Msg("Unable to list reminds")
+		logger.Error().Err(err).Msg("Unable to find remind")
 
 		return
 	}
 
-	if len(reminds) == 0 {
-		if _, err = b.discord.SendMessage(ctx, fmt.Sprintf("<@%s> Aucun rappel disponible", id)); err != nil {
+	if remind.DiscordUserID != cfg.AuthorID {
+		logger.Debug().Msg("Unable to snooze reminder: wrong discordUserID")
+
+		message := i18n.T(b.locale, "snooze.notOwner", cfg.AuthorID)
+		if _, err = b.discord.SendMessage(ctx, message); err != nil {
 			logger.Error().Err(err).Msg("Unable to send message")
+		}
 
-			return
+		return
+	}
+
+	if remind.Paused {
+		b.sendPaused(ctx, cfg.AuthorID, remind)
+
+		return
+	}
+
+	until := b.clock.Now().Add(cfg.Duration)
+
+	if !until.Before(remind.TimeoutRemind) {
+		message := i18n.T(b.locale, "snooze.tooLate", cfg.AuthorID, remind.PetName, remind.Character, remind.TimeoutRemind.In(b.location(ctx, cfg.AuthorID)).Format(time.RFC1123))
+		if _, err = b.discord.SendMessage(ctx, message); err != nil {
+			logger.Error().Err(err).Msg("Unable to send message")
 		}
 
 		return
 	}
 
-	message := []string{fmt.Sprintf("<@%s> Liste de vos rappels:", id)}
+	if until.Before(remind.NextRemind) {
+		until = remind.NextRemind
+	}
+
+	remind.ReminderSent = false
+	remind.SnoozedUntil = until
 
-	for _, remind := range reminds {
-		r := fmt.Sprintf("  - %s - %s sur %s - Prochain rappel: %s", remind.ID.Hex(), remind.PetName, remind.Character, remind.NextRemind.In(b.timezone).Format(time.RFC1123))
-		message = append(message, r)
+	if err = b.store.UpdateRemind(ctx, remind); err != nil {
+		logger.Error().Err(err).Msg("Unable to update remind")
+
+		return
 	}
 
-	if _, err = b.discord.SendMessage(ctx, strings.Join(message, "\n")); err != nil {
+	b.reminder.Upsert(remind)
+
+	message := i18n.T(b.locale, "snooze.done", cfg.AuthorID, remind.PetName, remind.Character, until.In(b.location(ctx, cfg.AuthorID)).Format(time.RFC1123))
+	if _, err = b.discord.SendMessage(ctx, message); err != nil {
 		logger.Error().Err(err).Msg("Unable to send message")
+	}
+}
+
+// AllReminds is the ID given to pause or resume all the reminds of the author.
+const AllReminds = "all"
+
+// PauseConfig represents pause command config.
+// ID is the ID of the remind, or AllReminds. Reminds paused until a zero time are resumed by hand.
+type PauseConfig struct {
+	AuthorID string
+	ID       string
+	Until    time.Time
+}
+
+// Validate ensures that all fields are valid.
+func (c PauseConfig) Validate() error {
+	if c.AuthorID == "" {
+		return errors.New("author id cannot be empty")
+	}
+
+	if c.ID == AllReminds {
+		return nil
+	}
+
+	if _, err := store.ParseID(c.ID); err != nil {
+		return fmt.Errorf("parse id: %w", err)
+	}
+
+	return nil
+}
+
+// Pause handles the pause command for the bot.
+// Call it with `!pause <RemindID|all>`.
+// Paused reminds are not notified and do not miss any meal until they are resumed.
+func (b *Bot) Pause(ctx context.Context, cfg PauseConfig) {
+	if err := cfg.Validate(); err != nil {
+		b.Usage(ctx, "pause")
+
+		return
+	}
+
+	b.pause(ctx, cfg)
+}
+
+func (b *Bot) pause(ctx context.Context, cfg PauseConfig) {
+	logger := log.With().Str("user", cfg.AuthorID).Str("id", cfg.ID).Logger()
+
+	reminds, ok := b.ownedReminds(ctx, cfg.AuthorID, cfg.ID, "pause.notOwner")
+	if !ok {
+		return
+	}
+
+	var paused []store.Remind
+
+	for _, remind := range reminds {
+		remind.Paused = true
+		remind.PausedUntil = cfg.Until
+		remind.SnoozedUntil = time.Time{}
+
+		if err := b.store.UpdateRemind(ctx, remind); err != nil {
+			logger.Error().Err(err).Str("remind", remind.ID.String()).Msg("Unable to update remind")
 
+			continue
+		}
+
+		b.recordEvent(ctx, remind, store.EventPaused, cfg.AuthorID)
+
+		b.reminder.Upsert(remind)
+
+		paused = append(paused, remind)
+	}
+
+	var message string
+
+	switch {
+	case !cfg.Until.IsZero():
+		message = i18n.T(b.locale, "vacation.done", cfg.AuthorID, len(paused), cfg.Until.In(b.location(ctx, cfg.AuthorID)).Format(time.RFC1123))
+	case cfg.ID == AllReminds:
+		message = i18n.T(b.locale, "pause.all", cfg.AuthorID, len(paused))
+	case len(paused) == 1:
+		message = i18n.T(b.locale, "pause.done", cfg.AuthorID, paused[0].PetName, paused[0].Character, paused[0].ID)
+	default:
 		return
 	}
+
+	if _, err := b.discord.SendMessage(ctx, message); err != nil {
+		logger.Error().Err(err).Msg("Unable to send message")
+	}
+}
+
+// ResumeConfig represents resume command config.
+// ID is the ID of the remind, or AllReminds.
+type ResumeConfig struct {
+	AuthorID string
+	ID       string
+}
+
+// Validate ensures that all fields are valid.
+func (c ResumeConfig) Validate() error {
+	return PauseConfig{AuthorID: c.AuthorID, ID: c.ID}.Validate()
+}
+
+// Resume handles the resume command for the bot.
+// Call it with `!resume <RemindID|all>`.
+// Resumed reminds start a new cycle, as if their pet had just been fed.
+func (b *Bot) Resume(ctx context.Context, cfg ResumeConfig) {
+	if err := cfg.Validate(); err != nil {
+		b.Usage(ctx, "resume")
+
+		return
+	}
+
+	logger := log.With().Str("user", cfg.AuthorID).Str("id", cfg.ID).Logger()
+
+	reminds, ok := b.ownedReminds(ctx, cfg.AuthorID, cfg.ID, "resume.notOwner")
+	if !ok {
+		return
+	}
+
+	now := b.clock.Now()
+
+	var resumed []store.Remind
+
+	for _, remind := range reminds {
+		if !remind.Paused {
+			continue
+		}
+
+		pet, err := b.store.GetPet(ctx, remind.PetName)
+		if err != nil {
+			logger.Error().Err(err).Str("remind", remind.ID.String()).Msg("Invalid pet")
+
+			continue
+		}
+
+		remind.Paused = false
+		remind.PausedUntil = time.Time{}
+		remind.MissedReminder = 0
+		remind.ReminderSent = false
+		remind.CycleStart = now
+		remind.NextRemind = now.Add(pet.FoodMinDuration)
+		remind.TimeoutRemind = now.Add(pet.FoodMaxDuration)
+		remind.SnoozedUntil = time.Time{}
+		remind.Warnings = nil
+
+		if err = b.store.UpdateRemind(ctx, remind); err != nil {
+			logger.Error().Err(err).Str("remind", remind.ID.String()).Msg("Unable to update remind")
+
+			continue
+		}
+
+		b.recordEvent(ctx, remind, store.EventResumed, cfg.AuthorID)
+
+		b.reminder.Upsert(remind)
+
+		resumed = append(resumed, remind)
+	}
+
+	var message string
+
+	switch {
+	case cfg.ID == AllReminds:
+		message = i18n.T(b.locale, "resume.all", cfg.AuthorID, len(resumed))
+	case len(resumed) == 1:
+		message = i18n.T(b.locale, "resume.done", cfg.AuthorID, resumed[0].PetName, resumed[0].Character, resumed[0].NextRemind.In(b.location(ctx, cfg.AuthorID)).Format(time.RFC1123))
+	case len(reminds) == 1 && !reminds[0].Paused:
+		message = i18n.T(b.locale, "resume.notPaused", cfg.AuthorID, reminds[0].PetName, reminds[0].Character)
+	default:
+		return
+	}
+
+	if _, err := b.discord.SendMessage(ctx, message); err != nil {
+		logger.Error().Err(err).Msg("Unable to send message")
+	}
+}
+
+// VacationConfig represents vacation command config.
+// Until is a date, like 2022-02-01, or a date and a time, like "2022-02-01 18:00", in the timezone of the author.
+type VacationConfig struct {
+	AuthorID string
+	Until    string
+}
+
+// Validate ensures that all fields are valid.
+func (c VacationConfig) Validate() error {
+	if c.AuthorID == "" {
+		return errors.New("author id cannot be empty")
+	}
+
+	if c.Until == "" {
+		return errors.New("until cannot be empty")
+	}
+
+	return nil
+}
+
+// vacationLayouts are the layouts the end of a vacation can be given with.
+var vacationLayouts = []string{"2006-01-02", "2006-01-02 15:04"}
+
+// Vacation handles the vacation command for the bot.
+// Call it with `!vacation until <Date>`.
+// All the reminds of the author are paused, and resumed at the given date.
+func (b *Bot) Vacation(ctx context.Context, cfg VacationConfig) {
+	if err := cfg.Validate(); err != nil {
+		b.Usage(ctx, "vacation")
+
+		return
+	}
+
+	loc := b.location(ctx, cfg.AuthorID)
+
+	var (
+		until time.Time
+		err   error
+	)
+
+	for _, layout := range vacationLayouts {
+		if until, err = time.ParseInLocation(layout, cfg.Until, loc); err == nil {
+			break
+		}
+	}
+
+	if err != nil || !until.After(b.clock.Now()) {
+		b.Usage(ctx, "vacation")
+
+		return
+	}
+
+	b.pause(ctx, PauseConfig{AuthorID: cfg.AuthorID, ID: AllReminds, Until: until})
+}
+
+// ownedReminds returns the remind with the given ID, or all the reminds of the author for AllReminds.
+// It sends the not owner message with the given key when the remind belongs to someone else, and returns false.
+func (b *Bot) ownedReminds(ctx context.Context, authorID, id, notOwnerKey string) ([]store.Remind, bool) {
+	logger := log.With().Str("user", authorID).Str("id", id).Logger()
+
+	if id == AllReminds {
+		reminds, err := b.store.ListRemindsByID(ctx, authorID)
+		if err != nil {
+			logger.Error().Err(err).Msg("Unable to list reminds")
+
+			return nil, false
+		}
+
+		return reminds, true
+	}
+
+	remind, err := b.store.GetRemind(ctx, id)
+	if err != nil {
+		if errors.As(err, &store.NotFoundError{}) {
+			if _, err = b.discord.SendMessage(ctx, i18n.T(b.locale, "remind.notFound", authorID, id)); err != nil {
+				logger.Error().Err(err).Msg("Unable to send message")
+			}
+
+			return nil, false
+		}
+
+		logger.Error().Err(err).Msg("Unable to find remind")
+
+		return nil, false
+	}
+
+	if remind.DiscordUserID != authorID {
+		logger.Debug().Msg("Unable to update reminder: wrong discordUserID")
+
+		if _, err = b.discord.SendMessage(ctx, i18n.T(b.locale, notOwnerKey, authorID)); err != nil {
+			logger.Error().Err(err).Msg("Unable to send message")
+		}
+
+		return nil, false
+	}
+
+	return []store.Remind{remind}, true
+}
+
+// tooOld reports whether the pet would have missed its meal since it has been fed, and tells the user so.
+func (b *Bot) tooOld(ctx context.Context, userID string, pet store.Pet, fedAt time.Time) bool {
+	if b.clock.Now().Sub(fedAt) < pet.FoodMaxDuration {
+		return false
+	}
+
+	message := i18n.T(b.locale, "fed.tooOld", userID, pet.Name, fedAt.In(b.location(ctx, userID)).Format(time.RFC1123))
+	if _, err := b.discord.SendMessage(ctx, message); err != nil {
+		log.Error().Err(err).Msg("Unable to send message")
+	}
+
+	return true
+}
+
+// sendPaused tells the user the remind has to be resumed first, paused reminds being left as they are.
+func (b *Bot) sendPaused(ctx context.Context, userID string, remind store.Remind) {
+	message := i18n.T(b.locale, "remind.paused", userID, remind.PetName, remind.Character, remind.ID)
+	if _, err := b.discord.SendMessage(ctx, message); err != nil {
+		log.Error().Err(err).Msg("Unable to send message")
+	}
+}
+
+// cycleStart returns when the current cycle of the remind started.
+// Reminds stored before it was recorded fall back to their last feed, resume or missed meal,
+// and then to their feeding window with the current durations of their pet.
+func (b *Bot) cycleStart(ctx context.Context, remind store.Remind) (time.Time, error) {
+	if !remind.CycleStart.IsZero() {
+		return remind.CycleStart, nil
+	}
+
+	for skip := 0; ; skip += historyPageSize {
+		events, err := b.store.ListRemindEvents(ctx, remind.ID.String(), skip, historyPageSize)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("list remind events: %w", err)
+		}
+
+		for _, event := range events {
+			switch event.Type {
+			case store.EventFed, store.EventMissed, store.EventResumed:
+				return event.CreatedAt, nil
+			}
+		}
+
+		if len(events) < historyPageSize {
+			break
+		}
+	}
+
+	pet, err := b.store.GetPet(ctx, remind.PetName)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("get pet: %w", err)
+	}
+
+	return remind.NextRemind.Add(-pet.FoodMinDuration), nil
+}
+
+// remindIDFromMessage returns the ID of the remind the message is about.
+// Messages sent before their ID was stored are resolved from the "ID:" line of their content.
+func (b *Bot) remindIDFromMessage(ctx context.Context, messageID string) (store.ID, error) {
+	msg, err := b.store.GetRemindMessage(ctx, messageID)
+	if err == nil {
+		return msg.RemindID, nil
+	}
+
+	if !errors.As(err, &store.NotFoundError{}) {
+		return "", fmt.Errorf("get remind message: %w", err)
+	}
+
+	message, err := b.discord.Message(ctx, messageID)
+	if err != nil {
+		return "", fmt.Errorf("get message: %w", err)
+	}
+
+	parts := strings.Split(message.Content, "ID:")
+	if len(parts) != 2 {
+		return "", errors.New("no remind id in message")
+	}
+
+	return store.ParseID(strings.TrimSpace(parts[1]))
+}
+
+// ListReminds lists all reminds set for the user identified by the given id.
+func (b *Bot) ListReminds(ctx context.Context, id string) {
+	logger := log.With().Str("id", id).Logger()
+
+	reminds, err := b.store.ListRemindsByID(ctx, id)
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to list reminds")
+
+		return
+	}
+
+	if len(reminds) == 0 {
+		if _, err = b.discord.SendMessage(ctx, i18n.T(b.locale, "list.empty", id)); err != nil {
+			logger.Error().Err(err).Msg("Unable to send message")
+
+			return
+		}
+
+		return
+	}
+
+	message := []string{i18n.T(b.locale, "list.header", id)}
+	loc := b.location(ctx, id)
+
+	for _, remind := range reminds {
+		var r string
+
+		switch {
+		case remind.Paused && remind.PausedUntil.IsZero():
+			r = i18n.T(b.locale, "list.paused", remind.ID, remind.PetName, remind.Character)
+		case remind.Paused:
+			r = i18n.T(b.locale, "list.pausedUntil", remind.ID, remind.PetName, remind.Character, remind.PausedUntil.In(loc).Format(time.RFC1123))
+		default:
+			r = i18n.T(b.locale, "list.line", remind.ID, remind.PetName, remind.Character, remind.NextRemind.In(loc).Format(time.RFC1123))
+		}
+
+		message = append(message, r)
+	}
+
+	if _, err = b.discord.SendMessage(ctx, strings.Join(message, "\n")); err != nil {
+		logger.Error().Err(err).Msg("Unable to send message")
+
+		return
+	}
+}
+
+// HistoryConfig represents history command config.
+type HistoryConfig struct {
+	AuthorID string
+	ID       string
+	Limit    int
+}
+
+// Validate ensures that all fields are valid.
+func (c HistoryConfig) Validate() error {
+	if c.AuthorID == "" {
+		return errors.New("author id cannot be empty")
+	}
+
+	if _, err := store.ParseID(c.ID); err != nil {
+		return fmt.Errorf("parse id: %w", err)
+	}
+
+	if c.Limit < 1 || c.Limit > MaxHistoryLimit {
+		return fmt.Errorf("limit must be between 1 and %d", MaxHistoryLimit)
+	}
+
+	return nil
+}
+
+// History handles the history command for the bot.
+// Call it with `!history <RemindID> [Limit]`.
+// It lists the last feeds and missed meals of the remind, even if it has been removed.
+func (b *Bot) History(ctx context.Context, cfg HistoryConfig) {
+	if err := cfg.Validate(); err != nil {
+		b.Usage(ctx, "history")
+
+		return
+	}
+
+	logger := log.With().Str("id", cfg.ID).Logger()
+
+	owner, meals, err := b.listMeals(ctx, cfg.ID, cfg.Limit)
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to list remind events")
+
+		return
+	}
+
+	var message string
+
+	switch {
+	case owner == "":
+		message = i18n.T(b.locale, "history.none", cfg.AuthorID, cfg.ID)
+	case owner != cfg.AuthorID:
+		message = i18n.T(b.locale, "history.notOwner", cfg.AuthorID)
+	case len(meals) == 0:
+		message = i18n.T(b.locale, "history.empty", cfg.AuthorID, cfg.ID)
+	default:
+		lines := []string{i18n.T(b.locale, "history.header", cfg.AuthorID, meals[0].PetName, meals[0].Character)}
+		loc := b.location(ctx, cfg.AuthorID)
+
+		for _, meal := range meals {
+			lines = append(lines, i18n.T(b.locale, "history.line", meal.CreatedAt.In(loc).Format(time.RFC1123), describeMeal(b.locale, meal)))
+		}
+
+		message = strings.Join(lines, "\n")
+	}
+
+	if _, err = b.discord.SendMessage(ctx, message); err != nil {
+		logger.Error().Err(err).Msg("Unable to send message")
+
+		return
+	}
+}
+
+// NotifyConfig represents notify command config.
+type NotifyConfig struct {
+	AuthorID string
+	Mode     string
+}
+
+// Validate ensures that all fields are valid.
+func (c NotifyConfig) Validate() error {
+	if c.AuthorID == "" {
+		return errors.New("author id cannot be empty")
+	}
+
+	if _, err := store.ParseNotifyMode(c.Mode); err != nil {
+		return fmt.Errorf("parse mode: %w", err)
+	}
+
+	return nil
+}
+
+// SetNotify handles the notify command for the bot.
+// Call it with `!notify <dm|channel>`.
+// It defines where the notifications of the author are delivered.
+func (b *Bot) SetNotify(ctx context.Context, cfg NotifyConfig) {
+	if err := cfg.Validate(); err != nil {
+		b.Usage(ctx, "notify")
+
+		return
+	}
+
+	logger := log.With().Str("user", cfg.AuthorID).Logger()
+
+	settings, err := b.store.GetUserSettings(ctx, cfg.AuthorID)
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to get user settings")
+
+		return
+	}
+
+	settings.Notify, _ = store.ParseNotifyMode(cfg.Mode)
+
+	if err = b.store.UpsertUserSettings(ctx, settings); err != nil {
+		logger.Error().Err(err).Msg("Unable to update user settings")
+
+		return
+	}
+
+	message := i18n.T(b.locale, "notify.channel", cfg.AuthorID)
+	if settings.Notify == store.NotifyDM {
+		message = i18n.T(b.locale, "notify.dm", cfg.AuthorID)
+	}
+
+	if _, err = b.discord.SendMessage(ctx, message); err != nil {
+		logger.Error().Err(err).Msg("Unable to send message")
+
+		return
+	}
+}
+
+// TimezoneConfig represents timezone command config.
+// Timezone is an IANA timezone name, like Europe/Paris.
+type TimezoneConfig struct {
+	AuthorID string
+	Timezone string
+}
+
+// Validate ensures that all fields are valid.
+func (c TimezoneConfig) Validate() error {
+	if c.AuthorID == "" {
+		return errors.New("author id cannot be empty")
+	}
+
+	if c.Timezone == "" {
+		return errors.New("timezone cannot be empty")
+	}
+
+	return nil
+}
+
+// SetTimezone handles the timezone command for the bot.
+// Call it with `!timezone <Timezone>`.
+// The times sent to the author are then displayed in this timezone.
+func (b *Bot) SetTimezone(ctx context.Context, cfg TimezoneConfig) {
+	if err := cfg.Validate(); err != nil {
+		b.Usage(ctx, "timezone")
+
+		return
+	}
+
+	logger := log.With().Str("user", cfg.AuthorID).Logger()
+
+	// Local would be resolved to the timezone of the bot, not the one of the user.
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil || cfg.Timezone == "Local" {
+		message := i18n.T(b.locale, "timezone.unknown", cfg.AuthorID, cfg.Timezone)
+		if _, err = b.discord.SendMessage(ctx, message); err != nil {
+			logger.Error().Err(err).Msg("Unable to send message")
+		}
+
+		return
+	}
+
+	settings, err := b.store.GetUserSettings(ctx, cfg.AuthorID)
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to get user settings")
+
+		return
+	}
+
+	settings.Timezone = loc.String()
+
+	if err = b.store.UpsertUserSettings(ctx, settings); err != nil {
+		logger.Error().Err(err).Msg("Unable to update user settings")
+
+		return
+	}
+
+	message := i18n.T(b.locale, "timezone.done", cfg.AuthorID, loc, b.clock.Now().In(loc).Format(time.RFC1123))
+	if _, err = b.discord.SendMessage(ctx, message); err != nil {
+		logger.Error().Err(err).Msg("Unable to send message")
+
+		return
+	}
+}
+
+// LanguageConfig represents language command config.
+type LanguageConfig struct {
+	AuthorID string
+	Language string
+}
+
+// Validate ensures that all fields are valid.
+func (c LanguageConfig) Validate() error {
+	if c.AuthorID == "" {
+		return errors.New("author id cannot be empty")
+	}
+
+	if c.Language == "" {
+		return errors.New("language cannot be empty")
+	}
+
+	return nil
+}
+
+// SetLanguage handles the language command for the bot.
+// Call it with `!language <fr|en>`.
+// The messages sent to the author are then sent in this language.
+func (b *Bot) SetLanguage(ctx context.Context, cfg LanguageConfig) {
+	if err := cfg.Validate(); err != nil {
+		b.Usage(ctx, "language")
+
+		return
+	}
+
+	logger := log.With().Str("user", cfg.AuthorID).Logger()
+
+	locale, err := i18n.ParseLocale(cfg.Language)
+	if err != nil {
+		b.sendUnknownLanguage(ctx, cfg.AuthorID, cfg.Language)
+
+		return
+	}
+
+	settings, err := b.store.GetUserSettings(ctx, cfg.AuthorID)
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to get user settings")
+
+		return
+	}
+
+	settings.Locale = string(locale)
+
+	if err = b.store.UpsertUserSettings(ctx, settings); err != nil {
+		logger.Error().Err(err).Msg("Unable to update user settings")
+
+		return
+	}
+
+	if _, err = b.discord.SendMessage(ctx, i18n.T(locale, "language.done", cfg.AuthorID)); err != nil {
+		logger.Error().Err(err).Msg("Unable to send message")
+
+		return
+	}
+}
+
+// maxWarnings is the maximum number of warnings sent before the end of a feeding window.
+const maxWarnings = 5
+
+// WarningsConfig represents warn command config.
+// Warnings are the lead times before the end of the feeding window the author is warned at, none disables them.
+type WarningsConfig struct {
+	AuthorID string
+	Warnings []time.Duration
+}
+
+// Validate ensures that all fields are valid.
+func (c WarningsConfig) Validate() error {
+	if c.AuthorID == "" {
+		return errors.New("author id cannot be empty")
+	}
+
+	if len(c.Warnings) > maxWarnings {
+		return fmt.Errorf("at most %d warnings can be set", maxWarnings)
+	}
+
+	for _, warning := range c.Warnings {
+		if warning <= 0 {
+			return errors.New("warning must be positive")
+		}
+	}
+
+	return nil
+}
+
+// SetWarnings handles the warn command for the bot.
+// Call it with `!warn <Duration>...` or `!warn off`.
+// The author is then warned before the end of the feeding windows of their reminds, once per lead time and cycle.
+func (b *Bot) SetWarnings(ctx context.Context, cfg WarningsConfig) {
+	if err := cfg.Validate(); err != nil {
+		b.Usage(ctx, "warn")
+
+		return
+	}
+
+	logger := log.With().Str("user", cfg.AuthorID).Logger()
+
+	settings, err := b.store.GetUserSettings(ctx, cfg.AuthorID)
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to get user settings")
+
+		return
+	}
+
+	settings.Warnings = sortWarnings(cfg.Warnings)
+
+	if err = b.store.UpsertUserSettings(ctx, settings); err != nil {
+		logger.Error().Err(err).Msg("Unable to update user settings")
+
+		return
+	}
+
+	message := i18n.T(b.locale, "warn.off", cfg.AuthorID)
+	if len(settings.Warnings) > 0 {
+		leads := make([]string, 0, len(settings.Warnings))
+		for _, warning := range settings.Warnings {
+			leads = append(leads, formatDuration(warning))
+		}
+
+		message = i18n.T(b.locale, "warn.done", cfg.AuthorID, strings.Join(leads, ", "))
+	}
+
+	if _, err = b.discord.SendMessage(ctx, message); err != nil {
+		logger.Error().Err(err).Msg("Unable to send message")
+
+		return
+	}
+}
+
+// sortWarnings returns the lead times without duplicates, greatest first.
+func sortWarnings(warnings []time.Duration) []time.Duration {
+	var sorted []time.Duration
+
+	for _, warning := range warnings {
+		if !containsDuration(sorted, warning) {
+			sorted = append(sorted, warning)
+		}
+	}
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] > sorted[j] })
+
+	return sorted
+}
+
+func containsDuration(durations []time.Duration, d time.Duration) bool {
+	for _, duration := range durations {
+		if duration == d {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseDuration parses a duration given in minutes, like `45`, or as a Go duration, like `1h30m`.
+// The minutes unit can be left out after hours, like `1h30`.
+func ParseDuration(s string) (time.Duration, error) {
+	if minutes, err := strconv.Atoi(s); err == nil {
+		return time.Duration(minutes) * time.Minute, nil
+	}
+
+	duration, err := time.ParseDuration(s)
+	if err != nil {
+		if d, errMinutes := time.ParseDuration(s + "m"); errMinutes == nil && strings.Contains(s, "h") {
+			return d, nil
+		}
+
+		return 0, fmt.Errorf("parse duration: %w", err)
+	}
+
+	return duration, nil
+}
+
+// parseFedAt parses the time a pet has been fed at, now when it is empty.
+// It is given as a duration ago, like `2h`, or as a time in the timezone of the user, like `14:30` for the last 14:30.
+func (b *Bot) parseFedAt(ctx context.Context, userID, s string) (time.Time, error) {
+	now := b.clock.Now()
+	if s == "" {
+		return now, nil
+	}
+
+	if ago, err := ParseDuration(s); err == nil {
+		if ago < 0 {
+			return time.Time{}, errors.New("duration cannot be negative")
+		}
+
+		return now.Add(-ago), nil
+	}
+
+	loc := b.location(ctx, userID)
+
+	clock, err := time.ParseInLocation("15:04", s, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse time: %w", err)
+	}
+
+	today := now.In(loc)
+
+	fedAt := time.Date(today.Year(), today.Month(), today.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+	if fedAt.After(now) {
+		fedAt = fedAt.AddDate(0, 0, -1)
+	}
+
+	return fedAt, nil
+}
+
+// formatDuration formats the duration without its zero units, like 1h or 1h30m.
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+
+	return s
+}
+
+// SetupConfig represents setup command config.
+// Admin reports whether the author is allowed to configure the guild.
+// Language is only used by the setup language command.
+type SetupConfig struct {
+	AuthorID  string
+	GuildID   string
+	ChannelID string
+	Admin     bool
+	Language  string
+}
+
+// Validate ensures that all fields are valid.
+func (c SetupConfig) Validate() error {
+	if c.AuthorID == "" {
+		return errors.New("author id cannot be empty")
+	}
+
+	if c.ChannelID == "" {
+		return errors.New("channel id cannot be empty")
+	}
+
+	return nil
+}
+
+// SetupChannel handles the setup channel command for the bot.
+// Call it with `!setup channel` in the default channel of the guild, used for the reminds created without a channel.
+func (b *Bot) SetupChannel(ctx context.Context, cfg SetupConfig) {
+	b.setupGuild(ctx, cfg, func(settings *store.GuildSettings) (string, bool) {
+		settings.ChannelID = cfg.ChannelID
+
+		return i18n.T(b.locale, "setup.channel", cfg.AuthorID, cfg.ChannelID), true
+	})
+}
+
+// SetupLanguage handles the setup language command for the bot.
+// Call it with `!setup language <fr|en>`, the messages of the guild are then sent in this language.
+func (b *Bot) SetupLanguage(ctx context.Context, cfg SetupConfig) {
+	b.setupGuild(ctx, cfg, func(settings *store.GuildSettings) (string, bool) {
+		locale, err := i18n.ParseLocale(cfg.Language)
+		if err != nil {
+			b.sendUnknownLanguage(ctx, cfg.AuthorID, cfg.Language)
+
+			return "", false
+		}
+
+		settings.Locale = string(locale)
+
+		return i18n.T(locale, "setup.language", cfg.AuthorID), true
+	})
+}
+
+// setupGuild updates the settings of the guild with configure, once the author has been allowed to.
+// configure returns the message to send, and false if the settings must not be updated.
+func (b *Bot) setupGuild(ctx context.Context, cfg SetupConfig, configure func(settings *store.GuildSettings) (string, bool)) {
+	if err := cfg.Validate(); err != nil {
+		b.Usage(ctx, "setup")
+
+		return
+	}
+
+	logger := log.With().Str("guild", cfg.GuildID).Logger()
+
+	var message string
+
+	switch {
+	case cfg.GuildID == "":
+		message = i18n.T(b.locale, "setup.notInGuild", cfg.AuthorID)
+	case !cfg.Admin:
+		message = i18n.T(b.locale, "setup.notAdmin", cfg.AuthorID)
+	default:
+		settings, err := b.store.GetGuildSettings(ctx, cfg.GuildID)
+		if err != nil {
+			logger.Error().Err(err).Msg("Unable to get guild settings")
+
+			return
+		}
+
+		var ok bool
+		if message, ok = configure(&settings); !ok {
+			return
+		}
+
+		if err = b.store.UpsertGuildSettings(ctx, settings); err != nil {
+			logger.Error().Err(err).Msg("Unable to update guild settings")
+
+			return
+		}
+	}
+
+	if _, err := b.discord.SendMessage(ctx, message); err != nil {
+		logger.Error().Err(err).Msg("Unable to send message")
+
+		return
+	}
+}
+
+func (b *Bot) sendUnknownLanguage(ctx context.Context, authorID, language string) {
+	locales := make([]string, 0, len(i18n.Locales()))
+	for _, l := range i18n.Locales() {
+		locales = append(locales, string(l))
+	}
+
+	message := i18n.T(b.locale, "language.unknown", authorID, language, strings.Join(locales, ", "))
+	if _, err := b.discord.SendMessage(ctx, message); err != nil {
+		log.Error().Err(err).Msg("Unable to send message")
+	}
+}
+
+// listMeals returns the owner of the remind and its last feeds and missed meals, newest first.
+func (b *Bot) listMeals(ctx context.Context, id string, limit int) (string, []store.RemindEvent, error) {
+	var (
+		owner string
+		meals []store.RemindEvent
+	)
+
+	for skip := 0; len(meals) < limit; skip += historyPageSize {
+		events, err := b.store.ListRemindEvents(ctx, id, skip, historyPageSize)
+		if err != nil {
+			return "", nil, err
+		}
+
+		for _, event := range events {
+			owner = event.DiscordUserID
+
+			if event.Type == store.EventFed || event.Type == store.EventMissed {
+				meals = append(meals, event)
+			}
+
+			if len(meals) == limit {
+				break
+			}
+		}
+
+		if len(events) < historyPageSize {
+			break
+		}
+	}
+
+	return owner, meals, nil
+}
+
+// describeMeal describes a feed or a missed meal in the given locale.
+// A feed is on time when it happened inside the [FoodMinDuration, FoodMaxDuration] window of its cycle.
+func describeMeal(l i18n.Locale, event store.RemindEvent) string {
+	if event.Type == store.EventMissed {
+		return i18n.T(l, "meal.missed")
+	}
+
+	switch {
+	case event.CreatedAt.Before(event.NextRemind):
+		return i18n.T(l, "meal.early", event.NextRemind.Sub(event.CreatedAt).Round(time.Minute))
+	case event.CreatedAt.After(event.TimeoutRemind):
+		return i18n.T(l, "meal.late", event.CreatedAt.Sub(event.TimeoutRemind).Round(time.Minute))
+	default:
+		return i18n.T(l, "meal.onTime")
+	}
+}
+
+func (b *Bot) recordEvent(ctx context.Context, remind store.Remind, typ store.EventType, actorID string) {
+	b.recordEventAt(ctx, remind, typ, actorID, b.clock.Now())
+}
+
+// recordEventAt records an event which happened at the given time, like a feed reported afterwards.
+func (b *Bot) recordEventAt(ctx context.Context, remind store.Remind, typ store.EventType, actorID string, at time.Time) {
+	event := store.NewRemindEvent(remind, typ, actorID, at)
+	if err := b.store.CreateRemindEvent(ctx, event); err != nil {
+		log.Error().Err(err).Str("id", remind.ID.String()).Msg("Unable to record remind event")
+	}
+}
+
+// recordMessage records the remind a message sent by the bot is about, so reactions to it can be resolved.
+func (b *Bot) recordMessage(ctx context.Context, sent *discord.Message, remindID store.ID) {
+	// Interaction responses are sent without a message ID.
+	if sent.ID == "" {
+		return
+	}
+
+	msg := store.RemindMessage{
+		MessageID: sent.ID,
+		RemindID:  remindID,
+		CreatedAt: b.clock.Now(),
+	}
+
+	if err := b.store.CreateRemindMessage(ctx, msg); err != nil {
+		log.Error().Err(err).Str("id", remindID.String()).Msg("Unable to record remind message")
+	}
+}
+
+// location returns the timezone of the user, or the timezone of the bot when it cannot be got.
+func (b *Bot) location(ctx context.Context, userID string) *time.Location {
+	settings, err := b.store.GetUserSettings(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Str("user", userID).Msg("Unable to get user settings")
+
+		return b.timezone
+	}
+
+	return settings.Location(b.timezone)
 }
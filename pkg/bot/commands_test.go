@@ -11,9 +11,8 @@ import (
 
 	"github.com/skwair/harmony/discord"
 	"github.com/stretchr/testify/mock"
-	"github.com/stretchr/testify/require"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/i18n"
 	"github.com/youkoulayley/pet-reminder-bot/pkg/store"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 const (
@@ -71,11 +70,15 @@ func TestHandler_Remind(t *testing.T) {
 		desc   string
 		config RemindConfig
 		pet    store.Pet
+		// pets are listed when the pet is not found by its exact name.
+		pets store.Pets
 	}{
 		{
 			desc: "remind chacha on toto",
 			config: RemindConfig{
 				AuthorID:  testDiscordUserID,
+				GuildID:   "guild",
+				ChannelID: "channel",
 				Pet:       "Chacha",
 				Character: "Toto",
 			},
@@ -98,6 +101,24 @@ func TestHandler_Remind(t *testing.T) {
 				FoodMaxDuration: 10 * time.Hour,
 			},
 		},
+		{
+			desc: "remind bebe panda by its alias",
+			config: RemindConfig{
+				AuthorID:  testDiscordUserID,
+				Pet:       "bébé panda",
+				Character: "Tutu",
+			},
+			pet: store.Pet{
+				Name:            "Bebe_Pandawa",
+				Aliases:         []string{"Bébé Panda"},
+				FoodMinDuration: 3 * time.Hour,
+				FoodMaxDuration: 6 * time.Hour,
+			},
+			pets: store.Pets{
+				{Name: "Chacha"},
+				{Name: "Bebe_Pandawa", Aliases: []string{"Bébé Panda"}, FoodMinDuration: 3 * time.Hour, FoodMaxDuration: 6 * time.Hour},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -106,22 +127,36 @@ func TestHandler_Remind(t *testing.T) {
 			t.Parallel()
 
 			s := &storeMock{}
-			s.On("GetPet", test.config.Pet).
-				Return(test.pet, nil).
-				Once()
+			if test.pets != nil {
+				s.On("GetPet", test.config.Pet).
+					Return(store.Pet{}, store.NotFoundError{Err: errors.New("not found")}).
+					Once()
+				s.On("ListPets").
+					Return(test.pets, nil).
+					Once()
+			} else {
+				s.On("GetPet", test.config.Pet).
+					Return(test.pet, nil).
+					Once()
+			}
 			s.On("CreateRemind", mock.MatchedBy(func(r store.Remind) bool {
 				return r.PetName == test.pet.Name &&
 					r.DiscordUserID == test.config.AuthorID &&
+					r.GuildID == test.config.GuildID &&
+					r.ChannelID == test.config.ChannelID &&
 					!r.ReminderSent &&
 					r.MissedReminder == 0 &&
 					r.Character == test.config.Character &&
-					r.NextRemind.Sub(time.Now().Add(test.pet.FoodMinDuration)) < time.Second &&
-					r.NextRemind.Sub(time.Now().Add(test.pet.FoodMaxDuration)) < time.Second
+					r.NextRemind.Equal(testNow.Add(test.pet.FoodMinDuration)) &&
+					r.TimeoutRemind.Equal(testNow.Add(test.pet.FoodMaxDuration))
 			})).Return(nil).
 				Once()
+			s.On("CreateRemindEvent", eventMatcher(store.EventCreated, test.config.AuthorID)).
+				Return(nil).
+				Once()
 
 			r := &reminderMock{}
-			r.On("SetUpdate").Return().Once()
+			r.On("Upsert", mock.AnythingOfType("store.Remind")).Return().Once()
 
 			d := &discordMock{}
 			d.On("SendMessage", mock.MatchedBy(func(msg string) bool {
@@ -134,20 +169,18 @@ func TestHandler_Remind(t *testing.T) {
 					return false
 				}
 
-				dateParts := strings.SplitN(datePart, ": ", 2)
-				date, err := time.Parse(time.RFC1123, dateParts[1])
-				if err != nil {
-					return false
-				}
-
-				if time.Now().Add(test.pet.FoodMinDuration).Sub(date) > time.Minute {
+				if datePart != "Prochain rappel: "+formatTestTime(t, testNow.Add(test.pet.FoodMinDuration)) {
 					return false
 				}
 
 				id := strings.SplitN(idPart, ": ", 2)[1]
 
 				return id != ""
-			})).Return(&discord.Message{}, nil).Once()
+			})).Return(&discord.Message{ID: "123"}, nil).Once()
+
+			s.On("CreateRemindMessage", mock.MatchedBy(func(msg store.RemindMessage) bool {
+				return msg.MessageID == "123" && msg.RemindID != "" && msg.CreatedAt.Equal(testNow)
+			})).Return(nil).Once()
 
 			b := Bot{store: s, discord: d, reminder: r}
 			b = setupBot(t, b)
@@ -198,7 +231,7 @@ func TestHandler_Remind_validation(t *testing.T) {
 			t.Parallel()
 
 			d := &discordMock{}
-			d.On("SendMessage", helpMessage).
+			d.On("SendMessage", i18n.T(i18n.French, "usage.remind")).
 				Return(&discord.Message{}, nil).
 				Once()
 
@@ -212,22 +245,45 @@ func TestHandler_Remind_validation(t *testing.T) {
 }
 
 func TestHandler_Remind_getPetError(t *testing.T) {
+	notFound := store.NotFoundError{Err: errors.New("not found")}
+	pets := store.Pets{{Name: "Chacha"}, {Name: "Chacha_Tigre"}, {Name: "Nomoon"}}
+
 	tests := []struct {
 		desc             string
+		pet              string
 		getPetError      error
+		listPetsError    error
+		wantMessage      string
 		sendMessageError error
 	}{
 		{
 			desc:        "get pet not found",
-			getPetError: store.NotFoundError{Err: errors.New("not found")},
+			pet:         "Pouet",
+			getPetError: notFound,
+			wantMessage: "\"Pouet\" n'existe pas. `!familiers` pour connaître la liste des familiers gérés.",
+		},
+		{
+			desc:        "get pet not found with suggestions",
+			pet:         "Chachaa",
+			getPetError: notFound,
+			wantMessage: "\"Chachaa\" n'existe pas. Vouliez-vous dire `Chacha`, `Chacha_Tigre` ?",
 		},
 		{
 			desc:        "get pet blew up",
+			pet:         "Chacha",
 			getPetError: errors.New("boom"),
 		},
+		{
+			desc:          "list pets blew up",
+			pet:           "Pouet",
+			getPetError:   notFound,
+			listPetsError: errors.New("boom"),
+		},
 		{
 			desc:             "get pet not found and unable to send message",
-			getPetError:      store.NotFoundError{Err: errors.New("not found")},
+			pet:              "Pouet",
+			getPetError:      notFound,
+			wantMessage:      "\"Pouet\" n'existe pas. `!familiers` pour connaître la liste des familiers gérés.",
 			sendMessageError: errors.New("boom"),
 		},
 	}
@@ -238,13 +294,19 @@ func TestHandler_Remind_getPetError(t *testing.T) {
 			t.Parallel()
 
 			s := &storeMock{}
-			s.On("GetPet", "Chacha").
+			s.On("GetPet", test.pet).
 				Return(store.Pet{}, test.getPetError).
 				Once()
 
-			d := &discordMock{}
 			if errors.As(test.getPetError, &store.NotFoundError{}) {
-				d.On("SendMessage", "\"Chacha\" n'existe pas. `!familiers` pour connaître la liste des familiers gérés.").
+				s.On("ListPets").
+					Return(pets, test.listPetsError).
+					Once()
+			}
+
+			d := &discordMock{}
+			if test.wantMessage != "" {
+				d.On("SendMessage", test.wantMessage).
 					Return(&discord.Message{}, test.sendMessageError).
 					Once()
 			}
@@ -254,7 +316,7 @@ func TestHandler_Remind_getPetError(t *testing.T) {
 
 			cfg := RemindConfig{
 				AuthorID:  testDiscordUserID,
-				Pet:       "Chacha",
+				Pet:       test.pet,
 				Character: "Test",
 			}
 			b.Remind(context.Background(), cfg)
@@ -282,8 +344,8 @@ func TestHandler_Remind_createRemindError(t *testing.T) {
 			!r.ReminderSent &&
 			r.MissedReminder == 0 &&
 			r.Character == "Test" &&
-			r.NextRemind.Sub(time.Now().Add(pet.FoodMinDuration)) < time.Second &&
-			r.NextRemind.Sub(time.Now().Add(pet.FoodMaxDuration)) < time.Second
+			r.NextRemind.Equal(testNow.Add(pet.FoodMinDuration)) &&
+			r.TimeoutRemind.Equal(testNow.Add(pet.FoodMaxDuration))
 	})).Return(errors.New("boom")).
 		Once()
 
@@ -317,13 +379,16 @@ func TestHandler_Remind_sendMessageError(t *testing.T) {
 			!r.ReminderSent &&
 			r.MissedReminder == 0 &&
 			r.Character == "Test" &&
-			r.NextRemind.Sub(time.Now().Add(pet.FoodMinDuration)) < time.Second &&
-			r.NextRemind.Sub(time.Now().Add(pet.FoodMaxDuration)) < time.Second
+			r.NextRemind.Equal(testNow.Add(pet.FoodMinDuration)) &&
+			r.TimeoutRemind.Equal(testNow.Add(pet.FoodMaxDuration))
 	})).Return(nil).
 		Once()
+	s.On("CreateRemindEvent", eventMatcher(store.EventCreated, testDiscordUserID)).
+		Return(nil).
+		Once()
 
 	r := &reminderMock{}
-	r.On("SetUpdate").Return().Once()
+	r.On("Upsert", mock.AnythingOfType("store.Remind")).Return().Once()
 
 	d := &discordMock{}
 	d.On("SendMessage", mock.MatchedBy(func(msg string) bool {
@@ -336,13 +401,7 @@ func TestHandler_Remind_sendMessageError(t *testing.T) {
 			return false
 		}
 
-		dateParts := strings.SplitN(datePart, ": ", 2)
-		date, err := time.Parse(time.RFC1123, dateParts[1])
-		if err != nil {
-			return false
-		}
-
-		if time.Now().Add(pet.FoodMinDuration).Sub(date) > time.Minute {
+		if datePart != "Prochain rappel: "+formatTestTime(t, testNow.Add(pet.FoodMinDuration)) {
 			return false
 		}
 
@@ -367,8 +426,7 @@ func TestHandler_Remind_sendMessageError(t *testing.T) {
 }
 
 func TestHandler_RemoveRemind(t *testing.T) {
-	objectID, err := primitive.ObjectIDFromHex(testRemindID)
-	require.NoError(t, err)
+	objectID := store.ID(testRemindID)
 
 	s := &storeMock{}
 	s.On("GetRemind", testRemindID).Return(store.Remind{
@@ -377,20 +435,22 @@ func TestHandler_RemoveRemind(t *testing.T) {
 		PetName:        "Chacha",
 		Character:      "Test",
 		MissedReminder: 0,
-		NextRemind:     time.Now().Add(1 * time.Hour),
+		NextRemind:     testNow.Add(1 * time.Hour),
 		ReminderSent:   false,
-		TimeoutRemind:  time.Now().Add(2 * time.Hour),
+		TimeoutRemind:  testNow.Add(2 * time.Hour),
 	}, nil).Once()
 
 	s.On("RemoveRemind", testRemindID).Return(nil).Once()
+	s.On("CreateRemindEvent", eventMatcher(store.EventRemoved, testDiscordUserID)).Return(nil).Once()
 
 	r := &reminderMock{}
-	r.On("SetUpdate").Once()
+	r.On("Remove", objectID).Once()
 
 	d := &discordMock{}
 	d.On("SendMessage", fmt.Sprintf("<@2> Rappel %q supprimé", testRemindID)).Return(&discord.Message{}, nil).Once()
 
 	b := Bot{store: s, discord: d, reminder: r}
+	b = setupBot(t, b)
 	b.RemoveRemind(context.Background(), RemoveRemindConfig{
 		AuthorID: testDiscordUserID,
 		ID:       testRemindID,
@@ -440,7 +500,7 @@ func TestHandler_RemoveRemind_validation(t *testing.T) {
 			t.Parallel()
 
 			d := &discordMock{}
-			d.On("SendMessage", helpMessage).Return(&discord.Message{}, test.sendMessageError).Once()
+			d.On("SendMessage", i18n.T(i18n.French, "usage.remove")).Return(&discord.Message{}, test.sendMessageError).Once()
 
 			b := Bot{discord: d}
 			b.RemoveRemind(context.Background(), test.config)
@@ -483,8 +543,7 @@ func TestHandler_RemoveRemind_badUser(t *testing.T) {
 		t.Run(test.desc, func(t *testing.T) {
 			t.Parallel()
 
-			objectID, err := primitive.ObjectIDFromHex(testRemindID)
-			require.NoError(t, err)
+			objectID := store.ID(testRemindID)
 
 			s := &storeMock{}
 			s.On("GetRemind", testRemindID).Return(store.Remind{
@@ -493,9 +552,9 @@ func TestHandler_RemoveRemind_badUser(t *testing.T) {
 				PetName:        "Chacha",
 				Character:      "Test",
 				MissedReminder: 0,
-				NextRemind:     time.Now().Add(1 * time.Hour),
+				NextRemind:     testNow.Add(1 * time.Hour),
 				ReminderSent:   false,
-				TimeoutRemind:  time.Now().Add(2 * time.Hour),
+				TimeoutRemind:  testNow.Add(2 * time.Hour),
 			}, nil).Once()
 
 			d := &discordMock{}
@@ -541,8 +600,7 @@ func TestHandler_RemoveRemind_removeRemindError(t *testing.T) {
 		t.Run(test.desc, func(t *testing.T) {
 			t.Parallel()
 
-			objectID, err := primitive.ObjectIDFromHex(testRemindID)
-			require.NoError(t, err)
+			objectID := store.ID(testRemindID)
 
 			s := &storeMock{}
 			s.On("GetRemind", testRemindID).Return(store.Remind{
@@ -551,9 +609,9 @@ func TestHandler_RemoveRemind_removeRemindError(t *testing.T) {
 				PetName:        "Chacha",
 				Character:      "Test",
 				MissedReminder: 0,
-				NextRemind:     time.Now().Add(1 * time.Hour),
+				NextRemind:     testNow.Add(1 * time.Hour),
 				ReminderSent:   false,
-				TimeoutRemind:  time.Now().Add(2 * time.Hour),
+				TimeoutRemind:  testNow.Add(2 * time.Hour),
 			}, nil).Once()
 
 			s.On("RemoveRemind", testRemindID).Return(test.removeRemindError).Once()
@@ -578,8 +636,7 @@ func TestHandler_RemoveRemind_removeRemindError(t *testing.T) {
 }
 
 func TestHandler_RemoveRemind_sendMessageError(t *testing.T) {
-	objectID, err := primitive.ObjectIDFromHex(testRemindID)
-	require.NoError(t, err)
+	objectID := store.ID(testRemindID)
 
 	s := &storeMock{}
 	s.On("GetRemind", testRemindID).Return(store.Remind{
@@ -588,20 +645,22 @@ func TestHandler_RemoveRemind_sendMessageError(t *testing.T) {
 		PetName:        "Chacha",
 		Character:      "Test",
 		MissedReminder: 0,
-		NextRemind:     time.Now().Add(1 * time.Hour),
+		NextRemind:     testNow.Add(1 * time.Hour),
 		ReminderSent:   false,
-		TimeoutRemind:  time.Now().Add(2 * time.Hour),
+		TimeoutRemind:  testNow.Add(2 * time.Hour),
 	}, nil).Once()
 
 	s.On("RemoveRemind", testRemindID).Return(nil).Once()
+	s.On("CreateRemindEvent", eventMatcher(store.EventRemoved, testDiscordUserID)).Return(nil).Once()
 
 	r := &reminderMock{}
-	r.On("SetUpdate").Once()
+	r.On("Remove", objectID).Once()
 
 	d := &discordMock{}
 	d.On("SendMessage", fmt.Sprintf("<@2> Rappel %q supprimé", testRemindID)).Return(&discord.Message{}, errors.New("boom")).Once()
 
 	b := Bot{store: s, discord: d, reminder: r}
+	b = setupBot(t, b)
 
 	cfg := RemoveRemindConfig{
 		AuthorID: testDiscordUserID,
@@ -634,7 +693,7 @@ func TestHandler_Help(t *testing.T) {
 			t.Parallel()
 
 			d := &discordMock{}
-			d.On("SendMessage", helpMessage).
+			d.On("SendMessage", i18n.T(i18n.French, "help")).
 				Return(&discord.Message{}, test.sendMessageError).
 				Once()
 
@@ -647,8 +706,38 @@ func TestHandler_Help(t *testing.T) {
 }
 
 func TestHandler_NewCycle(t *testing.T) {
-	objectID, err := primitive.ObjectIDFromHex(testRemindID)
-	require.NoError(t, err)
+	tests := []struct {
+		desc   string
+		source string
+	}{
+		{
+			desc:   "stored message",
+			source: "stored",
+		},
+		{
+			desc:   "legacy message",
+			source: "legacy",
+		},
+		{
+			desc:   "remind id",
+			source: "id",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			testNewCycle(t, test.source)
+		})
+	}
+}
+
+func testNewCycle(t *testing.T, source string) {
+	t.Helper()
+
+	objectID := store.ID(testRemindID)
 
 	pet := store.Pet{
 		Name:            "Chacha",
@@ -657,9 +746,23 @@ func TestHandler_NewCycle(t *testing.T) {
 	}
 
 	d := &discordMock{}
-	d.On("Message", "123").Return(&discord.Message{Content: "ID: " + testRemindID}, nil).Once()
-
 	s := &storeMock{}
+
+	cfg := NewCycleConfig{
+		AuthorID:  testDiscordUserID,
+		MessageID: "123",
+	}
+
+	switch source {
+	case "stored":
+		s.On("GetRemindMessage", "123").Return(store.RemindMessage{MessageID: "123", RemindID: objectID}, nil).Once()
+	case "legacy":
+		s.On("GetRemindMessage", "123").Return(store.RemindMessage{}, store.NotFoundError{Err: errors.New("not found")}).Once()
+		d.On("Message", "123").Return(&discord.Message{Content: "ID: " + testRemindID}, nil).Once()
+	default:
+		cfg = NewCycleConfig{AuthorID: testDiscordUserID, ID: testRemindID}
+	}
+
 	s.On("GetRemind", testRemindID).Return(store.Remind{
 		ID:             objectID,
 		DiscordUserID:  testDiscordUserID,
@@ -674,35 +777,35 @@ func TestHandler_NewCycle(t *testing.T) {
 	s.On("GetPet", "Chacha").Return(pet, nil).Once()
 
 	s.On("UpdateRemind", mock.MatchedBy(func(remind store.Remind) bool {
-		if time.Now().Add(pet.FoodMinDuration).Sub(remind.NextRemind) > time.Minute {
-			return false
-		}
-
-		if time.Now().Add(pet.FoodMaxDuration).Sub(remind.TimeoutRemind) > time.Minute {
-			return false
-		}
-
 		return reflect.DeepEqual(store.Remind{
 			ID:             objectID,
 			DiscordUserID:  testDiscordUserID,
 			PetName:        "Chacha",
 			Character:      "Test",
 			MissedReminder: 0,
-			NextRemind:     remind.NextRemind,
+			CycleStart:     testNow,
+			NextRemind:     testNow.Add(pet.FoodMinDuration),
 			ReminderSent:   false,
-			TimeoutRemind:  remind.TimeoutRemind,
+			TimeoutRemind:  testNow.Add(pet.FoodMaxDuration),
 		}, remind)
 	})).Return(nil).Once()
 
+	s.On("CreateRemindEvent", eventMatcher(store.EventFed, testDiscordUserID)).Return(nil).Once()
+
 	r := &reminderMock{}
-	r.On("SetUpdate").Once()
+	r.On("Upsert", mock.MatchedBy(func(remind store.Remind) bool {
+		return remind.ID == objectID && !remind.NextRemind.IsZero()
+	})).Once()
+
+	// Reactions are silent, only the fed button and the command are confirmed.
+	if source == "id" {
+		wantMessage := "<@2> \"Chacha\" sur Test nourri\nProchain rappel: " + formatTestTime(t, testNow.Add(pet.FoodMinDuration))
+		d.On("SendMessage", wantMessage).Return(&discord.Message{}, nil).Once()
+	}
 
 	b := Bot{store: s, reminder: r, discord: d}
+	b = setupBot(t, b)
 
-	cfg := NewCycleConfig{
-		AuthorID:  testDiscordUserID,
-		MessageID: "123",
-	}
 	b.NewCycle(context.Background(), cfg)
 
 	d.AssertExpectations(t)
@@ -710,11 +813,200 @@ func TestHandler_NewCycle(t *testing.T) {
 	r.AssertExpectations(t)
 }
 
+func TestHandler_NewCycle_fedAt(t *testing.T) {
+	pet := store.Pet{
+		Name:            "Chacha",
+		FoodMinDuration: 20 * time.Hour,
+		FoodMaxDuration: 30 * time.Hour,
+	}
+
+	tests := []struct {
+		desc  string
+		fedAt string
+		want  time.Time
+	}{
+		{
+			desc:  "duration ago",
+			fedAt: "2h",
+			want:  testNow.Add(-2 * time.Hour),
+		},
+		{
+			desc:  "minutes ago",
+			fedAt: "45",
+			want:  testNow.Add(-45 * time.Minute),
+		},
+		{
+			desc:  "time today",
+			fedAt: "09:30",
+			want:  time.Date(2022, 1, 18, 8, 30, 0, 0, time.UTC),
+		},
+		{
+			desc:  "time yesterday",
+			fedAt: "14:30",
+			want:  time.Date(2022, 1, 17, 13, 30, 0, 0, time.UTC),
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			s := &storeMock{}
+			s.On("GetRemind", testRemindID).
+				Return(store.Remind{ID: testRemindID, DiscordUserID: testDiscordUserID, PetName: "Chacha", Character: "Test"}, nil).
+				Once()
+			s.On("GetPet", "Chacha").Return(pet, nil).Once()
+			s.On("UpdateRemind", mock.MatchedBy(func(remind store.Remind) bool {
+				return remind.NextRemind.Equal(test.want.Add(pet.FoodMinDuration)) &&
+					remind.TimeoutRemind.Equal(test.want.Add(pet.FoodMaxDuration))
+			})).Return(nil).Once()
+			s.On("CreateRemindEvent", mock.MatchedBy(func(e store.RemindEvent) bool {
+				return e.Type == store.EventFed && e.CreatedAt.Equal(test.want)
+			})).Return(nil).Once()
+
+			r := &reminderMock{}
+			r.On("Upsert", mock.AnythingOfType("store.Remind")).Once()
+
+			d := &discordMock{}
+			d.On("SendMessage", "<@2> \"Chacha\" sur Test nourri\nProchain rappel: "+formatTestTime(t, test.want.Add(pet.FoodMinDuration))).
+				Return(&discord.Message{}, nil).
+				Once()
+
+			b := Bot{store: s, reminder: r, discord: d}
+			b = setupBot(t, b)
+			b.NewCycle(context.Background(), NewCycleConfig{AuthorID: testDiscordUserID, ID: testRemindID, FedAt: test.fedAt})
+
+			s.AssertExpectations(t)
+			r.AssertExpectations(t)
+			d.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_NewCycle_fedAtInvalid(t *testing.T) {
+	tests := []struct {
+		desc    string
+		fedAt   string
+		message string
+	}{
+		{
+			desc:    "unknown format",
+			fedAt:   "hier",
+			message: i18n.T(i18n.French, "usage.fed"),
+		},
+		{
+			desc:    "negative duration",
+			fedAt:   "-2h",
+			message: i18n.T(i18n.French, "usage.fed"),
+		},
+		{
+			desc:    "too old",
+			fedAt:   "2h",
+			message: "<@2> \"Chacha\" aurait déjà raté son repas s'il avait été nourri le " + formatTestTime(t, testNow.Add(-2*time.Hour)) + ".",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			s := &storeMock{}
+			s.On("GetRemind", testRemindID).
+				Return(store.Remind{ID: testRemindID, DiscordUserID: testDiscordUserID, PetName: "Chacha", Character: "Test"}, nil).
+				Once()
+			s.On("GetPet", "Chacha").Return(store.Pet{Name: "Chacha", FoodMinDuration: time.Hour, FoodMaxDuration: 2 * time.Hour}, nil).Once()
+
+			d := &discordMock{}
+			d.On("SendMessage", test.message).Return(&discord.Message{}, nil).Once()
+
+			b := Bot{store: s, discord: d}
+			b = setupBot(t, b)
+			b.NewCycle(context.Background(), NewCycleConfig{AuthorID: testDiscordUserID, ID: testRemindID, FedAt: test.fedAt})
+
+			s.AssertExpectations(t)
+			d.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_Remind_fedAt(t *testing.T) {
+	pet := store.Pet{Name: "Chacha", FoodMinDuration: 5 * time.Hour, FoodMaxDuration: 10 * time.Hour}
+	fedAt := testNow.Add(-time.Hour)
+
+	s := &storeMock{}
+	s.On("GetPet", "Chacha").Return(pet, nil).Once()
+	s.On("CreateRemind", mock.MatchedBy(func(r store.Remind) bool {
+		return r.NextRemind.Equal(fedAt.Add(pet.FoodMinDuration)) && r.TimeoutRemind.Equal(fedAt.Add(pet.FoodMaxDuration))
+	})).Return(nil).Once()
+	s.On("CreateRemindEvent", eventMatcher(store.EventCreated, testDiscordUserID)).Return(nil).Once()
+	s.On("CreateRemindMessage", mock.Anything).Return(nil).Once()
+
+	r := &reminderMock{}
+	r.On("Upsert", mock.AnythingOfType("store.Remind")).Return().Once()
+
+	d := &discordMock{}
+	d.On("SendMessage", mock.MatchedBy(func(msg string) bool {
+		return strings.Contains(msg, "Prochain rappel: "+formatTestTime(t, fedAt.Add(pet.FoodMinDuration)))
+	})).Return(&discord.Message{ID: "123"}, nil).Once()
+
+	b := Bot{store: s, discord: d, reminder: r}
+	b = setupBot(t, b)
+	b.Remind(context.Background(), RemindConfig{AuthorID: testDiscordUserID, Pet: "Chacha", Character: "Toto", FedAt: "1h"})
+
+	s.AssertExpectations(t)
+	r.AssertExpectations(t)
+	d.AssertExpectations(t)
+}
+
+func TestParseDuration(t *testing.T) {
+	for s, want := range map[string]time.Duration{
+		"45":    45 * time.Minute,
+		"2h":    2 * time.Hour,
+		"1h30":  90 * time.Minute,
+		"1h30m": 90 * time.Minute,
+		"90s":   90 * time.Second,
+	} {
+		got, err := ParseDuration(s)
+		if err != nil || got != want {
+			t.Errorf("ParseDuration(%q) = %s, %v, want %s", s, got, err, want)
+		}
+	}
+
+	for _, s := range []string{"", "bientôt", "14:30", "30x"} {
+		if _, err := ParseDuration(s); err == nil {
+			t.Errorf("ParseDuration(%q) should fail", s)
+		}
+	}
+}
+
 func TestHandler_NewCycle_messageError(t *testing.T) {
+	s := &storeMock{}
+	s.On("GetRemindMessage", "123").Return(store.RemindMessage{}, store.NotFoundError{Err: errors.New("not found")}).Once()
+
 	d := &discordMock{}
 	d.On("Message", "123").Return(&discord.Message{}, errors.New("boom")).Once()
 
-	b := Bot{discord: d}
+	b := Bot{store: s, discord: d}
+
+	cfg := NewCycleConfig{
+		AuthorID:  testDiscordUserID,
+		MessageID: "123",
+	}
+	b.NewCycle(context.Background(), cfg)
+
+	s.AssertExpectations(t)
+	d.AssertExpectations(t)
+}
+
+func TestHandler_NewCycle_getRemindMessageError(t *testing.T) {
+	s := &storeMock{}
+	s.On("GetRemindMessage", "123").Return(store.RemindMessage{}, errors.New("boom")).Once()
+
+	d := &discordMock{}
+
+	b := Bot{store: s, discord: d}
 
 	cfg := NewCycleConfig{
 		AuthorID:  testDiscordUserID,
@@ -722,6 +1014,7 @@ func TestHandler_NewCycle_messageError(t *testing.T) {
 	}
 	b.NewCycle(context.Background(), cfg)
 
+	s.AssertExpectations(t)
 	d.AssertExpectations(t)
 }
 
@@ -738,6 +1031,10 @@ func TestHandler_NewCycle_validation(t *testing.T) {
 			desc:   "author id empty",
 			config: NewCycleConfig{MessageID: "123"},
 		},
+		{
+			desc:   "invalid remind id",
+			config: NewCycleConfig{AuthorID: "5", ID: "invalid"},
+		},
 	}
 
 	for _, test := range tests {
@@ -746,7 +1043,7 @@ func TestHandler_NewCycle_validation(t *testing.T) {
 			t.Parallel()
 
 			d := &discordMock{}
-			d.On("SendMessage", helpMessage).
+			d.On("SendMessage", i18n.T(i18n.French, "usage.fed")).
 				Return(&discord.Message{}, nil).
 				Once()
 
@@ -778,16 +1075,20 @@ func TestHandler_NewCycle_validation_messageID(t *testing.T) {
 		t.Run(test.desc, func(t *testing.T) {
 			t.Parallel()
 
+			s := &storeMock{}
+			s.On("GetRemindMessage", "123").Return(store.RemindMessage{}, store.NotFoundError{Err: errors.New("not found")}).Once()
+
 			d := &discordMock{}
 			d.On("Message", "123").Return(&discord.Message{Content: test.message}, nil).Once()
 
-			b := Bot{discord: d}
+			b := Bot{store: s, discord: d}
 			cfg := NewCycleConfig{
 				AuthorID:  "5",
 				MessageID: "123",
 			}
 			b.NewCycle(context.Background(), cfg)
 
+			s.AssertExpectations(t)
 			d.AssertExpectations(t)
 		})
 	}
@@ -795,9 +1096,9 @@ func TestHandler_NewCycle_validation_messageID(t *testing.T) {
 
 func TestHandler_NewCycle_getRemindError(t *testing.T) {
 	d := &discordMock{}
-	d.On("Message", "123").Return(&discord.Message{Content: "ID: " + testRemindID}, nil).Once()
 
 	s := &storeMock{}
+	s.On("GetRemindMessage", "123").Return(store.RemindMessage{MessageID: "123", RemindID: testRemindID}, nil).Once()
 	s.On("GetRemind", testRemindID).Return(store.Remind{}, errors.New("boom")).Once()
 
 	b := Bot{store: s, discord: d}
@@ -813,44 +1114,110 @@ func TestHandler_NewCycle_getRemindError(t *testing.T) {
 }
 
 func TestHandler_NewCycle_badUser(t *testing.T) {
-	objectID, err := primitive.ObjectIDFromHex(testRemindID)
-	require.NoError(t, err)
+	tests := []struct {
+		desc        string
+		config      NewCycleConfig
+		wantMessage string
+	}{
+		{
+			desc:   "reaction",
+			config: NewCycleConfig{AuthorID: testDiscordUserID, MessageID: "123"},
+		},
+		{
+			desc:        "remind id",
+			config:      NewCycleConfig{AuthorID: testDiscordUserID, ID: testRemindID},
+			wantMessage: "<@2> Vous ne pouvez pas nourrir le familier d'un rappel qui ne vous appartient pas.",
+		},
+	}
 
-	d := &discordMock{}
-	d.On("Message", "123").Return(&discord.Message{Content: "ID: " + testRemindID}, nil).Once()
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
 
-	s := &storeMock{}
-	s.On("GetRemind", testRemindID).Return(store.Remind{
-		ID:             objectID,
-		DiscordUserID:  "5",
-		PetName:        "Chacha",
-		Character:      "Test",
-		MissedReminder: 0,
-		NextRemind:     time.Time{},
-		ReminderSent:   false,
-		TimeoutRemind:  time.Time{},
-	}, nil).Once()
+			s := &storeMock{}
+			if test.config.MessageID != "" {
+				s.On("GetRemindMessage", "123").Return(store.RemindMessage{MessageID: "123", RemindID: testRemindID}, nil).Once()
+			}
 
-	b := Bot{store: s, discord: d}
+			s.On("GetRemind", testRemindID).Return(store.Remind{
+				ID:            testRemindID,
+				DiscordUserID: "5",
+				PetName:       "Chacha",
+				Character:     "Test",
+			}, nil).Once()
 
-	cfg := NewCycleConfig{
-		AuthorID:  testDiscordUserID,
-		MessageID: "123",
-	}
-	b.NewCycle(context.Background(), cfg)
+			d := &discordMock{}
+			if test.wantMessage != "" {
+				d.On("SendMessage", test.wantMessage).Return(&discord.Message{}, nil).Once()
+			}
 
-	d.AssertExpectations(t)
-	s.AssertExpectations(t)
-}
+			b := Bot{store: s, discord: d}
+			b.NewCycle(context.Background(), test.config)
 
-func TestHandler_NewCycle_getPetError(t *testing.T) {
-	objectID, err := primitive.ObjectIDFromHex(testRemindID)
-	require.NoError(t, err)
+			d.AssertExpectations(t)
+			s.AssertExpectations(t)
+		})
+	}
+}
 
-	d := &discordMock{}
-	d.On("Message", "123").Return(&discord.Message{Content: "ID: " + testRemindID}, nil).Once()
+func TestHandler_NewCycle_paused(t *testing.T) {
+	tests := []struct {
+		desc        string
+		config      NewCycleConfig
+		wantMessage string
+	}{
+		{
+			desc:   "reaction",
+			config: NewCycleConfig{AuthorID: testDiscordUserID, MessageID: "123"},
+		},
+		{
+			desc:        "remind id",
+			config:      NewCycleConfig{AuthorID: testDiscordUserID, ID: testRemindID, FedAt: "2h"},
+			wantMessage: fmt.Sprintf("<@2> Le rappel de \"Chacha\" sur Test est en pause, `!reprendre %s` pour le reprendre.", testRemindID),
+		},
+	}
 
-	s := &storeMock{}
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			s := &storeMock{}
+			if test.config.MessageID != "" {
+				s.On("GetRemindMessage", "123").Return(store.RemindMessage{MessageID: "123", RemindID: testRemindID}, nil).Once()
+			}
+
+			s.On("GetRemind", testRemindID).Return(store.Remind{
+				ID:            testRemindID,
+				DiscordUserID: testDiscordUserID,
+				PetName:       "Chacha",
+				Character:     "Test",
+				Paused:        true,
+			}, nil).Once()
+
+			d := &discordMock{}
+			if test.wantMessage != "" {
+				d.On("SendMessage", test.wantMessage).Return(&discord.Message{}, nil).Once()
+			}
+
+			b := Bot{store: s, discord: d, reminder: &reminderMock{}}
+			b = setupBot(t, b)
+			b.NewCycle(context.Background(), test.config)
+
+			d.AssertExpectations(t)
+			s.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_NewCycle_getPetError(t *testing.T) {
+	objectID := store.ID(testRemindID)
+
+	d := &discordMock{}
+
+	s := &storeMock{}
+	s.On("GetRemindMessage", "123").Return(store.RemindMessage{MessageID: "123", RemindID: testRemindID}, nil).Once()
 	s.On("GetRemind", testRemindID).Return(store.Remind{
 		ID:             objectID,
 		DiscordUserID:  testDiscordUserID,
@@ -877,8 +1244,7 @@ func TestHandler_NewCycle_getPetError(t *testing.T) {
 }
 
 func TestHandler_NewCycle_updateRemindError(t *testing.T) {
-	objectID, err := primitive.ObjectIDFromHex(testRemindID)
-	require.NoError(t, err)
+	objectID := store.ID(testRemindID)
 
 	pet := store.Pet{
 		Name:            "Chacha",
@@ -887,9 +1253,9 @@ func TestHandler_NewCycle_updateRemindError(t *testing.T) {
 	}
 
 	d := &discordMock{}
-	d.On("Message", "123").Return(&discord.Message{Content: "ID: " + testRemindID}, nil).Once()
 
 	s := &storeMock{}
+	s.On("GetRemindMessage", "123").Return(store.RemindMessage{MessageID: "123", RemindID: testRemindID}, nil).Once()
 	s.On("GetRemind", testRemindID).Return(store.Remind{
 		ID:             objectID,
 		DiscordUserID:  testDiscordUserID,
@@ -904,27 +1270,21 @@ func TestHandler_NewCycle_updateRemindError(t *testing.T) {
 	s.On("GetPet", "Chacha").Return(pet, nil).Once()
 
 	s.On("UpdateRemind", mock.MatchedBy(func(remind store.Remind) bool {
-		if time.Now().Add(pet.FoodMinDuration).Sub(remind.NextRemind) > time.Minute {
-			return false
-		}
-
-		if time.Now().Add(pet.FoodMaxDuration).Sub(remind.TimeoutRemind) > time.Minute {
-			return false
-		}
-
 		return reflect.DeepEqual(store.Remind{
 			ID:             objectID,
 			DiscordUserID:  testDiscordUserID,
 			PetName:        "Chacha",
 			Character:      "Test",
 			MissedReminder: 0,
-			NextRemind:     remind.NextRemind,
+			CycleStart:     testNow,
+			NextRemind:     testNow.Add(pet.FoodMinDuration),
 			ReminderSent:   false,
-			TimeoutRemind:  remind.TimeoutRemind,
+			TimeoutRemind:  testNow.Add(pet.FoodMaxDuration),
 		}, remind)
 	})).Return(errors.New("boom")).Once()
 
 	b := Bot{store: s, discord: d}
+	b = setupBot(t, b)
 
 	cfg := NewCycleConfig{
 		AuthorID:  testDiscordUserID,
@@ -936,21 +1296,279 @@ func TestHandler_NewCycle_updateRemindError(t *testing.T) {
 	s.AssertExpectations(t)
 }
 
+func TestHandler_Snooze(t *testing.T) {
+	tests := []struct {
+		desc      string
+		remind    store.Remind
+		duration  time.Duration
+		wantUntil time.Time
+	}{
+		{
+			desc: "within the feeding window",
+			remind: store.Remind{
+				NextRemind:    testNow.Add(-time.Hour),
+				ReminderSent:  true,
+				TimeoutRemind: testNow.Add(2 * time.Hour),
+			},
+			duration:  30 * time.Minute,
+			wantUntil: testNow.Add(30 * time.Minute),
+		},
+		{
+			desc: "before the feeding window",
+			remind: store.Remind{
+				NextRemind:    testNow.Add(time.Hour),
+				TimeoutRemind: testNow.Add(2 * time.Hour),
+			},
+			duration:  30 * time.Minute,
+			wantUntil: testNow.Add(time.Hour),
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			remind := test.remind
+			remind.ID = testRemindID
+			remind.DiscordUserID = testDiscordUserID
+			remind.PetName = "Chacha"
+			remind.Character = "Test"
+			remind.MissedReminder = 1
+
+			want := remind
+			want.ReminderSent = false
+			want.SnoozedUntil = test.wantUntil
+
+			s := &storeMock{}
+			s.On("GetRemind", testRemindID).Return(remind, nil).Once()
+			s.On("UpdateRemind", want).Return(nil).Once()
+
+			r := &reminderMock{}
+			r.On("Upsert", want).Once()
+
+			d := &discordMock{}
+			d.On("SendMessage", "<@2> Rappel de \"Chacha\" sur Test repoussé au "+formatTestTime(t, test.wantUntil)).
+				Return(&discord.Message{}, nil).
+				Once()
+
+			b := Bot{store: s, reminder: r, discord: d}
+			b = setupBot(t, b)
+
+			b.Snooze(context.Background(), SnoozeConfig{AuthorID: testDiscordUserID, ID: testRemindID, Duration: test.duration})
+
+			s.AssertExpectations(t)
+			r.AssertExpectations(t)
+			d.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_Snooze_tooLate(t *testing.T) {
+	tests := []struct {
+		desc     string
+		duration time.Duration
+	}{
+		{
+			desc:     "after the feeding window",
+			duration: 30 * time.Minute,
+		},
+		{
+			desc:     "at the end of the feeding window",
+			duration: 10 * time.Minute,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			remind := store.Remind{
+				ID:            testRemindID,
+				DiscordUserID: testDiscordUserID,
+				PetName:       "Chacha",
+				Character:     "Test",
+				NextRemind:    testNow.Add(-time.Hour),
+				ReminderSent:  true,
+				TimeoutRemind: testNow.Add(10 * time.Minute),
+			}
+
+			s := &storeMock{}
+			s.On("GetRemind", testRemindID).Return(remind, nil).Once()
+
+			d := &discordMock{}
+			d.On("SendMessage", "<@2> Le rappel de \"Chacha\" sur Test ne peut pas être repoussé après la fin de la période de repas, le "+formatTestTime(t, remind.TimeoutRemind)+".").
+				Return(&discord.Message{}, nil).
+				Once()
+
+			b := Bot{store: s, discord: d}
+			b = setupBot(t, b)
+
+			b.Snooze(context.Background(), SnoozeConfig{AuthorID: testDiscordUserID, ID: testRemindID, Duration: test.duration})
+
+			s.AssertExpectations(t)
+			d.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_Snooze_paused(t *testing.T) {
+	s := &storeMock{}
+	s.On("GetRemind", testRemindID).Return(store.Remind{
+		ID:            testRemindID,
+		DiscordUserID: testDiscordUserID,
+		PetName:       "Chacha",
+		Character:     "Test",
+		NextRemind:    testNow.Add(-time.Hour),
+		TimeoutRemind: testNow.Add(2 * time.Hour),
+		Paused:        true,
+	}, nil).Once()
+
+	d := &discordMock{}
+	d.On("SendMessage", fmt.Sprintf("<@2> Le rappel de \"Chacha\" sur Test est en pause, `!reprendre %s` pour le reprendre.", testRemindID)).
+		Return(&discord.Message{}, nil).
+		Once()
+
+	b := Bot{store: s, discord: d, reminder: &reminderMock{}}
+	b = setupBot(t, b)
+	b.Snooze(context.Background(), SnoozeConfig{AuthorID: testDiscordUserID, ID: testRemindID, Duration: 30 * time.Minute})
+
+	s.AssertExpectations(t)
+	d.AssertExpectations(t)
+}
+
+func TestHandler_Snooze_validation(t *testing.T) {
+	tests := []struct {
+		desc   string
+		config SnoozeConfig
+	}{
+		{
+			desc:   "author id empty",
+			config: SnoozeConfig{ID: testRemindID, Duration: time.Minute},
+		},
+		{
+			desc:   "invalid id",
+			config: SnoozeConfig{AuthorID: "5", ID: "invalid", Duration: time.Minute},
+		},
+		{
+			desc:   "no duration",
+			config: SnoozeConfig{AuthorID: "5", ID: testRemindID},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			d := &discordMock{}
+			d.On("SendMessage", i18n.T(i18n.French, "usage.snooze")).Return(&discord.Message{}, nil).Once()
+
+			b := Bot{discord: d}
+			b.Snooze(context.Background(), test.config)
+
+			d.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_Snooze_badUser(t *testing.T) {
+	s := &storeMock{}
+	s.On("GetRemind", testRemindID).Return(store.Remind{ID: testRemindID, DiscordUserID: "5"}, nil).Once()
+
+	d := &discordMock{}
+	d.On("SendMessage", "<@2> Vous ne pouvez pas repousser un rappel qui ne vous appartient pas.").
+		Return(&discord.Message{}, nil).
+		Once()
+
+	b := Bot{store: s, discord: d}
+	b.Snooze(context.Background(), SnoozeConfig{AuthorID: testDiscordUserID, ID: testRemindID, Duration: time.Minute})
+
+	s.AssertExpectations(t)
+	d.AssertExpectations(t)
+}
+
+func TestHandler_Snooze_updateRemindError(t *testing.T) {
+	s := &storeMock{}
+	s.On("GetRemind", testRemindID).Return(store.Remind{
+		ID:            testRemindID,
+		DiscordUserID: testDiscordUserID,
+		ReminderSent:  true,
+		TimeoutRemind: testNow.Add(time.Hour),
+	}, nil).Once()
+	s.On("UpdateRemind", mock.Anything).Return(errors.New("boom")).Once()
+
+	b := Bot{store: s}
+	b = setupBot(t, b)
+
+	b.Snooze(context.Background(), SnoozeConfig{AuthorID: testDiscordUserID, ID: testRemindID, Duration: time.Minute})
+
+	s.AssertExpectations(t)
+}
+
 func TestHandler_ListReminds(t *testing.T) {
 	ctx := context.Background()
 
 	s := &storeMock{}
 	s.On("ListRemindsByID", "3").
 		Return([]store.Remind{
-			{PetName: "Chacha", Character: "Test", NextRemind: time.Time{}},
-			{PetName: "Nomoon", Character: "Test2", NextRemind: time.Time{}.Add(time.Hour)},
+			{ID: "61e71f03735c4de773d8879a", PetName: "Chacha", Character: "Test", NextRemind: time.Time{}},
+			{ID: "61e71f03735c4de773d8879b", PetName: "Nomoon", Character: "Test2", NextRemind: time.Time{}.Add(time.Hour)},
+		}, nil).
+		Once()
+
+	d := &discordMock{}
+	wantMessage := `<@3> Liste de vos rappels:
+  - 61e71f03735c4de773d8879a - Chacha sur Test - Prochain rappel: Mon, 01 Jan 0001 00:09:21 LMT
+  - 61e71f03735c4de773d8879b - Nomoon sur Test2 - Prochain rappel: Mon, 01 Jan 0001 01:09:21 LMT`
+	d.On("SendMessage", wantMessage).Return(&discord.Message{}, nil).Once()
+
+	b := Bot{discord: d, store: s}
+	b = setupBot(t, b)
+	b.ListReminds(ctx, "3")
+
+	s.AssertExpectations(t)
+	d.AssertExpectations(t)
+}
+
+func TestHandler_ListReminds_paused(t *testing.T) {
+	s := &storeMock{}
+	s.On("ListRemindsByID", "3").
+		Return([]store.Remind{
+			{ID: "61e71f03735c4de773d8879a", PetName: "Chacha", Character: "Test", Paused: true},
+			{ID: "61e71f03735c4de773d8879b", PetName: "Nomoon", Character: "Test2", Paused: true, PausedUntil: testNow},
 		}, nil).
 		Once()
 
 	d := &discordMock{}
 	wantMessage := `<@3> Liste de vos rappels:
-  - 000000000000000000000000 - Chacha sur Test - Prochain rappel: Mon, 01 Jan 0001 00:09:21 LMT
-  - 000000000000000000000000 - Nomoon sur Test2 - Prochain rappel: Mon, 01 Jan 0001 01:09:21 LMT`
+  - 61e71f03735c4de773d8879a - Chacha sur Test - En pause
+  - 61e71f03735c4de773d8879b - Nomoon sur Test2 - En pause jusqu'au ` + formatTestTime(t, testNow)
+	d.On("SendMessage", wantMessage).Return(&discord.Message{}, nil).Once()
+
+	b := Bot{discord: d, store: s}
+	b = setupBot(t, b)
+	b.ListReminds(context.Background(), "3")
+
+	s.AssertExpectations(t)
+	d.AssertExpectations(t)
+}
+
+func TestHandler_ListReminds_userTimezone(t *testing.T) {
+	ctx := context.Background()
+
+	s := &storeMock{}
+	s.On("ListRemindsByID", "3").
+		Return([]store.Remind{{ID: "61e71f03735c4de773d8879a", PetName: "Chacha", Character: "Test", NextRemind: testNow}}, nil).
+		Once()
+	s.On("GetUserSettings", "3").
+		Return(store.UserSettings{UserID: "3", Timezone: "America/New_York"}, nil).
+		Once()
+
+	d := &discordMock{}
+	wantMessage := `<@3> Liste de vos rappels:
+  - 61e71f03735c4de773d8879a - Chacha sur Test - Prochain rappel: Tue, 18 Jan 2022 05:00:00 EST`
 	d.On("SendMessage", wantMessage).Return(&discord.Message{}, nil).Once()
 
 	b := Bot{discord: d, store: s}
@@ -1022,15 +1640,15 @@ func TestHandler_ListReminds_sendMessageError(t *testing.T) {
 	s := &storeMock{}
 	s.On("ListRemindsByID", "3").
 		Return([]store.Remind{
-			{PetName: "Chacha", Character: "Test", NextRemind: time.Time{}},
-			{PetName: "Nomoon", Character: "Test2", NextRemind: time.Time{}.Add(time.Hour)},
+			{ID: "61e71f03735c4de773d8879a", PetName: "Chacha", Character: "Test", NextRemind: time.Time{}},
+			{ID: "61e71f03735c4de773d8879b", PetName: "Nomoon", Character: "Test2", NextRemind: time.Time{}.Add(time.Hour)},
 		}, nil).
 		Once()
 
 	d := &discordMock{}
 	wantMessage := `<@3> Liste de vos rappels:
-  - 000000000000000000000000 - Chacha sur Test - Prochain rappel: Mon, 01 Jan 0001 00:09:21 LMT
-  - 000000000000000000000000 - Nomoon sur Test2 - Prochain rappel: Mon, 01 Jan 0001 01:09:21 LMT`
+  - 61e71f03735c4de773d8879a - Chacha sur Test - Prochain rappel: Mon, 01 Jan 0001 00:09:21 LMT
+  - 61e71f03735c4de773d8879b - Nomoon sur Test2 - Prochain rappel: Mon, 01 Jan 0001 01:09:21 LMT`
 	d.On("SendMessage", wantMessage).Return(&discord.Message{}, errors.New("boom")).Once()
 
 	b := Bot{discord: d, store: s}
@@ -1040,3 +1658,1113 @@ func TestHandler_ListReminds_sendMessageError(t *testing.T) {
 	s.AssertExpectations(t)
 	d.AssertExpectations(t)
 }
+
+func TestHandler_History(t *testing.T) {
+	start := time.Date(2022, 1, 18, 10, 0, 0, 0, time.UTC)
+	nextRemind := start.Add(5 * time.Hour)
+	timeoutRemind := start.Add(18 * time.Hour)
+
+	event := func(typ store.EventType, at time.Time) store.RemindEvent {
+		return store.RemindEvent{
+			ID:            store.NewID(),
+			RemindID:      testRemindID,
+			Type:          typ,
+			DiscordUserID: testDiscordUserID,
+			PetName:       "Chacha",
+			Character:     "Test",
+			NextRemind:    nextRemind,
+			TimeoutRemind: timeoutRemind,
+			CreatedAt:     at,
+		}
+	}
+
+	tests := []struct {
+		desc        string
+		authorID    string
+		events      []store.RemindEvent
+		wantMessage string
+	}{
+		{
+			desc:     "feeds and misses",
+			authorID: testDiscordUserID,
+			events: []store.RemindEvent{
+				event(store.EventFed, timeoutRemind.Add(90*time.Minute)),
+				event(store.EventMissed, timeoutRemind),
+				event(store.EventReminderSent, nextRemind.Add(2*time.Hour)),
+				event(store.EventFed, nextRemind.Add(time.Hour)),
+				event(store.EventFed, nextRemind.Add(-30*time.Minute)),
+				event(store.EventCreated, start),
+			},
+			wantMessage: `<@2> Historique de Chacha sur Test:
+  - Wed, 19 Jan 2022 06:30:00 CET - Nourri en retard (1h30m0s après la fin de la fenêtre)
+  - Wed, 19 Jan 2022 05:00:00 CET - Repas râté
+  - Tue, 18 Jan 2022 17:00:00 CET - Nourri dans les temps
+  - Tue, 18 Jan 2022 15:30:00 CET - Nourri trop tôt (30m0s avant le début de la fenêtre)`,
+		},
+		{
+			desc:        "no meal",
+			authorID:    testDiscordUserID,
+			events:      []store.RemindEvent{event(store.EventCreated, start)},
+			wantMessage: `<@2> Aucun repas enregistré pour le rappel "61e71f03735c4de773d8879a"`,
+		},
+		{
+			desc:        "no history",
+			authorID:    testDiscordUserID,
+			events:      []store.RemindEvent{},
+			wantMessage: `<@2> Pas d'historique pour le rappel "61e71f03735c4de773d8879a"`,
+		},
+		{
+			desc:        "bad user",
+			authorID:    "3",
+			events:      []store.RemindEvent{event(store.EventFed, start)},
+			wantMessage: "<@3> Vous ne pouvez pas consulter l'historique d'un rappel qui ne vous appartient pas.",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			s := &storeMock{}
+			s.On("ListRemindEvents", testRemindID, 0, historyPageSize).
+				Return(test.events, nil).
+				Once()
+
+			d := &discordMock{}
+			d.On("SendMessage", test.wantMessage).Return(&discord.Message{}, nil).Once()
+
+			b := Bot{discord: d, store: s}
+			b = setupBot(t, b)
+			b.History(context.Background(), HistoryConfig{AuthorID: test.authorID, ID: testRemindID, Limit: 10})
+
+			s.AssertExpectations(t)
+			d.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_History_paging(t *testing.T) {
+	page := make([]store.RemindEvent, historyPageSize)
+	for i := range page {
+		page[i] = store.RemindEvent{Type: store.EventReminderSent, DiscordUserID: testDiscordUserID}
+	}
+
+	fed := store.RemindEvent{Type: store.EventFed, DiscordUserID: testDiscordUserID, PetName: "Chacha", Character: "Test"}
+
+	s := &storeMock{}
+	s.On("ListRemindEvents", testRemindID, 0, historyPageSize).Return(page, nil).Once()
+	s.On("ListRemindEvents", testRemindID, historyPageSize, historyPageSize).Return([]store.RemindEvent{fed, fed, fed}, nil).Once()
+
+	d := &discordMock{}
+	d.On("SendMessage", mock.MatchedBy(func(msg string) bool {
+		return strings.Count(msg, "Nourri dans les temps") == 2
+	})).Return(&discord.Message{}, nil).Once()
+
+	b := Bot{discord: d, store: s}
+	b = setupBot(t, b)
+	b.History(context.Background(), HistoryConfig{AuthorID: testDiscordUserID, ID: testRemindID, Limit: 2})
+
+	s.AssertExpectations(t)
+	d.AssertExpectations(t)
+}
+
+func TestHandler_History_validation(t *testing.T) {
+	tests := []struct {
+		desc string
+		cfg  HistoryConfig
+	}{
+		{
+			desc: "author id missing",
+			cfg:  HistoryConfig{ID: testRemindID, Limit: 10},
+		},
+		{
+			desc: "id invalid",
+			cfg:  HistoryConfig{AuthorID: testDiscordUserID, ID: "123", Limit: 10},
+		},
+		{
+			desc: "limit too low",
+			cfg:  HistoryConfig{AuthorID: testDiscordUserID, ID: testRemindID},
+		},
+		{
+			desc: "limit too high",
+			cfg:  HistoryConfig{AuthorID: testDiscordUserID, ID: testRemindID, Limit: MaxHistoryLimit + 1},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			d := &discordMock{}
+			d.On("SendMessage", i18n.T(i18n.French, "usage.history")).Return(&discord.Message{}, nil).Once()
+
+			b := Bot{discord: d}
+			b.History(context.Background(), test.cfg)
+
+			d.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_History_storeError(t *testing.T) {
+	s := &storeMock{}
+	s.On("ListRemindEvents", testRemindID, 0, historyPageSize).
+		Return([]store.RemindEvent{}, errors.New("boom")).
+		Once()
+
+	b := Bot{store: s}
+	b = setupBot(t, b)
+	b.History(context.Background(), HistoryConfig{AuthorID: testDiscordUserID, ID: testRemindID, Limit: 10})
+
+	s.AssertExpectations(t)
+}
+
+func TestHandler_SetNotify(t *testing.T) {
+	tests := []struct {
+		desc    string
+		mode    string
+		message string
+	}{
+		{
+			desc:    "direct message",
+			mode:    "dm",
+			message: fmt.Sprintf("<@%s> Vos rappels seront envoyés en message privé.", testDiscordUserID),
+		},
+		{
+			desc:    "channel",
+			mode:    "channel",
+			message: fmt.Sprintf("<@%s> Vos rappels seront envoyés dans le salon.", testDiscordUserID),
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			s := &storeMock{}
+			s.On("GetUserSettings", testDiscordUserID).Return(store.DefaultUserSettings(testDiscordUserID), nil).Once()
+			s.On("UpsertUserSettings", store.UserSettings{UserID: testDiscordUserID, Notify: store.NotifyMode(test.mode)}).Return(nil).Once()
+
+			d := &discordMock{}
+			d.On("SendMessage", test.message).Return(&discord.Message{}, nil).Once()
+
+			b := Bot{store: s, discord: d}
+			b = setupBot(t, b)
+			b.SetNotify(context.Background(), NotifyConfig{AuthorID: testDiscordUserID, Mode: test.mode})
+
+			s.AssertExpectations(t)
+			d.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_SetNotify_validation(t *testing.T) {
+	tests := []struct {
+		desc string
+		cfg  NotifyConfig
+	}{
+		{
+			desc: "author id missing",
+			cfg:  NotifyConfig{Mode: "dm"},
+		},
+		{
+			desc: "unknown mode",
+			cfg:  NotifyConfig{AuthorID: testDiscordUserID, Mode: "mp"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			d := &discordMock{}
+			d.On("SendMessage", i18n.T(i18n.French, "usage.notify")).Return(&discord.Message{}, nil).Once()
+
+			b := Bot{discord: d}
+			b.SetNotify(context.Background(), test.cfg)
+
+			d.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_SetNotify_storeError(t *testing.T) {
+	s := &storeMock{}
+	s.On("GetUserSettings", testDiscordUserID).Return(store.UserSettings{}, errors.New("boom")).Once()
+
+	b := Bot{store: s}
+	b = setupBot(t, b)
+	b.SetNotify(context.Background(), NotifyConfig{AuthorID: testDiscordUserID, Mode: "dm"})
+
+	s.AssertExpectations(t)
+}
+
+func TestHandler_SetupChannel(t *testing.T) {
+	tests := []struct {
+		desc    string
+		cfg     SetupConfig
+		upsert  bool
+		message string
+	}{
+		{
+			desc:    "admin",
+			cfg:     SetupConfig{AuthorID: testDiscordUserID, GuildID: "guild", ChannelID: "channel", Admin: true},
+			upsert:  true,
+			message: fmt.Sprintf("<@%s> Les rappels du serveur sans salon seront envoyés dans <#channel>.", testDiscordUserID),
+		},
+		{
+			desc:    "not admin",
+			cfg:     SetupConfig{AuthorID: testDiscordUserID, GuildID: "guild", ChannelID: "channel"},
+			message: fmt.Sprintf("<@%s> Seuls les administrateurs du serveur peuvent le configurer.", testDiscordUserID),
+		},
+		{
+			desc:    "not in a guild",
+			cfg:     SetupConfig{AuthorID: testDiscordUserID, ChannelID: "channel", Admin: true},
+			message: fmt.Sprintf("<@%s> Cette commande n'est disponible que sur un serveur.", testDiscordUserID),
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			s := &storeMock{}
+			if test.upsert {
+				s.On("GetGuildSettings", "guild").Return(store.GuildSettings{GuildID: "guild", Locale: "en"}, nil).Once()
+				s.On("UpsertGuildSettings", store.GuildSettings{GuildID: "guild", ChannelID: "channel", Locale: "en"}).Return(nil).Once()
+			}
+
+			d := &discordMock{}
+			d.On("SendMessage", test.message).Return(&discord.Message{}, nil).Once()
+
+			b := Bot{store: s, discord: d}
+			b.SetupChannel(context.Background(), test.cfg)
+
+			s.AssertExpectations(t)
+			d.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_SetupChannel_storeError(t *testing.T) {
+	s := &storeMock{}
+	s.On("GetGuildSettings", "guild").Return(store.GuildSettings{GuildID: "guild"}, nil).Once()
+	s.On("UpsertGuildSettings", mock.Anything).Return(errors.New("boom")).Once()
+
+	d := &discordMock{}
+
+	b := Bot{store: s, discord: d}
+	b.SetupChannel(context.Background(), SetupConfig{AuthorID: testDiscordUserID, GuildID: "guild", ChannelID: "channel", Admin: true})
+
+	s.AssertExpectations(t)
+	d.AssertNotCalled(t, "SendMessage", mock.Anything)
+}
+
+func TestHandler_SetTimezone(t *testing.T) {
+	s := &storeMock{}
+	s.On("GetUserSettings", testDiscordUserID).Return(store.DefaultUserSettings(testDiscordUserID), nil).Once()
+	s.On("UpsertUserSettings", store.UserSettings{UserID: testDiscordUserID, Notify: store.NotifyChannel, Timezone: "America/New_York"}).Return(nil).Once()
+
+	d := &discordMock{}
+	d.On("SendMessage", fmt.Sprintf("<@%s> Les heures vous seront affichées dans le fuseau horaire America/New_York: Tue, 18 Jan 2022 05:00:00 EST", testDiscordUserID)).
+		Return(&discord.Message{}, nil).
+		Once()
+
+	b := Bot{store: s, discord: d}
+	b = setupBot(t, b)
+	b.SetTimezone(context.Background(), TimezoneConfig{AuthorID: testDiscordUserID, Timezone: "America/New_York"})
+
+	s.AssertExpectations(t)
+	d.AssertExpectations(t)
+}
+
+func TestHandler_SetTimezone_unknown(t *testing.T) {
+	for _, timezone := range []string{"Europe/Pouet", "Local"} {
+		d := &discordMock{}
+		d.On("SendMessage", fmt.Sprintf("<@%s> Fuseau horaire %q inconnu, par exemple: `Europe/Paris`.", testDiscordUserID, timezone)).
+			Return(&discord.Message{}, nil).
+			Once()
+
+		b := Bot{store: &storeMock{}, discord: d}
+		b.SetTimezone(context.Background(), TimezoneConfig{AuthorID: testDiscordUserID, Timezone: timezone})
+
+		d.AssertExpectations(t)
+	}
+}
+
+func TestHandler_SetTimezone_validation(t *testing.T) {
+	d := &discordMock{}
+	d.On("SendMessage", i18n.T(i18n.French, "usage.timezone")).Return(&discord.Message{}, nil).Once()
+
+	b := Bot{discord: d}
+	b.SetTimezone(context.Background(), TimezoneConfig{AuthorID: testDiscordUserID})
+
+	d.AssertExpectations(t)
+}
+
+func TestHandler_SetWarnings(t *testing.T) {
+	tests := []struct {
+		desc     string
+		warnings []time.Duration
+		want     []time.Duration
+		message  string
+	}{
+		{
+			desc:     "warnings",
+			warnings: []time.Duration{15 * time.Minute, 90 * time.Minute, time.Hour, 15 * time.Minute},
+			want:     []time.Duration{90 * time.Minute, time.Hour, 15 * time.Minute},
+			message:  fmt.Sprintf("<@%s> Vous serez prévenu 1h30m, 1h, 15m avant la fin de chaque fenêtre de repas.", testDiscordUserID),
+		},
+		{
+			desc:    "off",
+			message: fmt.Sprintf("<@%s> Vous ne serez plus prévenu avant la fin des fenêtres de repas.", testDiscordUserID),
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			s := &storeMock{}
+			s.On("GetUserSettings", testDiscordUserID).
+				Return(store.UserSettings{UserID: testDiscordUserID, Timezone: "Europe/Paris", Warnings: []time.Duration{time.Minute}}, nil).
+				Once()
+			s.On("UpsertUserSettings", store.UserSettings{UserID: testDiscordUserID, Timezone: "Europe/Paris", Warnings: test.want}).Return(nil).Once()
+
+			d := &discordMock{}
+			d.On("SendMessage", test.message).Return(&discord.Message{}, nil).Once()
+
+			b := Bot{store: s, discord: d}
+			b = setupBot(t, b)
+			b.SetWarnings(context.Background(), WarningsConfig{AuthorID: testDiscordUserID, Warnings: test.warnings})
+
+			s.AssertExpectations(t)
+			d.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_SetWarnings_validation(t *testing.T) {
+	tests := []struct {
+		desc string
+		cfg  WarningsConfig
+	}{
+		{
+			desc: "author id missing",
+			cfg:  WarningsConfig{Warnings: []time.Duration{time.Hour}},
+		},
+		{
+			desc: "negative warning",
+			cfg:  WarningsConfig{AuthorID: testDiscordUserID, Warnings: []time.Duration{-time.Hour}},
+		},
+		{
+			desc: "too many warnings",
+			cfg:  WarningsConfig{AuthorID: testDiscordUserID, Warnings: []time.Duration{1, 2, 3, 4, 5, 6}},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			d := &discordMock{}
+			d.On("SendMessage", i18n.T(i18n.French, "usage.warn")).Return(&discord.Message{}, nil).Once()
+
+			b := Bot{discord: d}
+			b.SetWarnings(context.Background(), test.cfg)
+
+			d.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		time.Hour:                 "1h",
+		90 * time.Minute:          "1h30m",
+		15 * time.Minute:          "15m",
+		30 * time.Second:          "30s",
+		time.Minute + time.Second: "1m1s",
+		2*time.Hour + time.Second: "2h0m1s",
+	} {
+		if got := formatDuration(d); got != want {
+			t.Errorf("formatDuration(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestHandler_SetLanguage(t *testing.T) {
+	s := &storeMock{}
+	s.On("GetUserSettings", testDiscordUserID).Return(store.UserSettings{UserID: testDiscordUserID, Timezone: "Europe/Paris"}, nil).Once()
+	s.On("UpsertUserSettings", store.UserSettings{UserID: testDiscordUserID, Timezone: "Europe/Paris", Locale: "en"}).Return(nil).Once()
+
+	d := &discordMock{}
+	d.On("SendMessage", fmt.Sprintf("<@%s> Messages will be sent to you in English.", testDiscordUserID)).
+		Return(&discord.Message{}, nil).
+		Once()
+
+	b := Bot{store: s, discord: d, locale: i18n.French}
+	b.SetLanguage(context.Background(), LanguageConfig{AuthorID: testDiscordUserID, Language: "en-US"})
+
+	s.AssertExpectations(t)
+	d.AssertExpectations(t)
+}
+
+func TestHandler_SetLanguage_unknown(t *testing.T) {
+	d := &discordMock{}
+	d.On("SendMessage", fmt.Sprintf("<@%s> Langue \"de\" inconnue, langues disponibles: fr, en.", testDiscordUserID)).
+		Return(&discord.Message{}, nil).
+		Once()
+
+	b := Bot{store: &storeMock{}, discord: d}
+	b.SetLanguage(context.Background(), LanguageConfig{AuthorID: testDiscordUserID, Language: "de"})
+
+	d.AssertExpectations(t)
+}
+
+func TestHandler_SetupLanguage(t *testing.T) {
+	s := &storeMock{}
+	s.On("GetGuildSettings", "guild").Return(store.GuildSettings{GuildID: "guild", ChannelID: "channel"}, nil).Once()
+	s.On("UpsertGuildSettings", store.GuildSettings{GuildID: "guild", ChannelID: "channel", Locale: "en"}).Return(nil).Once()
+
+	d := &discordMock{}
+	d.On("SendMessage", fmt.Sprintf("<@%s> The messages of the server will be sent in English.", testDiscordUserID)).
+		Return(&discord.Message{}, nil).
+		Once()
+
+	b := Bot{store: s, discord: d}
+	b.SetupLanguage(context.Background(), SetupConfig{AuthorID: testDiscordUserID, GuildID: "guild", ChannelID: "other", Admin: true, Language: "en"})
+
+	s.AssertExpectations(t)
+	d.AssertExpectations(t)
+}
+
+func TestHandler_SetupLanguage_unknown(t *testing.T) {
+	s := &storeMock{}
+	s.On("GetGuildSettings", "guild").Return(store.GuildSettings{GuildID: "guild"}, nil).Once()
+
+	d := &discordMock{}
+	d.On("SendMessage", fmt.Sprintf("<@%s> Langue \"de\" inconnue, langues disponibles: fr, en.", testDiscordUserID)).
+		Return(&discord.Message{}, nil).
+		Once()
+
+	b := Bot{store: s, discord: d}
+	b.SetupLanguage(context.Background(), SetupConfig{AuthorID: testDiscordUserID, GuildID: "guild", ChannelID: "channel", Admin: true, Language: "de"})
+
+	s.AssertExpectations(t)
+	s.AssertNotCalled(t, "UpsertGuildSettings", mock.Anything)
+	d.AssertExpectations(t)
+}
+
+func TestHandler_Help_english(t *testing.T) {
+	d := &discordMock{}
+	d.On("SendMessage", mock.MatchedBy(func(text string) bool {
+		return strings.HasPrefix(text, "Available commands:") && strings.Contains(text, "`!pets`")
+	})).Return(&discord.Message{}, nil).Once()
+
+	b := Bot{discord: d, locale: i18n.English}
+	b.Help(context.Background())
+
+	d.AssertExpectations(t)
+}
+
+func TestHandler_Usage(t *testing.T) {
+	d := &discordMock{}
+	d.On("SendMessage", "Usage: `!remove <ID>`, the ID is given by `!list`.").Return(&discord.Message{}, nil).Once()
+
+	b := Bot{discord: d, locale: i18n.English}
+	b.Usage(context.Background(), "remove")
+
+	d.AssertExpectations(t)
+}
+
+func TestHandler_Pause(t *testing.T) {
+	remind := store.Remind{
+		ID:            testRemindID,
+		DiscordUserID: testDiscordUserID,
+		PetName:       "Chacha",
+		Character:     "Toto",
+		ReminderSent:  true,
+		SnoozedUntil:  testNow,
+	}
+
+	paused := remind
+	paused.Paused = true
+	paused.SnoozedUntil = time.Time{}
+
+	s := &storeMock{}
+	s.On("GetRemind", testRemindID).Return(remind, nil).Once()
+	s.On("UpdateRemind", paused).Return(nil).Once()
+	s.On("CreateRemindEvent", eventMatcher(store.EventPaused, testDiscordUserID)).Return(nil).Once()
+
+	r := &reminderMock{}
+	r.On("Upsert", paused).Return().Once()
+
+	d := &discordMock{}
+	d.On("SendMessage", fmt.Sprintf("<@%s> Rappel de \"Chacha\" sur Toto mis en pause, `!reprendre %s` pour le reprendre.", testDiscordUserID, testRemindID)).
+		Return(&discord.Message{}, nil).
+		Once()
+
+	b := Bot{store: s, discord: d, reminder: r}
+	b = setupBot(t, b)
+	b.Pause(context.Background(), PauseConfig{AuthorID: testDiscordUserID, ID: testRemindID})
+
+	s.AssertExpectations(t)
+	r.AssertExpectations(t)
+	d.AssertExpectations(t)
+}
+
+func TestHandler_Pause_all(t *testing.T) {
+	reminds := []store.Remind{
+		{ID: store.NewID(), DiscordUserID: testDiscordUserID, PetName: "Chacha"},
+		{ID: store.NewID(), DiscordUserID: testDiscordUserID, PetName: "Nomoon"},
+	}
+
+	s := &storeMock{}
+	s.On("ListRemindsByID", testDiscordUserID).Return(reminds, nil).Once()
+	s.On("UpdateRemind", mock.MatchedBy(func(r store.Remind) bool { return r.Paused })).Return(nil).Twice()
+	s.On("CreateRemindEvent", eventMatcher(store.EventPaused, testDiscordUserID)).Return(nil).Twice()
+
+	r := &reminderMock{}
+	r.On("Upsert", mock.MatchedBy(func(r store.Remind) bool { return r.Paused })).Return().Twice()
+
+	d := &discordMock{}
+	d.On("SendMessage", fmt.Sprintf("<@%s> 2 rappel(s) mis en pause, `!reprendre all` pour les reprendre.", testDiscordUserID)).
+		Return(&discord.Message{}, nil).
+		Once()
+
+	b := Bot{store: s, discord: d, reminder: r}
+	b = setupBot(t, b)
+	b.Pause(context.Background(), PauseConfig{AuthorID: testDiscordUserID, ID: AllReminds})
+
+	s.AssertExpectations(t)
+	r.AssertExpectations(t)
+	d.AssertExpectations(t)
+}
+
+func TestHandler_Pause_notOwner(t *testing.T) {
+	s := &storeMock{}
+	s.On("GetRemind", testRemindID).Return(store.Remind{ID: testRemindID, DiscordUserID: "other"}, nil).Once()
+
+	d := &discordMock{}
+	d.On("SendMessage", fmt.Sprintf("<@%s> Vous ne pouvez pas mettre en pause un rappel qui ne vous appartient pas.", testDiscordUserID)).
+		Return(&discord.Message{}, nil).
+		Once()
+
+	b := Bot{store: s, discord: d}
+	b = setupBot(t, b)
+	b.Pause(context.Background(), PauseConfig{AuthorID: testDiscordUserID, ID: testRemindID})
+
+	s.AssertExpectations(t)
+	d.AssertExpectations(t)
+}
+
+func TestHandler_Pause_validation(t *testing.T) {
+	d := &discordMock{}
+	d.On("SendMessage", i18n.T(i18n.French, "usage.pause")).Return(&discord.Message{}, nil).Once()
+
+	b := Bot{discord: d}
+	b.Pause(context.Background(), PauseConfig{AuthorID: testDiscordUserID, ID: "tout"})
+
+	d.AssertExpectations(t)
+}
+
+func TestHandler_Edit(t *testing.T) {
+	fedAt := testNow.Add(-2 * time.Hour)
+	chacha := store.Pet{Name: "Chacha", FoodMinDuration: 5 * time.Hour, FoodMaxDuration: 10 * time.Hour}
+	nomoon := store.Pet{Name: "Nomoon", FoodMinDuration: time.Hour, FoodMaxDuration: 3 * time.Hour}
+	dragoune := store.Pet{Name: "Dragoune_Rose", FoodMinDuration: 3 * time.Hour, FoodMaxDuration: 6 * time.Hour}
+
+	tests := []struct {
+		desc   string
+		remind store.Remind
+		newPet store.Pet
+		config EditConfig
+		want   store.Remind
+	}{
+		{
+			desc: "character",
+			remind: store.Remind{
+				PetName:       "Chacha",
+				Character:     "Toto",
+				CycleStart:    fedAt,
+				NextRemind:    fedAt.Add(5 * time.Hour),
+				TimeoutRemind: fedAt.Add(10 * time.Hour),
+			},
+			config: EditConfig{Character: "Titi"},
+			want: store.Remind{
+				PetName:       "Chacha",
+				Character:     "Titi",
+				CycleStart:    fedAt,
+				NextRemind:    fedAt.Add(5 * time.Hour),
+				TimeoutRemind: fedAt.Add(10 * time.Hour),
+			},
+		},
+		{
+			desc: "pet and character",
+			remind: store.Remind{
+				PetName:       "Chacha",
+				Character:     "Toto",
+				CycleStart:    fedAt,
+				NextRemind:    fedAt.Add(5 * time.Hour),
+				TimeoutRemind: fedAt.Add(10 * time.Hour),
+			},
+			newPet: dragoune,
+			config: EditConfig{Pet: "dragoune rose", Character: "Titi"},
+			want: store.Remind{
+				PetName:       "Dragoune_Rose",
+				Character:     "Titi",
+				CycleStart:    fedAt,
+				NextRemind:    fedAt.Add(3 * time.Hour),
+				TimeoutRemind: fedAt.Add(6 * time.Hour),
+			},
+		},
+		{
+			desc: "pet with a feeding window starting later",
+			remind: store.Remind{
+				PetName:       "Nomoon",
+				Character:     "Toto",
+				CycleStart:    fedAt,
+				NextRemind:    fedAt.Add(time.Hour),
+				ReminderSent:  true,
+				SnoozedUntil:  testNow.Add(10 * time.Minute),
+				TimeoutRemind: fedAt.Add(3 * time.Hour),
+				Warnings:      []time.Duration{30 * time.Minute},
+			},
+			newPet: chacha,
+			config: EditConfig{Pet: "Chacha"},
+			want: store.Remind{
+				PetName:       "Chacha",
+				Character:     "Toto",
+				CycleStart:    fedAt,
+				NextRemind:    fedAt.Add(5 * time.Hour),
+				TimeoutRemind: fedAt.Add(10 * time.Hour),
+			},
+		},
+		{
+			desc: "pet with a feeding window already started",
+			remind: store.Remind{
+				PetName:       "Chacha",
+				Character:     "Toto",
+				CycleStart:    fedAt,
+				NextRemind:    fedAt.Add(5 * time.Hour),
+				TimeoutRemind: fedAt.Add(10 * time.Hour),
+			},
+			newPet: nomoon,
+			config: EditConfig{Pet: "Nomoon"},
+			want: store.Remind{
+				PetName:       "Nomoon",
+				Character:     "Toto",
+				CycleStart:    fedAt,
+				NextRemind:    fedAt.Add(time.Hour),
+				TimeoutRemind: fedAt.Add(3 * time.Hour),
+			},
+		},
+		{
+			desc: "pet durations updated since the last feed",
+			remind: store.Remind{
+				PetName:       "Chacha",
+				Character:     "Toto",
+				CycleStart:    fedAt,
+				NextRemind:    fedAt.Add(4 * time.Hour),
+				TimeoutRemind: fedAt.Add(8 * time.Hour),
+			},
+			newPet: dragoune,
+			config: EditConfig{Pet: "Dragoune_Rose"},
+			want: store.Remind{
+				PetName:       "Dragoune_Rose",
+				Character:     "Toto",
+				CycleStart:    fedAt,
+				NextRemind:    fedAt.Add(3 * time.Hour),
+				TimeoutRemind: fedAt.Add(6 * time.Hour),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			remind := test.remind
+			remind.ID = testRemindID
+			remind.DiscordUserID = testDiscordUserID
+			remind.MissedReminder = 2
+
+			want := test.want
+			want.ID = testRemindID
+			want.DiscordUserID = testDiscordUserID
+			want.MissedReminder = 2
+
+			s := &storeMock{}
+			s.On("GetRemind", testRemindID).Return(remind, nil).Once()
+
+			if test.config.Pet != "" {
+				if test.config.Pet == test.newPet.Name {
+					s.On("GetPet", test.config.Pet).Return(test.newPet, nil).Once()
+				} else {
+					s.On("GetPet", test.config.Pet).Return(store.Pet{}, store.NotFoundError{Err: errors.New("not found")}).Once()
+					s.On("ListPets").Return(store.Pets{chacha, nomoon, dragoune}, nil).Once()
+				}
+			}
+
+			s.On("UpdateRemind", want).Return(nil).Once()
+			s.On("CreateRemindEvent", eventMatcher(store.EventEdited, testDiscordUserID)).Return(nil).Once()
+
+			r := &reminderMock{}
+			r.On("Upsert", want).Return().Once()
+
+			d := &discordMock{}
+			d.On("SendMessage", fmt.Sprintf("<@%s> Rappel %s modifié: %q sur %s\nProchain rappel: %s",
+				testDiscordUserID, testRemindID, want.PetName, want.Character, formatTestTime(t, want.NextRemind))).
+				Return(&discord.Message{}, nil).
+				Once()
+
+			b := Bot{store: s, discord: d, reminder: r}
+			b = setupBot(t, b)
+
+			cfg := test.config
+			cfg.AuthorID = testDiscordUserID
+			cfg.ID = testRemindID
+			b.Edit(context.Background(), cfg)
+
+			s.AssertExpectations(t)
+			r.AssertExpectations(t)
+			d.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_Edit_legacyRemind(t *testing.T) {
+	fedAt := testNow.Add(-2 * time.Hour)
+	dragoune := store.Pet{Name: "Dragoune_Rose", FoodMinDuration: 3 * time.Hour, FoodMaxDuration: 6 * time.Hour}
+
+	remind := store.Remind{
+		ID:            testRemindID,
+		DiscordUserID: testDiscordUserID,
+		PetName:       "Chacha",
+		Character:     "Toto",
+		NextRemind:    fedAt.Add(4 * time.Hour),
+		TimeoutRemind: fedAt.Add(8 * time.Hour),
+	}
+
+	want := remind
+	want.PetName = "Dragoune_Rose"
+	want.CycleStart = fedAt
+	want.NextRemind = fedAt.Add(3 * time.Hour)
+	want.TimeoutRemind = fedAt.Add(6 * time.Hour)
+
+	s := &storeMock{}
+	s.On("GetRemind", testRemindID).Return(remind, nil).Once()
+	s.On("GetPet", "Dragoune_Rose").Return(dragoune, nil).Once()
+	s.On("ListRemindEvents", testRemindID, 0, historyPageSize).Return([]store.RemindEvent{
+		{RemindID: testRemindID, Type: store.EventReminderSent, CreatedAt: testNow.Add(-time.Hour)},
+		{RemindID: testRemindID, Type: store.EventFed, CreatedAt: fedAt},
+		{RemindID: testRemindID, Type: store.EventCreated, CreatedAt: testNow.Add(-24 * time.Hour)},
+	}, nil).Once()
+	s.On("UpdateRemind", want).Return(nil).Once()
+	s.On("CreateRemindEvent", eventMatcher(store.EventEdited, testDiscordUserID)).Return(nil).Once()
+
+	r := &reminderMock{}
+	r.On("Upsert", want).Return().Once()
+
+	d := &discordMock{}
+	d.On("SendMessage", fmt.Sprintf("<@%s> Rappel %s modifié: %q sur %s\nProchain rappel: %s",
+		testDiscordUserID, testRemindID, want.PetName, want.Character, formatTestTime(t, want.NextRemind))).
+		Return(&discord.Message{}, nil).
+		Once()
+
+	b := Bot{store: s, discord: d, reminder: r}
+	b = setupBot(t, b)
+	b.Edit(context.Background(), EditConfig{AuthorID: testDiscordUserID, ID: testRemindID, Pet: "Dragoune_Rose"})
+
+	s.AssertExpectations(t)
+	r.AssertExpectations(t)
+	d.AssertExpectations(t)
+}
+
+func TestHandler_Edit_tooOld(t *testing.T) {
+	fedAt := testNow.Add(-4 * time.Hour)
+	nomoon := store.Pet{Name: "Nomoon", FoodMinDuration: time.Hour, FoodMaxDuration: 3 * time.Hour}
+
+	s := &storeMock{}
+	s.On("GetRemind", testRemindID).Return(store.Remind{
+		ID:            testRemindID,
+		DiscordUserID: testDiscordUserID,
+		PetName:       "Chacha",
+		Character:     "Toto",
+		CycleStart:    fedAt,
+		NextRemind:    fedAt.Add(5 * time.Hour),
+		TimeoutRemind: fedAt.Add(10 * time.Hour),
+	}, nil).Once()
+	s.On("GetPet", "Nomoon").Return(nomoon, nil).Once()
+
+	d := &discordMock{}
+	d.On("SendMessage", fmt.Sprintf("<@%s> \"Nomoon\" aurait déjà raté son repas s'il avait été nourri le %s.", testDiscordUserID, formatTestTime(t, fedAt))).
+		Return(&discord.Message{}, nil).
+		Once()
+
+	b := Bot{store: s, discord: d, reminder: &reminderMock{}}
+	b = setupBot(t, b)
+	b.Edit(context.Background(), EditConfig{AuthorID: testDiscordUserID, ID: testRemindID, Pet: "Nomoon"})
+
+	s.AssertExpectations(t)
+	d.AssertExpectations(t)
+}
+
+func TestHandler_Edit_paused(t *testing.T) {
+	s := &storeMock{}
+	s.On("GetRemind", testRemindID).Return(store.Remind{
+		ID:            testRemindID,
+		DiscordUserID: testDiscordUserID,
+		PetName:       "Chacha",
+		Character:     "Toto",
+		Paused:        true,
+	}, nil).Once()
+
+	d := &discordMock{}
+	d.On("SendMessage", fmt.Sprintf("<@2> Le rappel de \"Chacha\" sur Toto est en pause, `!reprendre %s` pour le reprendre.", testRemindID)).
+		Return(&discord.Message{}, nil).
+		Once()
+
+	b := Bot{store: s, discord: d, reminder: &reminderMock{}}
+	b = setupBot(t, b)
+	b.Edit(context.Background(), EditConfig{AuthorID: testDiscordUserID, ID: testRemindID, Pet: "Nomoon", Character: "Titi"})
+
+	s.AssertExpectations(t)
+	d.AssertExpectations(t)
+}
+
+func TestHandler_Edit_unknownPet(t *testing.T) {
+	s := &storeMock{}
+	s.On("GetRemind", testRemindID).Return(store.Remind{ID: testRemindID, DiscordUserID: testDiscordUserID, PetName: "Chacha"}, nil).Once()
+	s.On("GetPet", "Chachaa").Return(store.Pet{}, store.NotFoundError{Err: errors.New("not found")}).Once()
+	s.On("ListPets").Return(store.Pets{{Name: "Chacha"}, {Name: "Nomoon"}}, nil).Once()
+
+	d := &discordMock{}
+	d.On("SendMessage", "\"Chachaa\" n'existe pas. Vouliez-vous dire `Chacha` ?").
+		Return(&discord.Message{}, nil).
+		Once()
+
+	b := Bot{store: s, discord: d}
+	b = setupBot(t, b)
+	b.Edit(context.Background(), EditConfig{AuthorID: testDiscordUserID, ID: testRemindID, Pet: "Chachaa"})
+
+	s.AssertExpectations(t)
+	d.AssertExpectations(t)
+}
+
+func TestHandler_Edit_notOwner(t *testing.T) {
+	s := &storeMock{}
+	s.On("GetRemind", testRemindID).Return(store.Remind{ID: testRemindID, DiscordUserID: "other"}, nil).Once()
+
+	d := &discordMock{}
+	d.On("SendMessage", fmt.Sprintf("<@%s> Vous ne pouvez pas modifier un rappel qui ne vous appartient pas.", testDiscordUserID)).
+		Return(&discord.Message{}, nil).
+		Once()
+
+	b := Bot{store: s, discord: d}
+	b = setupBot(t, b)
+	b.Edit(context.Background(), EditConfig{AuthorID: testDiscordUserID, ID: testRemindID, Character: "Titi"})
+
+	s.AssertExpectations(t)
+	d.AssertExpectations(t)
+}
+
+func TestHandler_Edit_validation(t *testing.T) {
+	tests := []struct {
+		desc   string
+		config EditConfig
+	}{
+		{
+			desc:   "empty author",
+			config: EditConfig{ID: testRemindID, Character: "Titi"},
+		},
+		{
+			desc:   "invalid id",
+			config: EditConfig{AuthorID: testDiscordUserID, ID: AllReminds, Character: "Titi"},
+		},
+		{
+			desc:   "nothing to edit",
+			config: EditConfig{AuthorID: testDiscordUserID, ID: testRemindID},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			d := &discordMock{}
+			d.On("SendMessage", i18n.T(i18n.French, "usage.edit")).Return(&discord.Message{}, nil).Once()
+
+			b := Bot{discord: d}
+			b.Edit(context.Background(), test.config)
+
+			d.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_Resume(t *testing.T) {
+	remind := store.Remind{
+		ID:             testRemindID,
+		DiscordUserID:  testDiscordUserID,
+		PetName:        "Chacha",
+		Character:      "Toto",
+		MissedReminder: 3,
+		ReminderSent:   true,
+		Paused:         true,
+		PausedUntil:    testNow.Add(time.Hour),
+	}
+
+	pet := store.Pet{Name: "Chacha", FoodMinDuration: time.Hour, FoodMaxDuration: 2 * time.Hour}
+
+	resumed := remind
+	resumed.Paused = false
+	resumed.PausedUntil = time.Time{}
+	resumed.MissedReminder = 0
+	resumed.ReminderSent = false
+	resumed.CycleStart = testNow
+	resumed.NextRemind = testNow.Add(time.Hour)
+	resumed.TimeoutRemind = testNow.Add(2 * time.Hour)
+
+	s := &storeMock{}
+	s.On("GetRemind", testRemindID).Return(remind, nil).Once()
+	s.On("GetPet", "Chacha").Return(pet, nil).Once()
+	s.On("UpdateRemind", resumed).Return(nil).Once()
+	s.On("CreateRemindEvent", eventMatcher(store.EventResumed, testDiscordUserID)).Return(nil).Once()
+
+	r := &reminderMock{}
+	r.On("Upsert", resumed).Return().Once()
+
+	d := &discordMock{}
+	d.On("SendMessage", fmt.Sprintf("<@%s> Rappel de \"Chacha\" sur Toto repris\nProchain rappel: %s", testDiscordUserID, formatTestTime(t, resumed.NextRemind))).
+		Return(&discord.Message{}, nil).
+		Once()
+
+	b := Bot{store: s, discord: d, reminder: r}
+	b = setupBot(t, b)
+	b.Resume(context.Background(), ResumeConfig{AuthorID: testDiscordUserID, ID: testRemindID})
+
+	s.AssertExpectations(t)
+	r.AssertExpectations(t)
+	d.AssertExpectations(t)
+}
+
+func TestHandler_Resume_notPaused(t *testing.T) {
+	s := &storeMock{}
+	s.On("GetRemind", testRemindID).
+		Return(store.Remind{ID: testRemindID, DiscordUserID: testDiscordUserID, PetName: "Chacha", Character: "Toto"}, nil).
+		Once()
+
+	d := &discordMock{}
+	d.On("SendMessage", fmt.Sprintf("<@%s> Le rappel de \"Chacha\" sur Toto n'est pas en pause.", testDiscordUserID)).
+		Return(&discord.Message{}, nil).
+		Once()
+
+	b := Bot{store: s, discord: d}
+	b = setupBot(t, b)
+	b.Resume(context.Background(), ResumeConfig{AuthorID: testDiscordUserID, ID: testRemindID})
+
+	s.AssertExpectations(t)
+	d.AssertExpectations(t)
+}
+
+func TestHandler_Resume_all(t *testing.T) {
+	reminds := []store.Remind{
+		{ID: store.NewID(), DiscordUserID: testDiscordUserID, PetName: "Chacha", Paused: true},
+		{ID: store.NewID(), DiscordUserID: testDiscordUserID, PetName: "Nomoon"},
+	}
+
+	s := &storeMock{}
+	s.On("ListRemindsByID", testDiscordUserID).Return(reminds, nil).Once()
+	s.On("GetPet", "Chacha").Return(store.Pet{Name: "Chacha", FoodMinDuration: time.Hour, FoodMaxDuration: 2 * time.Hour}, nil).Once()
+	s.On("UpdateRemind", mock.MatchedBy(func(r store.Remind) bool { return r.ID == reminds[0].ID && !r.Paused })).Return(nil).Once()
+	s.On("CreateRemindEvent", eventMatcher(store.EventResumed, testDiscordUserID)).Return(nil).Once()
+
+	r := &reminderMock{}
+	r.On("Upsert", mock.AnythingOfType("store.Remind")).Return().Once()
+
+	d := &discordMock{}
+	d.On("SendMessage", fmt.Sprintf("<@%s> 1 rappel(s) repris.", testDiscordUserID)).
+		Return(&discord.Message{}, nil).
+		Once()
+
+	b := Bot{store: s, discord: d, reminder: r}
+	b = setupBot(t, b)
+	b.Resume(context.Background(), ResumeConfig{AuthorID: testDiscordUserID, ID: AllReminds})
+
+	s.AssertExpectations(t)
+	r.AssertExpectations(t)
+	d.AssertExpectations(t)
+}
+
+func TestHandler_Vacation(t *testing.T) {
+	tests := []struct {
+		desc  string
+		until string
+		want  time.Time
+	}{
+		{
+			desc:  "date",
+			until: "2022-02-01",
+			want:  time.Date(2022, 1, 31, 23, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:  "date and time",
+			until: "2022-02-01 18:00",
+			want:  time.Date(2022, 2, 1, 17, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			remind := store.Remind{ID: testRemindID, DiscordUserID: testDiscordUserID, PetName: "Chacha"}
+
+			s := &storeMock{}
+			s.On("ListRemindsByID", testDiscordUserID).Return([]store.Remind{remind}, nil).Once()
+			s.On("UpdateRemind", mock.MatchedBy(func(r store.Remind) bool { return r.Paused && r.PausedUntil.Equal(test.want) })).Return(nil).Once()
+			s.On("CreateRemindEvent", eventMatcher(store.EventPaused, testDiscordUserID)).Return(nil).Once()
+
+			r := &reminderMock{}
+			r.On("Upsert", mock.AnythingOfType("store.Remind")).Return().Once()
+
+			d := &discordMock{}
+			d.On("SendMessage", fmt.Sprintf("<@%s> 1 rappel(s) mis en pause jusqu'au %s.", testDiscordUserID, formatTestTime(t, test.want))).
+				Return(&discord.Message{}, nil).
+				Once()
+
+			b := Bot{store: s, discord: d, reminder: r}
+			b = setupBot(t, b)
+			b.Vacation(context.Background(), VacationConfig{AuthorID: testDiscordUserID, Until: test.until})
+
+			s.AssertExpectations(t)
+			r.AssertExpectations(t)
+			d.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_Vacation_invalidDate(t *testing.T) {
+	for _, until := range []string{"", "demain", "2022-01-17", "01/02/2022"} {
+		d := &discordMock{}
+		d.On("SendMessage", i18n.T(i18n.French, "usage.vacation")).Return(&discord.Message{}, nil).Once()
+
+		b := Bot{store: &storeMock{}, discord: d}
+		b = setupBot(t, b)
+		b.Vacation(context.Background(), VacationConfig{AuthorID: testDiscordUserID, Until: until})
+
+		d.AssertExpectations(t)
+	}
+}
@@ -64,10 +64,60 @@ func (s *storeMock) RemoveRemind(_ context.Context, id string) error {
 	return s.Called(id).Error(0)
 }
 
+func (s *storeMock) CreateRemindEvent(_ context.Context, event store.RemindEvent) error {
+	return s.Called(event).Error(0)
+}
+
+func (s *storeMock) ListRemindEvents(_ context.Context, remindID string, skip, limit int) ([]store.RemindEvent, error) {
+	ret := s.Called(remindID, skip, limit)
+
+	return ret.Get(0).([]store.RemindEvent), ret.Error(1)
+}
+
+func (s *storeMock) CreateRemindMessage(_ context.Context, msg store.RemindMessage) error {
+	return s.Called(msg).Error(0)
+}
+
+func (s *storeMock) GetRemindMessage(_ context.Context, messageID string) (store.RemindMessage, error) {
+	ret := s.Called(messageID)
+
+	return ret.Get(0).(store.RemindMessage), ret.Error(1)
+}
+
+func (s *storeMock) GetUserSettings(_ context.Context, userID string) (store.UserSettings, error) {
+	ret := s.Called(userID)
+
+	return ret.Get(0).(store.UserSettings), ret.Error(1)
+}
+
+func (s *storeMock) UpsertUserSettings(_ context.Context, settings store.UserSettings) error {
+	return s.Called(settings).Error(0)
+}
+
+func (s *storeMock) GetGuildSettings(_ context.Context, guildID string) (store.GuildSettings, error) {
+	ret := s.Called(guildID)
+
+	return ret.Get(0).(store.GuildSettings), ret.Error(1)
+}
+
+func (s *storeMock) UpsertGuildSettings(_ context.Context, settings store.GuildSettings) error {
+	return s.Called(settings).Error(0)
+}
+
+func eventMatcher(typ store.EventType, actorID string) interface{} {
+	return mock.MatchedBy(func(e store.RemindEvent) bool {
+		return e.Type == typ && e.ActorID == actorID
+	})
+}
+
 type reminderMock struct {
 	mock.Mock
 }
 
-func (r *reminderMock) SetUpdate() {
-	r.Called()
+func (r *reminderMock) Upsert(remind store.Remind) {
+	r.Called(remind)
+}
+
+func (r *reminderMock) Remove(id store.ID) {
+	r.Called(id)
 }
@@ -0,0 +1,52 @@
+package clock
+
+import "time"
+
+// Clock is capable of telling the time and creating timers.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer represents a single event timer, like time.Timer.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+type realClock struct{}
+
+// New creates a Clock using the system time.
+func New() Clock {
+	return realClock{}
+}
+
+// Now returns the current time.
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// NewTimer creates a new Timer firing after the given duration.
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{timer: time.NewTimer(d)}
+}
+
+type realTimer struct {
+	timer *time.Timer
+}
+
+// C returns the channel on which the time is delivered.
+func (t realTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+// Stop prevents the Timer from firing.
+func (t realTimer) Stop() bool {
+	return t.timer.Stop()
+}
+
+// Reset changes the timer to expire after the given duration.
+func (t realTimer) Reset(d time.Duration) bool {
+	return t.timer.Reset(d)
+}
@@ -0,0 +1,133 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake is a Clock which only moves when told to, for tests.
+type Fake struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// NewFake creates a Fake clock set at the given time.
+func NewFake(now time.Time) *Fake {
+	f := &Fake{now: now}
+	f.cond = sync.NewCond(&f.mu)
+
+	return f
+}
+
+// Now returns the current fake time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+// NewTimer creates a new Timer firing once the fake time has advanced by the given duration.
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	t := &fakeTimer{clock: f, c: make(chan time.Time, 1)}
+
+	f.mu.Lock()
+	f.timers = append(f.timers, t)
+	f.mu.Unlock()
+
+	t.Reset(d)
+
+	return t
+}
+
+// Advance moves the fake time forward, and fires the timers which expired.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = f.now.Add(d)
+
+	for _, t := range f.timers {
+		if t.active && !t.deadline.After(f.now) {
+			t.fire(f.now)
+		}
+	}
+
+	f.cond.Broadcast()
+}
+
+// BlockUntil blocks until n timers are waiting to fire.
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for f.active() < n {
+		f.cond.Wait()
+	}
+}
+
+func (f *Fake) active() int {
+	var n int
+
+	for _, t := range f.timers {
+		if t.active {
+			n++
+		}
+	}
+
+	return n
+}
+
+type fakeTimer struct {
+	clock    *Fake
+	c        chan time.Time
+	deadline time.Time
+	active   bool
+}
+
+// C returns the channel on which the time is delivered.
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+// Stop prevents the Timer from firing.
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	wasActive := t.active
+	t.active = false
+	t.clock.cond.Broadcast()
+
+	return wasActive
+}
+
+// Reset changes the timer to expire after the given duration.
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	wasActive := t.active
+	t.deadline = t.clock.now.Add(d)
+	t.active = true
+
+	if d <= 0 {
+		t.fire(t.clock.now)
+	}
+
+	t.clock.cond.Broadcast()
+
+	return wasActive
+}
+
+// fire must be called with the clock lock held.
+func (t *fakeTimer) fire(now time.Time) {
+	t.active = false
+
+	select {
+	case t.c <- now:
+	default:
+	}
+}
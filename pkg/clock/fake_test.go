@@ -0,0 +1,60 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFake_Advance(t *testing.T) {
+	start := time.Date(2022, 1, 18, 10, 0, 0, 0, time.UTC)
+	f := NewFake(start)
+
+	timer := f.NewTimer(time.Hour)
+
+	f.Advance(59 * time.Minute)
+	assert.Equal(t, start.Add(59*time.Minute), f.Now())
+	assert.Empty(t, timer.C())
+
+	f.Advance(time.Minute)
+	require.Len(t, timer.C(), 1)
+	assert.Equal(t, start.Add(time.Hour), <-timer.C())
+	assert.False(t, timer.Stop())
+}
+
+func TestFake_Reset(t *testing.T) {
+	f := NewFake(time.Date(2022, 1, 18, 10, 0, 0, 0, time.UTC))
+
+	timer := f.NewTimer(time.Hour)
+	assert.True(t, timer.Reset(2*time.Hour))
+
+	f.Advance(time.Hour)
+	assert.Empty(t, timer.C())
+
+	assert.True(t, timer.Stop())
+	f.Advance(time.Hour)
+	assert.Empty(t, timer.C())
+
+	assert.False(t, timer.Reset(0))
+	assert.Len(t, timer.C(), 1)
+}
+
+func TestFake_BlockUntil(t *testing.T) {
+	f := NewFake(time.Date(2022, 1, 18, 10, 0, 0, 0, time.UTC))
+
+	done := make(chan struct{})
+	go func() {
+		f.BlockUntil(1)
+		close(done)
+	}()
+
+	f.NewTimer(time.Hour)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("BlockUntil did not return")
+	}
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/youkoulayley/pet-reminder-bot/pkg/i18n"
+)
+
+// DiscordAPIURL is the base URL of the Discord REST API.
+const DiscordAPIURL = "https://discord.com/api/v10"
+
+// Application command option types.
+const (
+	optionTypeString = 3
+)
+
+// discordLocales maps the locales of the bot to the locales of the Discord clients.
+var discordLocales = map[i18n.Locale][]string{
+	i18n.French:  {"fr"},
+	i18n.English: {"en-US", "en-GB"},
+}
+
+// ApplicationCommand represents a slash command definition.
+// The description is in the default locale, and in the locale of the Discord client when it is supported.
+type ApplicationCommand struct {
+	Name                     string                     `json:"name"`
+	Description              string                     `json:"description"`
+	DescriptionLocalizations map[string]string          `json:"description_localizations,omitempty"`
+	Options                  []ApplicationCommandOption `json:"options,omitempty"`
+}
+
+// ApplicationCommandOption represents an option of a slash command.
+type ApplicationCommandOption struct {
+	Type                     int               `json:"type"`
+	Name                     string            `json:"name"`
+	Description              string            `json:"description"`
+	DescriptionLocalizations map[string]string `json:"description_localizations,omitempty"`
+	Required                 bool              `json:"required,omitempty"`
+	Autocomplete             bool              `json:"autocomplete,omitempty"`
+}
+
+// Commands returns the slash commands handled by the Interactions handler.
+func Commands() []ApplicationCommand {
+	return []ApplicationCommand{
+		{
+			Name:                     "remind",
+			Description:              i18n.T(i18n.Default, "command.remind"),
+			DescriptionLocalizations: localizations("command.remind"),
+			Options: []ApplicationCommandOption{
+				{
+					Type:                     optionTypeString,
+					Name:                     "familier",
+					Description:              i18n.T(i18n.Default, "command.remind.pet"),
+					DescriptionLocalizations: localizations("command.remind.pet"),
+					Required:                 true,
+					Autocomplete:             true,
+				},
+				{
+					Type:                     optionTypeString,
+					Name:                     "personnage",
+					Description:              i18n.T(i18n.Default, "command.remind.character"),
+					DescriptionLocalizations: localizations("command.remind.character"),
+					Required:                 true,
+				},
+			},
+		},
+		{
+			Name:                     "list",
+			Description:              i18n.T(i18n.Default, "command.list"),
+			DescriptionLocalizations: localizations("command.list"),
+		},
+		{
+			Name:                     "remove",
+			Description:              i18n.T(i18n.Default, "command.remove"),
+			DescriptionLocalizations: localizations("command.remove"),
+			Options: []ApplicationCommandOption{
+				{
+					Type:                     optionTypeString,
+					Name:                     "id",
+					Description:              i18n.T(i18n.Default, "command.remove.id"),
+					DescriptionLocalizations: localizations("command.remove.id"),
+					Required:                 true,
+				},
+			},
+		},
+		{
+			Name:                     "familiers",
+			Description:              i18n.T(i18n.Default, "command.pets"),
+			DescriptionLocalizations: localizations("command.pets"),
+		},
+		{
+			Name:                     "help",
+			Description:              i18n.T(i18n.Default, "command.help"),
+			DescriptionLocalizations: localizations("command.help"),
+		},
+	}
+}
+
+// localizations returns the message of the given key for each Discord locale supported by the bot.
+func localizations(key string) map[string]string {
+	localized := make(map[string]string)
+
+	for _, l := range i18n.Locales() {
+		for _, discordLocale := range discordLocales[l] {
+			localized[discordLocale] = i18n.T(l, key)
+		}
+	}
+
+	return localized
+}
+
+// RegisterCommands registers the slash commands of the application, replacing the existing ones.
+func RegisterCommands(ctx context.Context, client *http.Client, baseURL, applicationID, token string) error {
+	body, err := json.Marshal(Commands())
+	if err != nil {
+		return fmt.Errorf("marshal commands: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/applications/%s/commands", baseURL, applicationID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bot "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("register commands: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+
+		return fmt.Errorf("register commands: unexpected status %d: %s", resp.StatusCode, msg)
+	}
+
+	return nil
+}
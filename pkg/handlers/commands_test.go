@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterCommands(t *testing.T) {
+	var got []ApplicationCommand
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/applications/42/commands", r.URL.Path)
+		assert.Equal(t, "Bot token", r.Header.Get("Authorization"))
+
+		err := json.NewDecoder(r.Body).Decode(&got)
+		require.NoError(t, err)
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	err := RegisterCommands(context.Background(), srv.Client(), srv.URL, "42", "token")
+	require.NoError(t, err)
+
+	assert.Equal(t, Commands(), got)
+}
+
+func TestCommands_localized(t *testing.T) {
+	for _, cmd := range Commands() {
+		assert.Equal(t, cmd.Description, cmd.DescriptionLocalizations["fr"], cmd.Name)
+		assert.NotEqual(t, cmd.Description, cmd.DescriptionLocalizations["en-US"], cmd.Name)
+		assert.Equal(t, cmd.DescriptionLocalizations["en-US"], cmd.DescriptionLocalizations["en-GB"], cmd.Name)
+
+		for _, opt := range cmd.Options {
+			assert.Equal(t, opt.Description, opt.DescriptionLocalizations["fr"], cmd.Name, opt.Name)
+			assert.NotEqual(t, opt.Description, opt.DescriptionLocalizations["en-US"], cmd.Name, opt.Name)
+		}
+	}
+
+	assert.Equal(t, "List your reminders", Commands()[1].DescriptionLocalizations["en-US"])
+}
+
+func TestRegisterCommands_error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	err := RegisterCommands(context.Background(), srv.Client(), srv.URL, "42", "token")
+	assert.Error(t, err)
+}
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skwair/harmony/discord"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/i18n"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/outbox"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/store"
+)
+
+// SnoozeDuration is the duration a reminder is snoozed for with its button.
+const SnoozeDuration = 30 * time.Minute
+
+// maxRateLimitRetries is how many times a request rate limited by Discord is retried.
+const maxRateLimitRetries = 3
+
+// Message component types and styles.
+const (
+	componentTypeActionRow = 1
+	componentTypeButton    = 2
+
+	buttonStylePrimary   = 1
+	buttonStyleSecondary = 2
+	buttonStyleDanger    = 4
+)
+
+// Button actions, the custom ID of a button is "<action>:<remind ID>".
+const (
+	actionFed    = "fed"
+	actionSnooze = "snooze"
+	actionRemove = "remove"
+)
+
+type component struct {
+	Type       int         `json:"type"`
+	Style      int         `json:"style,omitempty"`
+	Label      string      `json:"label,omitempty"`
+	CustomID   string      `json:"custom_id,omitempty"`
+	Components []component `json:"components,omitempty"`
+}
+
+// remindButtons returns the buttons attached to the messages about the remind, labelled in the locale of its owner.
+func remindButtons(id store.ID, l i18n.Locale) []component {
+	return []component{
+		{
+			Type: componentTypeActionRow,
+			Components: []component{
+				{Type: componentTypeButton, Style: buttonStylePrimary, Label: i18n.T(l, "button.fed"), CustomID: actionFed + ":" + id.String()},
+				{Type: componentTypeButton, Style: buttonStyleSecondary, Label: i18n.T(l, "button.snooze"), CustomID: actionSnooze + ":" + id.String()},
+				{Type: componentTypeButton, Style: buttonStyleDanger, Label: i18n.T(l, "button.remove"), CustomID: actionRemove + ":" + id.String()},
+			},
+		},
+	}
+}
+
+// parseCustomID returns the action and the remind ID of a button.
+func parseCustomID(customID string) (string, string, error) {
+	parts := strings.SplitN(customID, ":", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("invalid custom id %q", customID)
+	}
+
+	switch parts[0] {
+	case actionFed, actionSnooze, actionRemove:
+		return parts[0], parts[1], nil
+	default:
+		return "", "", fmt.Errorf("unknown action %q", parts[0])
+	}
+}
+
+// ChannelSender sends messages to a channel through harmony,
+// and the messages about a remind through the Discord REST API, as harmony cannot attach buttons to them.
+type ChannelSender struct {
+	outbox.Discord
+
+	client    *http.Client
+	baseURL   string
+	token     string
+	channelID string
+}
+
+// NewChannelSender creates a new ChannelSender, d being the harmony resource of the channel.
+func NewChannelSender(d outbox.Discord, client *http.Client, baseURL, token, channelID string) *ChannelSender {
+	return &ChannelSender{
+		Discord:   d,
+		client:    client,
+		baseURL:   baseURL,
+		token:     token,
+		channelID: channelID,
+	}
+}
+
+type createMessage struct {
+	Content    string      `json:"content"`
+	Components []component `json:"components,omitempty"`
+}
+
+// SendRemindMessage sends a message about the remind to the channel, with the buttons acting on it.
+func (s *ChannelSender) SendRemindMessage(ctx context.Context, remindID store.ID, l i18n.Locale, text string) (*discord.Message, error) {
+	return s.send(ctx, createMessage{Content: text, Components: remindButtons(remindID, l)})
+}
+
+func (s *ChannelSender) send(ctx context.Context, msg createMessage) (*discord.Message, error) {
+	return sendMessage(ctx, s.client, s.baseURL, s.token, s.channelID, msg)
+}
+
+// DMOpener opens the direct message channels with the users, like the harmony resource of the bot user.
+type DMOpener interface {
+	NewDM(ctx context.Context, recipientID string) (*discord.Channel, error)
+}
+
+// DirectSender sends direct messages to the users, in the channels opened with them through harmony.
+type DirectSender struct {
+	dms      DMOpener
+	channels func(channelID string) outbox.Discord
+}
+
+// NewDirectSender creates a new DirectSender.
+// The messages are sent to the direct message channels returned by channels,
+// so the buttons acting on the reminds are attached when those channels are able to.
+func NewDirectSender(dms DMOpener, channels func(channelID string) outbox.Discord) *DirectSender {
+	return &DirectSender{
+		dms:      dms,
+		channels: channels,
+	}
+}
+
+// SendDirectMessage sends a message to the user, in the direct message channel opened with them.
+// It returns a *discord.APIError when Discord refuses the message, for instance when the user does not accept direct messages.
+func (s *DirectSender) SendDirectMessage(ctx context.Context, userID string, remindID store.ID, l i18n.Locale, text string) (*discord.Message, error) {
+	dm, err := s.dms.NewDM(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("open direct message channel: %w", err)
+	}
+
+	d := s.channels(dm.ID)
+	if rd, ok := d.(outbox.RemindDiscord); ok && remindID != "" {
+		return rd.SendRemindMessage(ctx, remindID, l, text)
+	}
+
+	return d.SendMessage(ctx, text)
+}
+
+// sendMessage sends a message to the given channel.
+func sendMessage(ctx context.Context, client *http.Client, baseURL, token, channelID string, msg createMessage) (*discord.Message, error) {
+	if msg.Content == "" {
+		return nil, errors.New("empty message")
+	}
+
+	var sent discord.Message
+	if err := post(ctx, client, fmt.Sprintf("%s/channels/%s/messages", baseURL, channelID), token, msg, &sent); err != nil {
+		return nil, fmt.Errorf("send message: %w", err)
+	}
+
+	return &sent, nil
+}
+
+// post sends the JSON encoded body to the Discord REST API, and decodes the response into out.
+// Requests rate limited by Discord are retried after the delay it asks for, up to maxRateLimitRetries times.
+func post(ctx context.Context, client *http.Client, url, token string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal body: %w", err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		wait, err := postOnce(ctx, client, url, token, data, out)
+		if wait == 0 || attempt == maxRateLimitRetries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// postOnce sends the request once, and returns how long to wait before retrying it when it has been rate limited.
+func postOnce(ctx context.Context, client *http.Client, url, token string, data []byte, out interface{}) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return 0, fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bot "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("do request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return retryAfter(resp.Header), discord.NewAPIError(resp)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return 0, discord.NewAPIError(resp)
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return 0, fmt.Errorf("decode response: %w", err)
+	}
+
+	return 0, nil
+}
+
+// retryAfter returns how long Discord asks to wait before retrying a rate limited request.
+func retryAfter(h http.Header) time.Duration {
+	seconds, err := strconv.ParseFloat(h.Get("Retry-After"), 64)
+	if err != nil || seconds <= 0 {
+		return time.Second
+	}
+
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/skwair/harmony/discord"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/i18n"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/outbox"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/store"
+)
+
+func TestParseCustomID(t *testing.T) {
+	tests := []struct {
+		desc       string
+		customID   string
+		wantAction string
+		wantID     string
+		wantErr    bool
+	}{
+		{
+			desc:       "fed",
+			customID:   "fed:61dac053b64a65b3de7650d3",
+			wantAction: actionFed,
+			wantID:     "61dac053b64a65b3de7650d3",
+		},
+		{
+			desc:       "snooze",
+			customID:   "snooze:61dac053b64a65b3de7650d3",
+			wantAction: actionSnooze,
+			wantID:     "61dac053b64a65b3de7650d3",
+		},
+		{
+			desc:       "remove",
+			customID:   "remove:61dac053b64a65b3de7650d3",
+			wantAction: actionRemove,
+			wantID:     "61dac053b64a65b3de7650d3",
+		},
+		{
+			desc:     "unknown action",
+			customID: "pouet:61dac053b64a65b3de7650d3",
+			wantErr:  true,
+		},
+		{
+			desc:     "no id",
+			customID: "fed",
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			action, id, err := parseCustomID(test.customID)
+			if test.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.wantAction, action)
+			assert.Equal(t, test.wantID, id)
+		})
+	}
+}
+
+func TestRemindButtons(t *testing.T) {
+	id := store.NewID()
+
+	labels := func(l i18n.Locale) []string {
+		var got []string
+		for _, button := range remindButtons(id, l)[0].Components {
+			got = append(got, button.Label)
+		}
+
+		return got
+	}
+
+	assert.Equal(t, []string{"Nourri", "Repousser", "Stop"}, labels(i18n.French))
+	assert.Equal(t, []string{"Fed", "Snooze", "Stop"}, labels(i18n.English))
+}
+
+func TestChannelSender_SendRemindMessage(t *testing.T) {
+	var got createMessage
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/channels/42/messages", r.URL.Path)
+		assert.Equal(t, "Bot token", r.Header.Get("Authorization"))
+
+		got = createMessage{}
+		err := json.NewDecoder(r.Body).Decode(&got)
+		require.NoError(t, err)
+
+		_, _ = w.Write([]byte(`{"id":"123","channel_id":"42","content":"hello"}`))
+	}))
+	defer srv.Close()
+
+	s := NewChannelSender(&discordMock{}, srv.Client(), srv.URL, "token", "42")
+
+	id := store.NewID()
+
+	msg, err := s.SendRemindMessage(context.Background(), id, i18n.English, "hello")
+	require.NoError(t, err)
+
+	assert.Equal(t, "123", msg.ID)
+	assert.Equal(t, createMessage{Content: "hello", Components: remindButtons(id, i18n.English)}, got)
+}
+
+func TestChannelSender_SendMessage(t *testing.T) {
+	d := &discordMock{}
+	d.On("SendMessage", "hello").Return(&discord.Message{ID: "123"}, nil).Once()
+
+	s := NewChannelSender(d, http.DefaultClient, "http://localhost", "token", "42")
+
+	msg, err := s.SendMessage(context.Background(), "hello")
+	require.NoError(t, err)
+
+	assert.Equal(t, "123", msg.ID)
+
+	d.AssertExpectations(t)
+}
+
+func TestChannelSender_SendRemindMessage_error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	s := NewChannelSender(&discordMock{}, srv.Client(), srv.URL, "token", "42")
+
+	_, err := s.SendRemindMessage(context.Background(), store.NewID(), i18n.French, "hello")
+	assert.Error(t, err)
+
+	_, err = s.SendRemindMessage(context.Background(), store.NewID(), i18n.French, "")
+	assert.Error(t, err)
+}
+
+func TestChannelSender_SendRemindMessage_rateLimited(t *testing.T) {
+	var calls int
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0.01")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"message":"You are being rate limited.","retry_after":0.01,"global":false}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"id":"123","channel_id":"42","content":"hello"}`))
+	}))
+	defer srv.Close()
+
+	s := NewChannelSender(&discordMock{}, srv.Client(), srv.URL, "token", "42")
+
+	msg, err := s.SendRemindMessage(context.Background(), store.NewID(), i18n.French, "hello")
+	require.NoError(t, err)
+
+	assert.Equal(t, "123", msg.ID)
+	assert.Equal(t, 2, calls)
+}
+
+func TestChannelSender_SendRemindMessage_rateLimitedTooLong(t *testing.T) {
+	var calls int
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+
+		w.Header().Set("Retry-After", "0.01")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"message":"You are being rate limited.","retry_after":0.01,"global":false}`))
+	}))
+	defer srv.Close()
+
+	s := NewChannelSender(&discordMock{}, srv.Client(), srv.URL, "token", "42")
+
+	_, err := s.SendRemindMessage(context.Background(), store.NewID(), i18n.French, "hello")
+
+	var apiErr *discord.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusTooManyRequests, apiErr.HTTPCode)
+	assert.Equal(t, maxRateLimitRetries+1, calls)
+}
+
+func TestDirectSender_SendDirectMessage(t *testing.T) {
+	id := store.NewID()
+
+	dms := &dmOpenerMock{}
+	dms.On("NewDM", "1").Return(&discord.Channel{ID: "42"}, nil).Times(3)
+
+	d := &discordMock{}
+	d.On("SendMessage", "hello").Return(&discord.Message{ID: "123"}, nil).Twice()
+
+	s := NewDirectSender(dms, func(channelID string) outbox.Discord {
+		assert.Equal(t, "42", channelID)
+
+		return d
+	})
+
+	msg, err := s.SendDirectMessage(context.Background(), "1", id, i18n.French, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "123", msg.ID)
+
+	_, err = s.SendDirectMessage(context.Background(), "1", "", i18n.French, "hello")
+	require.NoError(t, err)
+
+	rd := &remindDiscordMock{}
+	rd.On("SendRemindMessage", id, i18n.French, "hello").Return(&discord.Message{ID: "124"}, nil).Once()
+
+	s = NewDirectSender(dms, func(string) outbox.Discord { return rd })
+
+	msg, err = s.SendDirectMessage(context.Background(), "1", id, i18n.French, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "124", msg.ID)
+
+	dms.AssertExpectations(t)
+	d.AssertExpectations(t)
+	rd.AssertExpectations(t)
+}
+
+func TestDirectSender_SendDirectMessage_closed(t *testing.T) {
+	dms := &dmOpenerMock{}
+	dms.On("NewDM", "1").Return(&discord.Channel{ID: "42"}, nil).Once()
+
+	d := &discordMock{}
+	d.On("SendMessage", "hello").Return((*discord.Message)(nil), &discord.APIError{HTTPCode: http.StatusForbidden, Code: 50007}).Once()
+
+	s := NewDirectSender(dms, func(string) outbox.Discord { return d })
+
+	_, err := s.SendDirectMessage(context.Background(), "1", "", i18n.French, "hello")
+
+	var apiErr *discord.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 50007, apiErr.Code)
+
+	dms.AssertExpectations(t)
+	d.AssertExpectations(t)
+}
+
+func TestDirectSender_SendDirectMessage_newDMError(t *testing.T) {
+	dms := &dmOpenerMock{}
+	dms.On("NewDM", "1").Return((*discord.Channel)(nil), errors.New("boom")).Once()
+
+	s := NewDirectSender(dms, func(string) outbox.Discord {
+		t.Fatal("unexpected channel")
+
+		return nil
+	})
+
+	_, err := s.SendDirectMessage(context.Background(), "1", "", i18n.French, "hello")
+	assert.Error(t, err)
+
+	dms.AssertExpectations(t)
+}
@@ -3,24 +3,44 @@ package handlers
 import (
 	"context"
 
+	"github.com/rs/zerolog/log"
 	"github.com/skwair/harmony/discord"
 	"github.com/youkoulayley/pet-reminder-bot/pkg/bot"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/i18n"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/store"
 )
 
 // Handler represents a Discord Handler.
 type Handler struct {
-	bot     Bot
-	botUser discord.User
+	newBot   func(channelID string, l i18n.Locale) Bot
+	guilds   Guilds
+	settings Settings
+	botUser  discord.User
 }
 
 // New creates a new Handler.
-func New(b Bot, bu discord.User) Handler {
+// newBot creates a bot sending its messages to the given channel in the given locale,
+// it is used to reply in the channel of each command, in the language of its author.
+func New(newBot func(channelID string, l i18n.Locale) Bot, g Guilds, s Settings, bu discord.User) Handler {
 	return Handler{
-		bot:     b,
-		botUser: bu,
+		newBot:   newBot,
+		guilds:   g,
+		settings: s,
+		botUser:  bu,
 	}
 }
 
+// Settings is capable of getting the settings of the users and of the guilds.
+type Settings interface {
+	GetUserSettings(ctx context.Context, userID string) (store.UserSettings, error)
+	GetGuildSettings(ctx context.Context, guildID string) (store.GuildSettings, error)
+}
+
+// Guilds is capable of getting the Discord guilds.
+type Guilds interface {
+	Guild(ctx context.Context, id string) (*discord.Guild, error)
+}
+
 // Bot is capable of interacting with the bot.
 type Bot interface {
 	ListPets(ctx context.Context)
@@ -28,5 +48,44 @@ type Bot interface {
 	Remind(ctx context.Context, cfg bot.RemindConfig)
 	RemoveRemind(ctx context.Context, cfg bot.RemoveRemindConfig)
 	Help(ctx context.Context)
+	Usage(ctx context.Context, command string)
 	NewCycle(ctx context.Context, cfg bot.NewCycleConfig)
+	Snooze(ctx context.Context, cfg bot.SnoozeConfig)
+	Edit(ctx context.Context, cfg bot.EditConfig)
+	Pause(ctx context.Context, cfg bot.PauseConfig)
+	Resume(ctx context.Context, cfg bot.ResumeConfig)
+	Vacation(ctx context.Context, cfg bot.VacationConfig)
+	History(ctx context.Context, cfg bot.HistoryConfig)
+	SetNotify(ctx context.Context, cfg bot.NotifyConfig)
+	SetTimezone(ctx context.Context, cfg bot.TimezoneConfig)
+	SetWarnings(ctx context.Context, cfg bot.WarningsConfig)
+	SetLanguage(ctx context.Context, cfg bot.LanguageConfig)
+	SetupChannel(ctx context.Context, cfg bot.SetupConfig)
+	SetupLanguage(ctx context.Context, cfg bot.SetupConfig)
+}
+
+// resolveLocale returns the locale to reply to the user in: the one they have chosen,
+// else the one chosen for the guild, else the first supported fallback.
+func resolveLocale(ctx context.Context, s Settings, userID, guildID string, fallbacks ...string) i18n.Locale {
+	var candidates []string
+
+	if userID != "" {
+		settings, err := s.GetUserSettings(ctx, userID)
+		if err != nil {
+			log.Error().Err(err).Str("user", userID).Msg("Unable to get user settings")
+		}
+
+		candidates = append(candidates, settings.Locale)
+	}
+
+	if guildID != "" {
+		settings, err := s.GetGuildSettings(ctx, guildID)
+		if err != nil {
+			log.Error().Err(err).Str("guild", guildID).Msg("Unable to get guild settings")
+		}
+
+		candidates = append(candidates, settings.Locale)
+	}
+
+	return i18n.Resolve(append(candidates, fallbacks...)...)
 }
@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/skwair/harmony/discord"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/bot"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/i18n"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/store"
+)
+
+// Interaction types.
+const (
+	interactionTypePing                           = 1
+	interactionTypeApplicationCommand             = 2
+	interactionTypeMessageComponent               = 3
+	interactionTypeApplicationCommandAutocomplete = 4
+)
+
+// Interaction response types.
+const (
+	responseTypePong                                 = 1
+	responseTypeChannelMessageWithSource             = 4
+	responseTypeApplicationCommandAutocompleteResult = 8
+)
+
+const (
+	messageFlagEphemeral = 1 << 6
+
+	// interactionTimeout is the delay Discord waits for the response of an interaction.
+	interactionTimeout = 3 * time.Second
+	maxBodySize        = 1 << 20
+	maxChoices         = 25
+)
+
+// PetLister is capable of listing the pets.
+type PetLister interface {
+	ListPets(ctx context.Context) (store.Pets, error)
+}
+
+// Interactions handles the Discord interactions, received over HTTP.
+type Interactions struct {
+	newBot    func(d bot.Discord, l i18n.Locale) Bot
+	discord   bot.Discord
+	pets      PetLister
+	settings  Settings
+	publicKey ed25519.PublicKey
+}
+
+// NewInteractions creates a new Interactions handler.
+// newBot creates a bot sending its messages with the given Discord in the given locale, it is used to reply to the interactions.
+func NewInteractions(newBot func(d bot.Discord, l i18n.Locale) Bot, d bot.Discord, p PetLister, s Settings, publicKey ed25519.PublicKey) *Interactions {
+	return &Interactions{
+		newBot:    newBot,
+		discord:   d,
+		pets:      p,
+		settings:  s,
+		publicKey: publicKey,
+	}
+}
+
+type interaction struct {
+	Type        int             `json:"type"`
+	GuildID     string          `json:"guild_id"`
+	ChannelID   string          `json:"channel_id"`
+	Data        interactionData `json:"data"`
+	Locale      string          `json:"locale"`
+	GuildLocale string          `json:"guild_locale"`
+	Member      *struct {
+		User discord.User `json:"user"`
+	} `json:"member"`
+	User *discord.User `json:"user"`
+}
+
+// authorID returns the user who triggered the interaction, in a guild or in DM.
+func (in interaction) authorID() string {
+	if in.Member != nil {
+		return in.Member.User.ID
+	}
+
+	if in.User != nil {
+		return in.User.ID
+	}
+
+	return ""
+}
+
+type interactionData struct {
+	Name     string              `json:"name"`
+	Options  []interactionOption `json:"options"`
+	CustomID string              `json:"custom_id"`
+}
+
+func (d interactionData) option(name string) string {
+	for _, opt := range d.Options {
+		if opt.Name == name {
+			return opt.Value
+		}
+	}
+
+	return ""
+}
+
+type interactionOption struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Focused bool   `json:"focused"`
+}
+
+type interactionResponse struct {
+	Type int         `json:"type"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+type messageData struct {
+	Content string `json:"content"`
+	Flags   int    `json:"flags,omitempty"`
+}
+
+type autocompleteData struct {
+	Choices []choice `json:"choices"`
+}
+
+type choice struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ServeHTTP handles an interaction sent by Discord.
+func (i *Interactions) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		log.Error().Err(err).Msg("Unable to read interaction")
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	if !i.verify(r.Header, body) {
+		log.Debug().Msg("Invalid interaction signature")
+		w.WriteHeader(http.StatusUnauthorized)
+
+		return
+	}
+
+	var in interaction
+	if err = json.Unmarshal(body, &in); err != nil {
+		log.Error().Err(err).Msg("Unable to decode interaction")
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), interactionTimeout)
+	defer cancel()
+
+	var resp interactionResponse
+
+	switch in.Type {
+	case interactionTypePing:
+		resp = interactionResponse{Type: responseTypePong}
+	case interactionTypeApplicationCommand:
+		resp = i.handleCommand(ctx, in)
+	case interactionTypeApplicationCommandAutocomplete:
+		resp = i.handleAutocomplete(ctx, in)
+	case interactionTypeMessageComponent:
+		resp = i.handleComponent(ctx, in)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		log.Error().Err(err).Msg("Unable to write interaction response")
+	}
+}
+
+// verify ensures the interaction has been signed by Discord.
+func (i *Interactions) verify(header http.Header, body []byte) bool {
+	signature, err := hex.DecodeString(header.Get("X-Signature-Ed25519"))
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return false
+	}
+
+	msg := append([]byte(header.Get("X-Signature-Timestamp")), body...)
+
+	return ed25519.Verify(i.publicKey, msg, signature)
+}
+
+// handleCommand routes the slash command to the bot, and replies with the messages it sent.
+// Only the remind confirmation is visible to everyone.
+func (i *Interactions) handleCommand(ctx context.Context, in interaction) interactionResponse {
+	authorID := in.authorID()
+	locale := resolveLocale(ctx, i.settings, authorID, in.GuildID, in.Locale, in.GuildLocale)
+
+	reply := &interactionReply{Discord: i.discord, locale: locale}
+	b := i.newBot(reply, locale)
+
+	ephemeral := true
+
+	switch in.Data.Name {
+	case "familiers":
+		b.ListPets(ctx)
+	case "list":
+		b.ListReminds(ctx, authorID)
+	case "remind":
+		ephemeral = false
+
+		b.Remind(ctx, bot.RemindConfig{
+			AuthorID:  authorID,
+			GuildID:   in.GuildID,
+			ChannelID: in.ChannelID,
+			Pet:       in.Data.option("familier"),
+			Character: in.Data.option("personnage"),
+		})
+	case "remove":
+		b.RemoveRemind(ctx, bot.RemoveRemindConfig{
+			AuthorID: authorID,
+			ID:       in.Data.option("id"),
+		})
+	default:
+		b.Help(ctx)
+	}
+
+	return reply.response(ephemeral)
+}
+
+// handleComponent routes the button clicked on a remind message to the bot, and replies with the messages it sent.
+// The replies are only visible to the user who clicked, so other users are told privately they don't own the remind.
+func (i *Interactions) handleComponent(ctx context.Context, in interaction) interactionResponse {
+	authorID := in.authorID()
+	locale := resolveLocale(ctx, i.settings, authorID, in.GuildID, in.Locale, in.GuildLocale)
+
+	reply := &interactionReply{Discord: i.discord, locale: locale}
+
+	action, id, err := parseCustomID(in.Data.CustomID)
+	if err != nil {
+		log.Debug().Err(err).Msg("Unknown component")
+
+		return reply.response(true)
+	}
+
+	b := i.newBot(reply, locale)
+
+	switch action {
+	case actionFed:
+		b.NewCycle(ctx, bot.NewCycleConfig{AuthorID: authorID, ID: id})
+	case actionSnooze:
+		b.Snooze(ctx, bot.SnoozeConfig{AuthorID: authorID, ID: id, Duration: SnoozeDuration})
+	case actionRemove:
+		b.RemoveRemind(ctx, bot.RemoveRemindConfig{AuthorID: authorID, ID: id})
+	}
+
+	return reply.response(true)
+}
+
+// handleAutocomplete suggests the pets whose name or one of its aliases starts with the value typed by the user.
+func (i *Interactions) handleAutocomplete(ctx context.Context, in interaction) interactionResponse {
+	var value string
+
+	for _, opt := range in.Data.Options {
+		if opt.Focused {
+			value = opt.Value
+		}
+	}
+
+	choices := []choice{}
+
+	pets, err := i.pets.ListPets(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Unable to list pets")
+	}
+
+	for _, pet := range pets.StartingWith(value, maxChoices) {
+		choices = append(choices, choice{Name: pet.Name, Value: pet.Name})
+	}
+
+	return interactionResponse{
+		Type: responseTypeApplicationCommandAutocompleteResult,
+		Data: autocompleteData{Choices: choices},
+	}
+}
+
+// interactionReply collects the messages sent by the bot, to send them as the interaction response.
+type interactionReply struct {
+	bot.Discord
+
+	locale   i18n.Locale
+	messages []string
+}
+
+func (r *interactionReply) SendMessage(_ context.Context, text string) (*discord.Message, error) {
+	r.messages = append(r.messages, text)
+
+	return &discord.Message{}, nil
+}
+
+// response returns the interaction response holding the collected messages.
+func (r *interactionReply) response(ephemeral bool) interactionResponse {
+	data := messageData{Content: strings.Join(r.messages, "\n")}
+	if data.Content == "" {
+		data.Content = i18n.T(r.locale, "interaction.error")
+		ephemeral = true
+	}
+
+	if ephemeral {
+		data.Flags = messageFlagEphemeral
+	}
+
+	return interactionResponse{Type: responseTypeChannelMessageWithSource, Data: data}
+}
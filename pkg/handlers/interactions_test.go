@@ -0,0 +1,365 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/bot"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/i18n"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/store"
+)
+
+func TestInteractions_ServeHTTP_ping(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	i := NewInteractions(nil, nil, nil, nil, publicKey)
+
+	rec := serveInteraction(t, i, privateKey, `{"type":1}`)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"type":1}`, rec.Body.String())
+}
+
+func TestInteractions_ServeHTTP_invalidSignature(t *testing.T) {
+	publicKey, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	_, otherKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	i := NewInteractions(nil, nil, nil, nil, publicKey)
+
+	rec := serveInteraction(t, i, otherKey, `{"type":1}`)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"type":1}`))
+	rec = httptest.NewRecorder()
+	i.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestInteractions_ServeHTTP_command(t *testing.T) {
+	tests := []struct {
+		desc        string
+		interaction string
+		setup       func(b *botMock, reply func(string))
+		wantContent string
+		wantFlags   int
+	}{
+		{
+			desc:        "remind",
+			interaction: `{"type":2,"guild_id":"5","channel_id":"6","member":{"user":{"id":"3"}},"data":{"name":"remind","options":[{"name":"familier","type":3,"value":"Chacha"},{"name":"personnage","type":3,"value":"Toto"}]}}`,
+			setup: func(b *botMock, reply func(string)) {
+				b.On("Remind", bot.RemindConfig{AuthorID: "3", GuildID: "5", ChannelID: "6", Pet: "Chacha", Character: "Toto"}).
+					Run(func(mock.Arguments) { reply("Rappel activé") }).
+					Once()
+			},
+			wantContent: "Rappel activé",
+		},
+		{
+			desc:        "list in DM",
+			interaction: `{"type":2,"user":{"id":"4"},"data":{"name":"list"}}`,
+			setup: func(b *botMock, reply func(string)) {
+				b.On("ListReminds", "4").
+					Run(func(mock.Arguments) { reply("Liste de vos rappels:") }).
+					Once()
+			},
+			wantContent: "Liste de vos rappels:",
+			wantFlags:   messageFlagEphemeral,
+		},
+		{
+			desc:        "remove",
+			interaction: `{"type":2,"member":{"user":{"id":"3"}},"data":{"name":"remove","options":[{"name":"id","type":3,"value":"61dac053b64a65b3de7650d3"}]}}`,
+			setup: func(b *botMock, reply func(string)) {
+				b.On("RemoveRemind", bot.RemoveRemindConfig{AuthorID: "3", ID: "61dac053b64a65b3de7650d3"}).
+					Run(func(mock.Arguments) { reply("Rappel supprimé") }).
+					Once()
+			},
+			wantContent: "Rappel supprimé",
+			wantFlags:   messageFlagEphemeral,
+		},
+		{
+			desc:        "familiers",
+			interaction: `{"type":2,"member":{"user":{"id":"3"}},"data":{"name":"familiers"}}`,
+			setup: func(b *botMock, reply func(string)) {
+				b.On("ListPets").
+					Run(func(mock.Arguments) {
+						reply("Chacha")
+						reply("Nomoon")
+					}).
+					Once()
+			},
+			wantContent: "Chacha\nNomoon",
+			wantFlags:   messageFlagEphemeral,
+		},
+		{
+			desc:        "help",
+			interaction: `{"type":2,"member":{"user":{"id":"3"}},"data":{"name":"help"}}`,
+			setup: func(b *botMock, reply func(string)) {
+				b.On("Help").
+					Run(func(mock.Arguments) { reply("Commandes disponible:") }).
+					Once()
+			},
+			wantContent: "Commandes disponible:",
+			wantFlags:   messageFlagEphemeral,
+		},
+		{
+			desc:        "no reply",
+			interaction: `{"type":2,"member":{"user":{"id":"3"}},"data":{"name":"remind"}}`,
+			setup: func(b *botMock, _ func(string)) {
+				b.On("Remind", bot.RemindConfig{AuthorID: "3"}).Once()
+			},
+			wantContent: i18n.T(i18n.French, "interaction.error"),
+			wantFlags:   messageFlagEphemeral,
+		},
+		{
+			desc:        "no reply in the client locale",
+			interaction: `{"type":2,"locale":"en-US","member":{"user":{"id":"3"}},"data":{"name":"remind"}}`,
+			setup: func(b *botMock, _ func(string)) {
+				b.On("Remind", bot.RemindConfig{AuthorID: "3"}).Once()
+			},
+			wantContent: "An error occurred, please try again.",
+			wantFlags:   messageFlagEphemeral,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			publicKey, privateKey, err := ed25519.GenerateKey(nil)
+			require.NoError(t, err)
+
+			b := &botMock{}
+
+			var d bot.Discord
+
+			newBot := func(reply bot.Discord, _ i18n.Locale) Bot {
+				d = reply
+
+				return b
+			}
+
+			test.setup(b, func(text string) {
+				_, err := d.SendMessage(context.Background(), text)
+				require.NoError(t, err)
+			})
+
+			i := NewInteractions(newBot, nil, nil, defaultSettings(), publicKey)
+
+			rec := serveInteraction(t, i, privateKey, test.interaction)
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			var got struct {
+				Type int `json:"type"`
+				Data struct {
+					Content string `json:"content"`
+					Flags   int    `json:"flags"`
+				} `json:"data"`
+			}
+			err = json.Unmarshal(rec.Body.Bytes(), &got)
+			require.NoError(t, err)
+
+			assert.Equal(t, responseTypeChannelMessageWithSource, got.Type)
+			assert.Equal(t, test.wantContent, got.Data.Content)
+			assert.Equal(t, test.wantFlags, got.Data.Flags)
+
+			b.AssertExpectations(t)
+		})
+	}
+}
+
+func TestInteractions_ServeHTTP_component(t *testing.T) {
+	tests := []struct {
+		desc        string
+		customID    string
+		setup       func(b *botMock, reply func(string))
+		wantContent string
+	}{
+		{
+			desc:     "fed",
+			customID: "fed:61dac053b64a65b3de7650d3",
+			setup: func(b *botMock, reply func(string)) {
+				b.On("NewCycle", bot.NewCycleConfig{AuthorID: "3", ID: "61dac053b64a65b3de7650d3"}).
+					Run(func(mock.Arguments) { reply("nourri") }).
+					Once()
+			},
+			wantContent: "nourri",
+		},
+		{
+			desc:     "fed by another user",
+			customID: "fed:61dac053b64a65b3de7650d3",
+			setup: func(b *botMock, reply func(string)) {
+				b.On("NewCycle", bot.NewCycleConfig{AuthorID: "3", ID: "61dac053b64a65b3de7650d3"}).
+					Run(func(mock.Arguments) { reply("Vous ne pouvez pas nourrir") }).
+					Once()
+			},
+			wantContent: "Vous ne pouvez pas nourrir",
+		},
+		{
+			desc:     "snooze",
+			customID: "snooze:61dac053b64a65b3de7650d3",
+			setup: func(b *botMock, reply func(string)) {
+				b.On("Snooze", bot.SnoozeConfig{AuthorID: "3", ID: "61dac053b64a65b3de7650d3", Duration: SnoozeDuration}).
+					Run(func(mock.Arguments) { reply("repoussé") }).
+					Once()
+			},
+			wantContent: "repoussé",
+		},
+		{
+			desc:     "remove",
+			customID: "remove:61dac053b64a65b3de7650d3",
+			setup: func(b *botMock, reply func(string)) {
+				b.On("RemoveRemind", bot.RemoveRemindConfig{AuthorID: "3", ID: "61dac053b64a65b3de7650d3"}).
+					Run(func(mock.Arguments) { reply("supprimé") }).
+					Once()
+			},
+			wantContent: "supprimé",
+		},
+		{
+			desc:        "unknown button",
+			customID:    "pouet",
+			setup:       func(*botMock, func(string)) {},
+			wantContent: i18n.T(i18n.French, "interaction.error"),
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			publicKey, privateKey, err := ed25519.GenerateKey(nil)
+			require.NoError(t, err)
+
+			b := &botMock{}
+
+			var d bot.Discord
+
+			newBot := func(reply bot.Discord, _ i18n.Locale) Bot {
+				d = reply
+
+				return b
+			}
+
+			test.setup(b, func(text string) {
+				_, err := d.SendMessage(context.Background(), text)
+				require.NoError(t, err)
+			})
+
+			i := NewInteractions(newBot, nil, nil, defaultSettings(), publicKey)
+
+			interaction := `{"type":3,"member":{"user":{"id":"3"}},"data":{"custom_id":"` + test.customID + `","component_type":2}}`
+
+			rec := serveInteraction(t, i, privateKey, interaction)
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			want, err := json.Marshal(messageData{Content: test.wantContent, Flags: messageFlagEphemeral})
+			require.NoError(t, err)
+
+			assert.JSONEq(t, `{"type":4,"data":`+string(want)+`}`, rec.Body.String())
+
+			b.AssertExpectations(t)
+		})
+	}
+}
+
+func TestInteractions_ServeHTTP_autocomplete(t *testing.T) {
+	tests := []struct {
+		desc        string
+		value       string
+		pets        store.Pets
+		err         error
+		wantChoices string
+	}{
+		{
+			desc:        "prefix",
+			value:       "ch",
+			pets:        store.Pets{{Name: "Chacha"}, {Name: "Nomoon"}, {Name: "Chachanoir"}},
+			wantChoices: `[{"name":"Chacha","value":"Chacha"},{"name":"Chachanoir","value":"Chachanoir"}]`,
+		},
+		{
+			desc:        "separators and case",
+			value:       "dragoune rose",
+			pets:        store.Pets{{Name: "Chacha"}, {Name: "Dragoune_Rose"}},
+			wantChoices: `[{"name":"Dragoune_Rose","value":"Dragoune_Rose"}]`,
+		},
+		{
+			desc:        "name with separators",
+			value:       "Koalak",
+			pets:        store.Pets{{Name: "Koalak_Sanguin", Aliases: []string{"Koalak"}}, {Name: "Nomoon"}},
+			wantChoices: `[{"name":"Koalak_Sanguin","value":"Koalak_Sanguin"}]`,
+		},
+		{
+			desc:        "alias",
+			value:       "corb",
+			pets:        store.Pets{{Name: "Chacha"}, {Name: "Vilain_Petit_Corbac", Aliases: []string{"Corbac"}}},
+			wantChoices: `[{"name":"Vilain_Petit_Corbac","value":"Vilain_Petit_Corbac"}]`,
+		},
+		{
+			desc:        "no match",
+			value:       "zz",
+			pets:        store.Pets{{Name: "Chacha"}},
+			wantChoices: `[]`,
+		},
+		{
+			desc:        "store error",
+			value:       "ch",
+			pets:        store.Pets(nil),
+			err:         errors.New("boom"),
+			wantChoices: `[]`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			publicKey, privateKey, err := ed25519.GenerateKey(nil)
+			require.NoError(t, err)
+
+			p := &petListerMock{}
+			p.On("ListPets").Return(test.pets, test.err).Once()
+
+			i := NewInteractions(nil, nil, p, nil, publicKey)
+
+			interaction := `{"type":4,"member":{"user":{"id":"3"}},"data":{"name":"remind","options":[{"name":"familier","type":3,"value":"` + test.value + `","focused":true}]}}`
+
+			rec := serveInteraction(t, i, privateKey, interaction)
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			assert.JSONEq(t, `{"type":8,"data":{"choices":`+test.wantChoices+`}}`, rec.Body.String())
+
+			p.AssertExpectations(t)
+		})
+	}
+}
+
+func serveInteraction(t *testing.T, i *Interactions, key ed25519.PrivateKey, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	timestamp := "1642500000"
+	signature := ed25519.Sign(key, []byte(timestamp+body))
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+	req.Header.Set("X-Signature-Ed25519", hex.EncodeToString(signature))
+	req.Header.Set("X-Signature-Timestamp", timestamp)
+
+	rec := httptest.NewRecorder()
+	i.ServeHTTP(rec, req)
+
+	return rec
+}
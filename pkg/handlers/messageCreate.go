@@ -3,14 +3,19 @@ package handlers
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/skwair/harmony/discord"
 	"github.com/youkoulayley/pet-reminder-bot/pkg/bot"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/i18n"
 )
 
+const defaultHistoryLimit = 10
+
 // MessageCreate gets all message created.
 // All messages send by the bot are ignored.
 func (h *Handler) MessageCreate(m *discord.Message) {
@@ -23,68 +28,236 @@ func (h *Handler) MessageCreate(m *discord.Message) {
 		return
 	}
 
-	switch {
-	case strings.HasPrefix(m.Content, "!familiers"):
-		h.bot.ListPets(ctx)
-	case strings.HasPrefix(m.Content, "!list"):
-		h.bot.ListReminds(ctx, m.Author.ID)
-	case strings.HasPrefix(m.Content, "!remind"):
-		cfg, err := h.handleRemindConfig(m)
+	cmd, err := parseCommand(m.Content)
+	if errors.Is(err, errNotACommand) {
+		return
+	}
+
+	b := h.newBot(m.ChannelID, resolveLocale(ctx, h.settings, m.Author.ID, m.GuildID))
+
+	if err != nil {
+		log.Debug().Err(err).Str("command", cmd.name).Msg("Invalid command")
+		b.Usage(ctx, cmd.name)
+
+		return
+	}
+
+	switch cmd.name {
+	case "pets":
+		b.ListPets(ctx)
+	case "list":
+		b.ListReminds(ctx, m.Author.ID)
+	case "remind":
+		b.Remind(ctx, bot.RemindConfig{
+			AuthorID:  m.Author.ID,
+			GuildID:   m.GuildID,
+			ChannelID: m.ChannelID,
+			Pet:       cmd.args[0],
+			Character: cmd.args[1],
+			FedAt:     optionalArg(cmd.args, 2),
+		})
+	case "fed":
+		b.NewCycle(ctx, bot.NewCycleConfig{AuthorID: m.Author.ID, ID: cmd.args[0], FedAt: optionalArg(cmd.args, 1)})
+	case "remove":
+		b.RemoveRemind(ctx, bot.RemoveRemindConfig{AuthorID: m.Author.ID, ID: cmd.args[0]})
+	case "edit":
+		cfg, err := handleEditConfig(m, cmd.args)
 		if err != nil {
-			h.bot.Help(ctx)
+			b.Usage(ctx, cmd.name)
 
 			return
 		}
 
-		h.bot.Remind(ctx, cfg)
-	case strings.HasPrefix(m.Content, "!remove"):
-		cfg, err := h.handleRemoveRemindConfig(m)
+		b.Edit(ctx, cfg)
+	case "snooze":
+		duration, err := bot.ParseDuration(cmd.args[1])
 		if err != nil {
-			h.bot.Help(ctx)
+			b.Usage(ctx, cmd.name)
 
 			return
 		}
 
-		h.bot.RemoveRemind(ctx, cfg)
-	case strings.HasPrefix(m.Content, "!help"):
-		h.bot.Help(ctx)
-	default:
+		b.Snooze(ctx, bot.SnoozeConfig{AuthorID: m.Author.ID, ID: cmd.args[0], Duration: duration})
+	case "pause":
+		b.Pause(ctx, bot.PauseConfig{AuthorID: m.Author.ID, ID: cmd.args[0]})
+	case "resume":
+		b.Resume(ctx, bot.ResumeConfig{AuthorID: m.Author.ID, ID: cmd.args[0]})
+	case "vacation":
+		if keyword, _ := i18n.Keyword(cmd.args[0]); keyword != "until" {
+			b.Usage(ctx, cmd.name)
+
+			return
+		}
+
+		b.Vacation(ctx, bot.VacationConfig{AuthorID: m.Author.ID, Until: cmd.args[1]})
+	case "history":
+		cfg, err := handleHistoryConfig(m, cmd.args)
+		if err != nil {
+			b.Usage(ctx, cmd.name)
+
+			return
+		}
+
+		b.History(ctx, cfg)
+	case "notify":
+		b.SetNotify(ctx, bot.NotifyConfig{AuthorID: m.Author.ID, Mode: cmd.args[0]})
+	case "timezone":
+		b.SetTimezone(ctx, bot.TimezoneConfig{AuthorID: m.Author.ID, Timezone: cmd.args[0]})
+	case "warn":
+		cfg, err := handleWarningsConfig(m, cmd.args)
+		if err != nil {
+			b.Usage(ctx, cmd.name)
+
+			return
+		}
+
+		b.SetWarnings(ctx, cfg)
+	case "language":
+		b.SetLanguage(ctx, bot.LanguageConfig{AuthorID: m.Author.ID, Language: cmd.args[0]})
+	case "setup":
+		cfg, err := handleSetupConfig(m, cmd.args)
+		if err != nil {
+			b.Usage(ctx, cmd.name)
+
+			return
+		}
+
+		if cfg.GuildID != "" {
+			guild, err := h.guilds.Guild(ctx, m.GuildID)
+			if err != nil {
+				log.Error().Err(err).Msg("Unable to get guild")
+				b.Help(ctx)
+
+				return
+			}
+
+			cfg.Admin = isAdmin(guild, m.Author.ID, m.Member.Roles)
+		}
+
+		if cfg.Language != "" {
+			b.SetupLanguage(ctx, cfg)
+
+			return
+		}
+
+		b.SetupChannel(ctx, cfg)
+	case "help":
+		b.Help(ctx)
 	}
 }
 
-func (h *Handler) handleRemindConfig(m *discord.Message) (bot.RemindConfig, error) {
-	parts := strings.Split(m.Content, " ")
-	if len(parts) != 3 {
-		return bot.RemindConfig{}, errors.New("command invalid")
-	}
+// handleEditConfig parses `!edit <ID> pet=<Pet> character=<Character>`, each field being given at most once.
+func handleEditConfig(m *discord.Message, args []string) (bot.EditConfig, error) {
+	cfg := bot.EditConfig{AuthorID: m.Author.ID, ID: args[0]}
+
+	for _, arg := range args[1:] {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok || value == "" {
+			return bot.EditConfig{}, fmt.Errorf("invalid change %q", arg)
+		}
 
-	pet := parts[1]
-	character := parts[2]
+		switch field, _ := i18n.Keyword(strings.ToLower(name)); field {
+		case "pet":
+			if cfg.Pet != "" {
+				return bot.EditConfig{}, errors.New("pet given twice")
+			}
 
-	if pet == "" || character == "" {
-		return bot.RemindConfig{}, errors.New("pet or character missing")
+			cfg.Pet = value
+		case "character":
+			if cfg.Character != "" {
+				return bot.EditConfig{}, errors.New("character given twice")
+			}
+
+			cfg.Character = value
+		default:
+			return bot.EditConfig{}, fmt.Errorf("unknown field %q", name)
+		}
 	}
 
-	return bot.RemindConfig{
-		AuthorID:  m.Author.ID,
-		Pet:       pet,
-		Character: character,
-	}, nil
+	return cfg, nil
 }
 
-func (h *Handler) handleRemoveRemindConfig(m *discord.Message) (bot.RemoveRemindConfig, error) {
-	parts := strings.Split(m.Content, " ")
-	if len(parts) != 2 {
-		return bot.RemoveRemindConfig{}, errors.New("command invalid")
+// optionalArg returns the argument at the given index, or an empty string when it has not been given.
+func optionalArg(args []string, i int) string {
+	if i < len(args) {
+		return args[i]
 	}
 
-	id := parts[1]
-	if id == "" {
-		return bot.RemoveRemindConfig{}, errors.New("id is missing")
+	return ""
+}
+
+func handleHistoryConfig(m *discord.Message, args []string) (bot.HistoryConfig, error) {
+	limit := defaultHistoryLimit
+	if len(args) == 2 {
+		var err error
+		if limit, err = strconv.Atoi(args[1]); err != nil {
+			return bot.HistoryConfig{}, fmt.Errorf("parse limit: %w", err)
+		}
 	}
 
-	return bot.RemoveRemindConfig{
+	return bot.HistoryConfig{
 		AuthorID: m.Author.ID,
-		ID:       id,
+		ID:       args[0],
+		Limit:    limit,
 	}, nil
 }
+
+// handleWarningsConfig parses `!warn <Duration>...`, and `!warn off` which disables the warnings.
+func handleWarningsConfig(m *discord.Message, args []string) (bot.WarningsConfig, error) {
+	cfg := bot.WarningsConfig{AuthorID: m.Author.ID}
+	if len(args) == 1 && args[0] == "off" {
+		return cfg, nil
+	}
+
+	for _, arg := range args {
+		warning, err := bot.ParseDuration(arg)
+		if err != nil {
+			return bot.WarningsConfig{}, err
+		}
+
+		cfg.Warnings = append(cfg.Warnings, warning)
+	}
+
+	return cfg, nil
+}
+
+// handleSetupConfig parses `!setup channel` and `!setup language <Language>`, in any language, the language is only set
+// by the latter.
+func handleSetupConfig(m *discord.Message, args []string) (bot.SetupConfig, error) {
+	cfg := bot.SetupConfig{
+		AuthorID:  m.Author.ID,
+		GuildID:   m.GuildID,
+		ChannelID: m.ChannelID,
+	}
+
+	switch setting, _ := i18n.Keyword(args[0]); {
+	case setting == "channel" && len(args) == 1:
+	case setting == "language" && len(args) == 2:
+		cfg.Language = args[1]
+	default:
+		return bot.SetupConfig{}, fmt.Errorf("unknown setting %q", args[0])
+	}
+
+	return cfg, nil
+}
+
+// isAdmin reports whether the user can configure the guild: its owner, or a member allowed to manage it.
+func isAdmin(guild *discord.Guild, userID string, roleIDs []string) bool {
+	if guild.OwnerID == userID {
+		return true
+	}
+
+	member := discord.GuildMember{Roles: roleIDs}
+
+	var permissions int
+
+	for _, role := range guild.Roles {
+		// The @everyone role has the ID of the guild.
+		if role.ID == guild.ID || member.HasRole(role.ID) {
+			permissions |= role.Permissions
+		}
+	}
+
+	return discord.PermissionsContains(permissions, discord.PermissionAdministrator) ||
+		discord.PermissionsContains(permissions, discord.PermissionManageGuild)
+}
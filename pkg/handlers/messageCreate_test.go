@@ -1,17 +1,23 @@
 package handlers
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/skwair/harmony/discord"
+	"github.com/stretchr/testify/assert"
 	"github.com/youkoulayley/pet-reminder-bot/pkg/bot"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/i18n"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/store"
 )
 
 func TestHandler_MessageCreate_botMessage(t *testing.T) {
 	b := &botMock{}
 	h := Handler{
-		bot:     b,
-		botUser: discord.User{ID: "3"},
+		newBot:   botFactory(b),
+		settings: defaultSettings(),
+		botUser:  discord.User{ID: "3"},
 	}
 
 	msg := &discord.Message{Content: "!help", Author: discord.User{ID: "3"}}
@@ -23,12 +29,16 @@ func TestHandler_MessageCreate_botMessage(t *testing.T) {
 func TestHandler_MessageCreate_unknownCommand(t *testing.T) {
 	b := &botMock{}
 	h := Handler{
-		bot:     b,
-		botUser: discord.User{ID: "2"},
+		newBot:   botFactory(b),
+		settings: defaultSettings(),
+		botUser:  discord.User{ID: "2"},
 	}
 
-	msg := &discord.Message{Content: "!pouet", Author: discord.User{ID: "3"}}
-	h.MessageCreate(msg)
+	// Commands are matched by their exact name.
+	for _, content := range []string{"!pouet", "!listfoo", "list", "!"} {
+		msg := &discord.Message{Content: content, Author: discord.User{ID: "3"}}
+		h.MessageCreate(msg)
+	}
 
 	b.AssertExpectations(t)
 }
@@ -38,8 +48,9 @@ func TestHandler_MessageCreate_listRemindsCommand(t *testing.T) {
 	b.On("ListReminds", "3").Once()
 
 	h := Handler{
-		bot:     b,
-		botUser: discord.User{ID: "2"},
+		newBot:   botFactory(b),
+		settings: defaultSettings(),
+		botUser:  discord.User{ID: "2"},
 	}
 
 	msg := &discord.Message{Content: "!list", Author: discord.User{ID: "3"}}
@@ -49,18 +60,48 @@ func TestHandler_MessageCreate_listRemindsCommand(t *testing.T) {
 }
 
 func TestHandler_MessageCreate_listPetsCommand(t *testing.T) {
+	// The commands can be given in any language.
+	for _, command := range []string{"!familiers", "!pets"} {
+		b := &botMock{}
+		b.On("ListPets").Once()
+
+		h := Handler{
+			newBot:   botFactory(b),
+			settings: defaultSettings(),
+			botUser:  discord.User{ID: "2"},
+		}
+
+		msg := &discord.Message{Content: command, Author: discord.User{ID: "3"}}
+		h.MessageCreate(msg)
+
+		b.AssertExpectations(t)
+	}
+}
+
+func TestHandler_MessageCreate_userLocale(t *testing.T) {
 	b := &botMock{}
-	b.On("ListPets").Once()
+	b.On("Help").Once()
+
+	settings := &settingsMock{}
+	settings.On("GetUserSettings", "3").Return(store.UserSettings{UserID: "3", Locale: "en"}, nil).Once()
+	settings.On("GetGuildSettings", "4").Return(store.GuildSettings{GuildID: "4", Locale: "fr"}, nil).Once()
 
 	h := Handler{
-		bot:     b,
-		botUser: discord.User{ID: "2"},
+		newBot: func(_ string, l i18n.Locale) Bot {
+			// The language chosen by the user prevails over the one of the guild.
+			assert.Equal(t, i18n.English, l)
+
+			return b
+		},
+		settings: settings,
+		botUser:  discord.User{ID: "2"},
 	}
 
-	msg := &discord.Message{Content: "!familiers", Author: discord.User{ID: "3"}}
+	msg := &discord.Message{Content: "!aide", Author: discord.User{ID: "3"}, GuildID: "4"}
 	h.MessageCreate(msg)
 
 	b.AssertExpectations(t)
+	settings.AssertExpectations(t)
 }
 
 func TestHandler_MessageCreate_helpCommand(t *testing.T) {
@@ -68,8 +109,9 @@ func TestHandler_MessageCreate_helpCommand(t *testing.T) {
 	b.On("Help").Once()
 
 	h := Handler{
-		bot:     b,
-		botUser: discord.User{ID: "2"},
+		newBot:   botFactory(b),
+		settings: defaultSettings(),
+		botUser:  discord.User{ID: "2"},
 	}
 
 	msg := &discord.Message{Content: "!help", Author: discord.User{ID: "3"}}
@@ -95,6 +137,14 @@ func TestHandler_MessageCreate_remindCommand_validation(t *testing.T) {
 			desc:    "character empty",
 			command: "!remind Chacha ",
 		},
+		{
+			desc:    "too many arguments",
+			command: "!remind Chacha Mon Perso 2h",
+		},
+		{
+			desc:    "unterminated quote",
+			command: `!remind Chacha "Mon Perso`,
+		},
 	}
 
 	for _, test := range tests {
@@ -103,11 +153,12 @@ func TestHandler_MessageCreate_remindCommand_validation(t *testing.T) {
 			t.Parallel()
 
 			b := &botMock{}
-			b.On("Help").Once()
+			b.On("Usage", "remind").Once()
 
 			h := Handler{
-				bot:     b,
-				botUser: discord.User{ID: "2"},
+				newBot:   botFactory(b),
+				settings: defaultSettings(),
+				botUser:  discord.User{ID: "2"},
 			}
 
 			msg := &discord.Message{Content: test.command, Author: discord.User{ID: "3"}}
@@ -122,16 +173,113 @@ func TestHandler_MessageCreate_remindCommand(t *testing.T) {
 	b := &botMock{}
 	b.On("Remind", bot.RemindConfig{
 		AuthorID:  "3",
+		GuildID:   "4",
+		ChannelID: "5",
 		Pet:       "Chacha",
 		Character: "Toto",
 	}).Once()
 
+	settings := &settingsMock{}
+	settings.On("GetUserSettings", "3").Return(store.UserSettings{UserID: "3"}, nil).Once()
+	settings.On("GetGuildSettings", "4").Return(store.GuildSettings{GuildID: "4", Locale: "en"}, nil).Once()
+
+	h := Handler{
+		newBot: func(channelID string, l i18n.Locale) Bot {
+			// The bot replies in the channel of the command, in the language of the guild.
+			assert.Equal(t, "5", channelID)
+			assert.Equal(t, i18n.English, l)
+
+			return b
+		},
+		settings: settings,
+		botUser:  discord.User{ID: "2"},
+	}
+
+	msg := &discord.Message{Content: "!remind Chacha Toto", Author: discord.User{ID: "3"}, GuildID: "4", ChannelID: "5"}
+	h.MessageCreate(msg)
+
+	b.AssertExpectations(t)
+}
+
+func TestHandler_MessageCreate_remindCommand_quoted(t *testing.T) {
+	b := &botMock{}
+	b.On("Remind", bot.RemindConfig{AuthorID: "3", Pet: "Chacha", Character: "Mon Perso"}).Once()
+
 	h := Handler{
-		bot:     b,
-		botUser: discord.User{ID: "2"},
+		newBot:   botFactory(b),
+		settings: defaultSettings(),
+		botUser:  discord.User{ID: "2"},
 	}
 
-	msg := &discord.Message{Content: "!remind Chacha Toto", Author: discord.User{ID: "3"}}
+	msg := &discord.Message{Content: ` !remind   Chacha  "Mon Perso" `, Author: discord.User{ID: "3"}}
+	h.MessageCreate(msg)
+
+	b.AssertExpectations(t)
+}
+
+func TestHandler_MessageCreate_fedCommand(t *testing.T) {
+	tests := []struct {
+		desc    string
+		command string
+		cfg     *bot.NewCycleConfig
+	}{
+		{
+			desc:    "id missing",
+			command: "!fed",
+		},
+		{
+			desc:    "too many arguments",
+			command: "!fed 123 2h 14:30",
+		},
+		{
+			desc:    "fed now",
+			command: "!fed 123",
+			cfg:     &bot.NewCycleConfig{AuthorID: "3", ID: "123"},
+		},
+		{
+			desc:    "fed ago",
+			command: "!nourri 123 2h",
+			cfg:     &bot.NewCycleConfig{AuthorID: "3", ID: "123", FedAt: "2h"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			b := &botMock{}
+			if test.cfg != nil {
+				b.On("NewCycle", *test.cfg).Once()
+			} else {
+				b.On("Usage", "fed").Once()
+			}
+
+			h := Handler{
+				newBot:   botFactory(b),
+				settings: defaultSettings(),
+				botUser:  discord.User{ID: "2"},
+			}
+
+			msg := &discord.Message{Content: test.command, Author: discord.User{ID: "3"}}
+			h.MessageCreate(msg)
+
+			b.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_MessageCreate_remindCommand_fedAt(t *testing.T) {
+	b := &botMock{}
+	b.On("Remind", bot.RemindConfig{AuthorID: "3", Pet: "Chacha", Character: "Toto", FedAt: "14:30"}).Once()
+
+	h := Handler{
+		newBot:   botFactory(b),
+		settings: defaultSettings(),
+		botUser:  discord.User{ID: "2"},
+	}
+
+	msg := &discord.Message{Content: "!remind Chacha Toto 14:30", Author: discord.User{ID: "3"}}
 	h.MessageCreate(msg)
 
 	b.AssertExpectations(t)
@@ -158,11 +306,12 @@ func TestHandler_MessageCreate_removeCommand_validation(t *testing.T) {
 			t.Parallel()
 
 			b := &botMock{}
-			b.On("Help").Once()
+			b.On("Usage", "remove").Once()
 
 			h := Handler{
-				bot:     b,
-				botUser: discord.User{ID: "2"},
+				newBot:   botFactory(b),
+				settings: defaultSettings(),
+				botUser:  discord.User{ID: "2"},
 			}
 
 			msg := &discord.Message{Content: test.command, Author: discord.User{ID: "3"}}
@@ -181,8 +330,9 @@ func TestHandler_MessageCreate_removeCommand(t *testing.T) {
 	}).Once()
 
 	h := Handler{
-		bot:     b,
-		botUser: discord.User{ID: "2"},
+		newBot:   botFactory(b),
+		settings: defaultSettings(),
+		botUser:  discord.User{ID: "2"},
 	}
 
 	msg := &discord.Message{Content: "!remove 123", Author: discord.User{ID: "3"}}
@@ -190,3 +340,739 @@ func TestHandler_MessageCreate_removeCommand(t *testing.T) {
 
 	b.AssertExpectations(t)
 }
+
+func TestHandler_MessageCreate_snoozeCommand_validation(t *testing.T) {
+	tests := []struct {
+		desc    string
+		command string
+	}{
+		{
+			desc:    "duration missing",
+			command: "!snooze 123",
+		},
+		{
+			desc:    "duration invalid",
+			command: "!snooze 123 bientôt",
+		},
+		{
+			desc:    "duration unit invalid",
+			command: "!snooze 123 30x",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			b := &botMock{}
+			b.On("Usage", "snooze").Once()
+
+			h := Handler{
+				newBot:   botFactory(b),
+				settings: defaultSettings(),
+				botUser:  discord.User{ID: "2"},
+			}
+
+			msg := &discord.Message{Content: test.command, Author: discord.User{ID: "3"}}
+			h.MessageCreate(msg)
+
+			b.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_MessageCreate_snoozeCommand(t *testing.T) {
+	tests := []struct {
+		desc     string
+		command  string
+		duration time.Duration
+	}{
+		{
+			desc:     "minutes",
+			command:  "!snooze 123 45",
+			duration: 45 * time.Minute,
+		},
+		{
+			desc:     "duration",
+			command:  "!snooze 123 1h30m",
+			duration: 90 * time.Minute,
+		},
+		{
+			desc:     "hours and minutes",
+			command:  "!repousser 123 1h30",
+			duration: 90 * time.Minute,
+		},
+		{
+			desc:     "hours",
+			command:  "!snooze 123 2h",
+			duration: 2 * time.Hour,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			b := &botMock{}
+			b.On("Snooze", bot.SnoozeConfig{
+				AuthorID: "3",
+				ID:       "123",
+				Duration: test.duration,
+			}).Once()
+
+			h := Handler{
+				newBot:   botFactory(b),
+				settings: defaultSettings(),
+				botUser:  discord.User{ID: "2"},
+			}
+
+			msg := &discord.Message{Content: test.command, Author: discord.User{ID: "3"}}
+			h.MessageCreate(msg)
+
+			b.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_MessageCreate_editCommand(t *testing.T) {
+	tests := []struct {
+		desc    string
+		command string
+		cfg     bot.EditConfig
+	}{
+		{
+			desc:    "pet",
+			command: "!edit 123 pet=Chacha",
+			cfg:     bot.EditConfig{AuthorID: "3", ID: "123", Pet: "Chacha"},
+		},
+		{
+			desc:    "character with spaces",
+			command: `!edit 123 character="Mon Personnage"`,
+			cfg:     bot.EditConfig{AuthorID: "3", ID: "123", Character: "Mon Personnage"},
+		},
+		{
+			desc:    "pet and character in french",
+			command: "!modifier 123 personnage=Toto Familier=Nomoon",
+			cfg:     bot.EditConfig{AuthorID: "3", ID: "123", Pet: "Nomoon", Character: "Toto"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			b := &botMock{}
+			b.On("Edit", test.cfg).Once()
+
+			h := Handler{
+				newBot:   botFactory(b),
+				settings: defaultSettings(),
+				botUser:  discord.User{ID: "2"},
+			}
+
+			msg := &discord.Message{Content: test.command, Author: discord.User{ID: "3"}}
+			h.MessageCreate(msg)
+
+			b.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_MessageCreate_editCommand_validation(t *testing.T) {
+	tests := []struct {
+		desc    string
+		command string
+	}{
+		{
+			desc:    "change missing",
+			command: "!edit 123",
+		},
+		{
+			desc:    "not a change",
+			command: "!edit 123 Chacha",
+		},
+		{
+			desc:    "empty value",
+			command: "!edit 123 pet=",
+		},
+		{
+			desc:    "unknown field",
+			command: "!edit 123 level=50",
+		},
+		{
+			desc:    "field given twice",
+			command: "!edit 123 pet=Chacha familier=Nomoon",
+		},
+		{
+			desc:    "too many changes",
+			command: "!edit 123 pet=Chacha character=Toto pet=Nomoon",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			b := &botMock{}
+			b.On("Usage", "edit").Once()
+
+			h := Handler{
+				newBot:   botFactory(b),
+				settings: defaultSettings(),
+				botUser:  discord.User{ID: "2"},
+			}
+
+			msg := &discord.Message{Content: test.command, Author: discord.User{ID: "3"}}
+			h.MessageCreate(msg)
+
+			b.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_MessageCreate_pauseCommand(t *testing.T) {
+	tests := []struct {
+		desc    string
+		command string
+		method  string
+		cfg     interface{}
+		usage   string
+	}{
+		{
+			desc:    "pause",
+			command: "!pause 123",
+			method:  "Pause",
+			cfg:     bot.PauseConfig{AuthorID: "3", ID: "123"},
+		},
+		{
+			desc:    "pause without id",
+			command: "!pause",
+			usage:   "pause",
+		},
+		{
+			desc:    "resume all",
+			command: "!reprendre all",
+			method:  "Resume",
+			cfg:     bot.ResumeConfig{AuthorID: "3", ID: "all"},
+		},
+		{
+			desc:    "resume too many arguments",
+			command: "!resume 123 456",
+			usage:   "resume",
+		},
+		{
+			desc:    "vacation",
+			command: `!vacation until "2022-02-01 18:00"`,
+			method:  "Vacation",
+			cfg:     bot.VacationConfig{AuthorID: "3", Until: "2022-02-01 18:00"},
+		},
+		{
+			desc:    "localized vacation",
+			command: "!vacances jusqu'au 2022-02-01",
+			method:  "Vacation",
+			cfg:     bot.VacationConfig{AuthorID: "3", Until: "2022-02-01"},
+		},
+		{
+			desc:    "vacation without until",
+			command: "!vacation from 2022-02-01",
+			usage:   "vacation",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			b := &botMock{}
+			if test.usage != "" {
+				b.On("Usage", test.usage).Once()
+			} else {
+				b.On(test.method, test.cfg).Once()
+			}
+
+			h := Handler{
+				newBot:   botFactory(b),
+				settings: defaultSettings(),
+				botUser:  discord.User{ID: "2"},
+			}
+
+			msg := &discord.Message{Content: test.command, Author: discord.User{ID: "3"}}
+			h.MessageCreate(msg)
+
+			b.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_MessageCreate_historyCommand_validation(t *testing.T) {
+	tests := []struct {
+		desc    string
+		command string
+	}{
+		{
+			desc:    "command invalid",
+			command: "!history",
+		},
+		{
+			desc:    "id empty",
+			command: "!history ",
+		},
+		{
+			desc:    "limit invalid",
+			command: "!history 123 dix",
+		},
+		{
+			desc:    "too many arguments",
+			command: "!history 123 10 20",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			b := &botMock{}
+			b.On("Usage", "history").Once()
+
+			h := Handler{
+				newBot:   botFactory(b),
+				settings: defaultSettings(),
+				botUser:  discord.User{ID: "2"},
+			}
+
+			msg := &discord.Message{Content: test.command, Author: discord.User{ID: "3"}}
+			h.MessageCreate(msg)
+
+			b.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_MessageCreate_historyCommand(t *testing.T) {
+	tests := []struct {
+		desc    string
+		command string
+		limit   int
+	}{
+		{
+			desc:    "default limit",
+			command: "!history 123",
+			limit:   10,
+		},
+		{
+			desc:    "custom limit",
+			command: "!history 123 5",
+			limit:   5,
+		},
+		{
+			desc:    "localized",
+			command: "!historique 123 5",
+			limit:   5,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			b := &botMock{}
+			b.On("History", bot.HistoryConfig{
+				AuthorID: "3",
+				ID:       "123",
+				Limit:    test.limit,
+			}).Once()
+
+			h := Handler{
+				newBot:   botFactory(b),
+				settings: defaultSettings(),
+				botUser:  discord.User{ID: "2"},
+			}
+
+			msg := &discord.Message{Content: test.command, Author: discord.User{ID: "3"}}
+			h.MessageCreate(msg)
+
+			b.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_MessageCreate_notifyCommand(t *testing.T) {
+	tests := []struct {
+		desc    string
+		command string
+		cfg     *bot.NotifyConfig
+	}{
+		{
+			desc:    "command invalid",
+			command: "!notify",
+		},
+		{
+			desc:    "mode empty",
+			command: "!notify ",
+		},
+		{
+			desc:    "too many arguments",
+			command: "!notify dm channel",
+		},
+		{
+			desc:    "dm",
+			command: "!notify dm",
+			cfg:     &bot.NotifyConfig{AuthorID: "3", Mode: "dm"},
+		},
+		{
+			desc:    "localized",
+			command: "!notifier channel",
+			cfg:     &bot.NotifyConfig{AuthorID: "3", Mode: "channel"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			b := &botMock{}
+			if test.cfg != nil {
+				b.On("SetNotify", *test.cfg).Once()
+			} else {
+				b.On("Usage", "notify").Once()
+			}
+
+			h := Handler{
+				newBot:   botFactory(b),
+				settings: defaultSettings(),
+				botUser:  discord.User{ID: "2"},
+			}
+
+			msg := &discord.Message{Content: test.command, Author: discord.User{ID: "3"}}
+			h.MessageCreate(msg)
+
+			b.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_MessageCreate_timezoneCommand(t *testing.T) {
+	tests := []struct {
+		desc    string
+		command string
+		cfg     *bot.TimezoneConfig
+	}{
+		{
+			desc:    "command invalid",
+			command: "!timezone",
+		},
+		{
+			desc:    "timezone empty",
+			command: "!timezone ",
+		},
+		{
+			desc:    "too many arguments",
+			command: "!timezone Europe/Paris UTC",
+		},
+		{
+			desc:    "timezone",
+			command: "!timezone Europe/Paris",
+			cfg:     &bot.TimezoneConfig{AuthorID: "3", Timezone: "Europe/Paris"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			b := &botMock{}
+			if test.cfg != nil {
+				b.On("SetTimezone", *test.cfg).Once()
+			} else {
+				b.On("Usage", "timezone").Once()
+			}
+
+			h := Handler{
+				newBot:   botFactory(b),
+				settings: defaultSettings(),
+				botUser:  discord.User{ID: "2"},
+			}
+
+			msg := &discord.Message{Content: test.command, Author: discord.User{ID: "3"}}
+			h.MessageCreate(msg)
+
+			b.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_MessageCreate_warnCommand(t *testing.T) {
+	tests := []struct {
+		desc    string
+		command string
+		cfg     *bot.WarningsConfig
+	}{
+		{
+			desc:    "command invalid",
+			command: "!warn",
+		},
+		{
+			desc:    "duration invalid",
+			command: "!warn 1h bientôt",
+		},
+		{
+			desc:    "off with durations",
+			command: "!warn off 1h",
+		},
+		{
+			desc:    "too many durations",
+			command: "!warn 1 2 3 4 5 6",
+		},
+		{
+			desc:    "durations",
+			command: "!alerte 1h 15",
+			cfg:     &bot.WarningsConfig{AuthorID: "3", Warnings: []time.Duration{time.Hour, 15 * time.Minute}},
+		},
+		{
+			desc:    "off",
+			command: "!warn off",
+			cfg:     &bot.WarningsConfig{AuthorID: "3"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			b := &botMock{}
+			if test.cfg != nil {
+				b.On("SetWarnings", *test.cfg).Once()
+			} else {
+				b.On("Usage", "warn").Once()
+			}
+
+			h := Handler{
+				newBot:   botFactory(b),
+				settings: defaultSettings(),
+				botUser:  discord.User{ID: "2"},
+			}
+
+			msg := &discord.Message{Content: test.command, Author: discord.User{ID: "3"}}
+			h.MessageCreate(msg)
+
+			b.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_MessageCreate_languageCommand(t *testing.T) {
+	tests := []struct {
+		desc    string
+		command string
+		cfg     *bot.LanguageConfig
+	}{
+		{
+			desc:    "command invalid",
+			command: "!language",
+		},
+		{
+			desc:    "too many arguments",
+			command: "!language en fr",
+		},
+		{
+			desc:    "language",
+			command: "!language en",
+			cfg:     &bot.LanguageConfig{AuthorID: "3", Language: "en"},
+		},
+		{
+			desc:    "localized command",
+			command: "!langue fr",
+			cfg:     &bot.LanguageConfig{AuthorID: "3", Language: "fr"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			b := &botMock{}
+			if test.cfg != nil {
+				b.On("SetLanguage", *test.cfg).Once()
+			} else {
+				b.On("Usage", "language").Once()
+			}
+
+			h := Handler{
+				newBot:   botFactory(b),
+				settings: defaultSettings(),
+				botUser:  discord.User{ID: "2"},
+			}
+
+			msg := &discord.Message{Content: test.command, Author: discord.User{ID: "3"}}
+			h.MessageCreate(msg)
+
+			b.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_MessageCreate_setupCommand(t *testing.T) {
+	guild := &discord.Guild{
+		ID:      "4",
+		OwnerID: "1",
+		Roles: []discord.Role{
+			{ID: "4", Permissions: discord.PermissionSendMessages},
+			{ID: "admin", Permissions: discord.PermissionManageGuild},
+		},
+	}
+
+	tests := []struct {
+		desc     string
+		command  string
+		guildID  string
+		roles    []string
+		guildErr error
+		cfg      *bot.SetupConfig
+	}{
+		{
+			desc:    "command invalid",
+			command: "!setup",
+			guildID: "4",
+		},
+		{
+			desc:    "unknown setting",
+			command: "!setup pouet",
+			guildID: "4",
+		},
+		{
+			desc:     "get guild error",
+			command:  "!setup channel",
+			guildID:  "4",
+			guildErr: errors.New("boom"),
+		},
+		{
+			desc:    "admin",
+			command: "!setup channel",
+			guildID: "4",
+			roles:   []string{"admin"},
+			cfg:     &bot.SetupConfig{AuthorID: "3", GuildID: "4", ChannelID: "5", Admin: true},
+		},
+		{
+			desc:    "not admin",
+			command: "!setup channel",
+			guildID: "4",
+			cfg:     &bot.SetupConfig{AuthorID: "3", GuildID: "4", ChannelID: "5"},
+		},
+		{
+			desc:    "localized channel",
+			command: "!configurer salon",
+			guildID: "4",
+			roles:   []string{"admin"},
+			cfg:     &bot.SetupConfig{AuthorID: "3", GuildID: "4", ChannelID: "5", Admin: true},
+		},
+		{
+			desc:    "direct message",
+			command: "!setup channel",
+			cfg:     &bot.SetupConfig{AuthorID: "3", ChannelID: "5"},
+		},
+		{
+			desc:    "language missing",
+			command: "!setup language",
+			guildID: "4",
+		},
+		{
+			desc:    "language",
+			command: "!setup language en",
+			guildID: "4",
+			roles:   []string{"admin"},
+			cfg:     &bot.SetupConfig{AuthorID: "3", GuildID: "4", ChannelID: "5", Admin: true, Language: "en"},
+		},
+		{
+			desc:    "localized language",
+			command: "!setup langue fr",
+			guildID: "4",
+			cfg:     &bot.SetupConfig{AuthorID: "3", GuildID: "4", ChannelID: "5", Language: "fr"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			b := &botMock{}
+			switch {
+			case test.cfg != nil && test.cfg.Language != "":
+				b.On("SetupLanguage", *test.cfg).Once()
+			case test.cfg != nil:
+				b.On("SetupChannel", *test.cfg).Once()
+			case test.guildErr != nil:
+				b.On("Help").Once()
+			default:
+				b.On("Usage", "setup").Once()
+			}
+
+			g := &guildsMock{}
+			g.On("Guild", "4").Return(guild, test.guildErr)
+
+			h := Handler{
+				newBot:   botFactory(b),
+				settings: defaultSettings(),
+				guilds:   g,
+				botUser:  discord.User{ID: "2"},
+			}
+
+			msg := &discord.Message{
+				Content:   test.command,
+				Author:    discord.User{ID: "3"},
+				Member:    discord.GuildMember{Roles: test.roles},
+				GuildID:   test.guildID,
+				ChannelID: "5",
+			}
+			h.MessageCreate(msg)
+
+			b.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIsAdmin(t *testing.T) {
+	guild := &discord.Guild{
+		ID:      "guild",
+		OwnerID: "owner",
+		Roles: []discord.Role{
+			{ID: "guild", Permissions: discord.PermissionSendMessages},
+			{ID: "admin", Permissions: discord.PermissionAdministrator},
+			{ID: "manager", Permissions: discord.PermissionManageGuild | discord.PermissionSendMessages},
+			{ID: "moderator", Permissions: discord.PermissionManageMessages},
+		},
+	}
+
+	tests := []struct {
+		desc   string
+		userID string
+		roles  []string
+		want   bool
+	}{
+		{desc: "owner", userID: "owner", want: true},
+		{desc: "administrator", userID: "user", roles: []string{"admin"}, want: true},
+		{desc: "manager", userID: "user", roles: []string{"moderator", "manager"}, want: true},
+		{desc: "moderator", userID: "user", roles: []string{"moderator"}},
+		{desc: "member", userID: "user"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.want, isAdmin(guild, test.userID, test.roles))
+		})
+	}
+}
@@ -3,8 +3,11 @@ package handlers
 import (
 	"context"
 
+	"github.com/skwair/harmony/discord"
 	"github.com/stretchr/testify/mock"
 	"github.com/youkoulayley/pet-reminder-bot/pkg/bot"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/i18n"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/store"
 )
 
 type botMock struct {
@@ -31,6 +34,139 @@ func (b *botMock) Help(_ context.Context) {
 	b.Called()
 }
 
+func (b *botMock) Usage(_ context.Context, command string) {
+	b.Called(command)
+}
+
 func (b *botMock) NewCycle(_ context.Context, cfg bot.NewCycleConfig) {
 	b.Called(cfg)
 }
+
+func (b *botMock) Snooze(_ context.Context, cfg bot.SnoozeConfig) {
+	b.Called(cfg)
+}
+
+func (b *botMock) History(_ context.Context, cfg bot.HistoryConfig) {
+	b.Called(cfg)
+}
+
+func (b *botMock) SetNotify(_ context.Context, cfg bot.NotifyConfig) {
+	b.Called(cfg)
+}
+
+func (b *botMock) Edit(_ context.Context, cfg bot.EditConfig) {
+	b.Called(cfg)
+}
+
+func (b *botMock) Pause(_ context.Context, cfg bot.PauseConfig) {
+	b.Called(cfg)
+}
+
+func (b *botMock) Resume(_ context.Context, cfg bot.ResumeConfig) {
+	b.Called(cfg)
+}
+
+func (b *botMock) Vacation(_ context.Context, cfg bot.VacationConfig) {
+	b.Called(cfg)
+}
+
+func (b *botMock) SetWarnings(_ context.Context, cfg bot.WarningsConfig) {
+	b.Called(cfg)
+}
+
+func (b *botMock) SetTimezone(_ context.Context, cfg bot.TimezoneConfig) {
+	b.Called(cfg)
+}
+
+func (b *botMock) SetLanguage(_ context.Context, cfg bot.LanguageConfig) {
+	b.Called(cfg)
+}
+
+func (b *botMock) SetupChannel(_ context.Context, cfg bot.SetupConfig) {
+	b.Called(cfg)
+}
+
+func (b *botMock) SetupLanguage(_ context.Context, cfg bot.SetupConfig) {
+	b.Called(cfg)
+}
+
+// botFactory returns a bot factory always returning b.
+func botFactory(b Bot) func(string, i18n.Locale) Bot {
+	return func(string, i18n.Locale) Bot { return b }
+}
+
+type settingsMock struct {
+	mock.Mock
+}
+
+func (s *settingsMock) GetUserSettings(_ context.Context, userID string) (store.UserSettings, error) {
+	ret := s.Called(userID)
+
+	return ret.Get(0).(store.UserSettings), ret.Error(1)
+}
+
+func (s *settingsMock) GetGuildSettings(_ context.Context, guildID string) (store.GuildSettings, error) {
+	ret := s.Called(guildID)
+
+	return ret.Get(0).(store.GuildSettings), ret.Error(1)
+}
+
+// defaultSettings returns settings where neither the users nor the guilds have chosen anything.
+func defaultSettings() *settingsMock {
+	s := &settingsMock{}
+	s.On("GetUserSettings", mock.Anything).Return(store.UserSettings{}, nil).Maybe()
+	s.On("GetGuildSettings", mock.Anything).Return(store.GuildSettings{}, nil).Maybe()
+
+	return s
+}
+
+type guildsMock struct {
+	mock.Mock
+}
+
+func (g *guildsMock) Guild(_ context.Context, id string) (*discord.Guild, error) {
+	ret := g.Called(id)
+
+	return ret.Get(0).(*discord.Guild), ret.Error(1)
+}
+
+type petListerMock struct {
+	mock.Mock
+}
+
+func (p *petListerMock) ListPets(_ context.Context) (store.Pets, error) {
+	ret := p.Called()
+
+	return ret.Get(0).(store.Pets), ret.Error(1)
+}
+
+type discordMock struct {
+	mock.Mock
+}
+
+func (d *discordMock) SendMessage(_ context.Context, text string) (*discord.Message, error) {
+	ret := d.Called(text)
+
+	return ret.Get(0).(*discord.Message), ret.Error(1)
+}
+
+// remindDiscordMock is a discordMock able to attach the buttons acting on a remind to its messages.
+type remindDiscordMock struct {
+	discordMock
+}
+
+func (d *remindDiscordMock) SendRemindMessage(_ context.Context, remindID store.ID, l i18n.Locale, text string) (*discord.Message, error) {
+	ret := d.Called(remindID, l, text)
+
+	return ret.Get(0).(*discord.Message), ret.Error(1)
+}
+
+type dmOpenerMock struct {
+	mock.Mock
+}
+
+func (d *dmOpenerMock) NewDM(_ context.Context, recipientID string) (*discord.Channel, error) {
+	ret := d.Called(recipientID)
+
+	return ret.Get(0).(*discord.Channel), ret.Error(1)
+}
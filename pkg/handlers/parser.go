@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/youkoulayley/pet-reminder-bot/pkg/i18n"
+)
+
+const commandPrefix = "!"
+
+var (
+	// errNotACommand is returned when a message is not a command of the bot.
+	errNotACommand = errors.New("not a command")
+	// errUnterminatedQuote is returned when a quoted argument is not closed.
+	errUnterminatedQuote = errors.New("unterminated quote")
+)
+
+// arity is the number of arguments a command accepts.
+type arity struct {
+	min int
+	max int
+}
+
+// arities holds the number of arguments of each command, by command name.
+var arities = map[string]arity{
+	"pets":     {min: 0, max: 0},
+	"list":     {min: 0, max: 0},
+	"help":     {min: 0, max: 0},
+	"remind":   {min: 2, max: 3},
+	"fed":      {min: 1, max: 2},
+	"remove":   {min: 1, max: 1},
+	"edit":     {min: 2, max: 3},
+	"snooze":   {min: 2, max: 2},
+	"pause":    {min: 1, max: 1},
+	"resume":   {min: 1, max: 1},
+	"vacation": {min: 2, max: 2},
+	"history":  {min: 1, max: 2},
+	"notify":   {min: 1, max: 1},
+	"timezone": {min: 1, max: 1},
+	"warn":     {min: 1, max: 5},
+	"language": {min: 1, max: 1},
+	"setup":    {min: 1, max: 2},
+}
+
+// command is a command sent in a message.
+// Its name is the one it has been registered with, whatever the language it has been given in.
+type command struct {
+	name string
+	args []string
+}
+
+// parseCommand parses the command of the message.
+// The name of the command is returned along with the error when its arguments are invalid,
+// and errNotACommand is returned when the message is not a command of the bot.
+func parseCommand(content string) (command, error) {
+	content = strings.TrimLeftFunc(content, unicode.IsSpace)
+	if !strings.HasPrefix(content, commandPrefix) {
+		return command{}, errNotACommand
+	}
+
+	content = strings.TrimPrefix(content, commandPrefix)
+
+	end := strings.IndexFunc(content, unicode.IsSpace)
+	if end < 0 {
+		end = len(content)
+	}
+
+	name, ok := i18n.Command(content[:end])
+	if !ok {
+		return command{}, errNotACommand
+	}
+
+	cmd := command{name: name}
+
+	args, err := tokenize(content[end:])
+	if err != nil {
+		return cmd, err
+	}
+
+	if a := arities[name]; len(args) < a.min || len(args) > a.max {
+		return cmd, fmt.Errorf("%s takes between %d and %d arguments, got %d", name, a.min, a.max, len(args))
+	}
+
+	cmd.args = args
+
+	return cmd, nil
+}
+
+// tokenize splits s into arguments separated by whitespaces.
+// Double quotes group the words of an argument, like `"Mon Personnage"`, and `\"` or `\\` escape them inside quotes.
+func tokenize(s string) ([]string, error) {
+	var (
+		args    []string
+		arg     strings.Builder
+		inArg   bool
+		quoted  bool
+		escaped bool
+	)
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			if r != '"' && r != '\\' {
+				arg.WriteRune('\\')
+			}
+
+			arg.WriteRune(r)
+			escaped = false
+		case quoted && r == '\\':
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+			inArg = true
+		case !quoted && unicode.IsSpace(r):
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quoted {
+		return nil, errUnterminatedQuote
+	}
+
+	if inArg {
+		args = append(args, arg.String())
+	}
+
+	return args, nil
+}
@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/i18n"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		desc    string
+		input   string
+		want    []string
+		wantErr error
+	}{
+		{
+			desc:  "empty",
+			input: "",
+		},
+		{
+			desc:  "blank",
+			input: " \t\n ",
+		},
+		{
+			desc:  "words",
+			input: "Chacha Toto",
+			want:  []string{"Chacha", "Toto"},
+		},
+		{
+			desc:  "several whitespaces",
+			input: "  Chacha \t Toto  ",
+			want:  []string{"Chacha", "Toto"},
+		},
+		{
+			desc:  "quoted",
+			input: `Chacha "Mon Perso"`,
+			want:  []string{"Chacha", "Mon Perso"},
+		},
+		{
+			desc:  "quoted inside a word",
+			input: `Mon" "Perso`,
+			want:  []string{"Mon Perso"},
+		},
+		{
+			desc:  "empty quotes",
+			input: `"" Toto`,
+			want:  []string{"", "Toto"},
+		},
+		{
+			desc:  "escaped quote",
+			input: `"Le \"Perso\"" \a`,
+			want:  []string{`Le "Perso"`, `\a`},
+		},
+		{
+			desc:  "escaped backslash",
+			input: `"C:\\" "\n"`,
+			want:  []string{`C:\`, `\n`},
+		},
+		{
+			desc:    "unterminated quote",
+			input:   `Chacha "Mon Perso`,
+			wantErr: errUnterminatedQuote,
+		},
+		{
+			desc:    "unterminated escape",
+			input:   `"Toto\"`,
+			wantErr: errUnterminatedQuote,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tokenize(test.input)
+			if test.wantErr != nil {
+				assert.ErrorIs(t, err, test.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		desc     string
+		content  string
+		want     command
+		wantErr  bool
+		notFound bool
+	}{
+		{
+			desc:     "not a command",
+			content:  "remind Chacha Toto",
+			notFound: true,
+		},
+		{
+			desc:     "unknown command",
+			content:  "!pouet",
+			notFound: true,
+		},
+		{
+			desc:     "command prefix",
+			content:  "!listfoo",
+			notFound: true,
+		},
+		{
+			desc:    "command",
+			content: "!list",
+			want:    command{name: "list"},
+		},
+		{
+			desc:    "localized command",
+			content: "  !familiers ",
+			want:    command{name: "pets"},
+		},
+		{
+			desc:    "arguments",
+			content: `!remind Chacha "Mon Perso"`,
+			want:    command{name: "remind", args: []string{"Chacha", "Mon Perso"}},
+		},
+		{
+			desc:    "optional argument",
+			content: "!history 123",
+			want:    command{name: "history", args: []string{"123"}},
+		},
+		{
+			desc:    "missing argument",
+			content: "!remind Chacha",
+			want:    command{name: "remind"},
+			wantErr: true,
+		},
+		{
+			desc:    "too many arguments",
+			content: "!list 1",
+			want:    command{name: "list"},
+			wantErr: true,
+		},
+		{
+			desc:    "unterminated quote",
+			content: `!remind Chacha "Mon Perso`,
+			want:    command{name: "remind"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseCommand(test.content)
+			switch {
+			case test.notFound:
+				assert.ErrorIs(t, err, errNotACommand)
+			case test.wantErr:
+				assert.Error(t, err)
+				assert.NotErrorIs(t, err, errNotACommand)
+			default:
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestArities(t *testing.T) {
+	// Every command has its usage message, so it can be sent when its arguments are invalid.
+	for name := range arities {
+		key := "usage." + name
+		assert.NotEqual(t, key, i18n.T(i18n.Default, key), name)
+	}
+}
+
+func FuzzTokenize(f *testing.F) {
+	for _, seed := range []string{"", "Chacha Toto", `Chacha "Mon Perso"`, `"Le \"Perso\""`, `"C:\\"`, `"`, `\`, "a\tb\nc"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		args, err := tokenize(input)
+		if err != nil {
+			assert.ErrorIs(t, err, errUnterminatedQuote)
+
+			return
+		}
+
+		if !strings.Contains(input, `"`) {
+			// Without quotes, arguments are the words of the input, invalid UTF-8 bytes being replaced like range does.
+			if words := strings.Fields(string([]rune(input))); len(words) > 0 {
+				assert.Equal(t, words, args)
+			} else {
+				assert.Empty(t, args)
+			}
+
+			for _, arg := range args {
+				assert.False(t, strings.IndexFunc(arg, unicode.IsSpace) >= 0, "%q", arg)
+			}
+		}
+
+		// Quoting the arguments gives them back.
+		quoted := make([]string, 0, len(args))
+		for _, arg := range args {
+			quoted = append(quoted, quote(arg))
+		}
+
+		got, err := tokenize(strings.Join(quoted, " "))
+		require.NoError(t, err)
+		assert.Equal(t, args, got)
+	})
+}
+
+func FuzzParseCommand(f *testing.F) {
+	for _, seed := range []string{"!remind Chacha Toto", `!remind Chacha "Mon Perso"`, "!history 123 10", "!setup langue en", "!", "", "!list\"", " !aide"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, content string) {
+		cmd, err := parseCommand(content)
+		if errors.Is(err, errNotACommand) {
+			assert.Equal(t, command{}, cmd)
+
+			return
+		}
+
+		a, ok := arities[cmd.name]
+		require.True(t, ok, cmd.name)
+
+		if err == nil {
+			assert.GreaterOrEqual(t, len(cmd.args), a.min)
+			assert.LessOrEqual(t, len(cmd.args), a.max)
+		}
+	})
+}
+
+// quote quotes the argument so it is tokenized as it is.
+func quote(arg string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+}
@@ -14,5 +14,6 @@ func (h Handler) ReactionAdd(m *harmony.MessageReaction) {
 	defer cancel()
 
 	cfg := bot.NewCycleConfig{AuthorID: m.UserID, MessageID: m.MessageID}
-	h.bot.NewCycle(ctx, cfg)
+	locale := resolveLocale(ctx, h.settings, m.UserID, m.GuildID)
+	h.newBot(m.ChannelID, locale).NewCycle(ctx, cfg)
 }
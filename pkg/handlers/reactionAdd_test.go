@@ -16,8 +16,9 @@ func TestHandler_ReactionAdd(t *testing.T) {
 	}).Once()
 
 	h := Handler{
-		bot:     b,
-		botUser: discord.User{ID: "3"},
+		newBot:   botFactory(b),
+		settings: defaultSettings(),
+		botUser:  discord.User{ID: "3"},
 	}
 
 	msg := &harmony.MessageReaction{MessageID: "123", UserID: "3"}
@@ -0,0 +1,136 @@
+// Package i18n translates the messages and the commands of the bot.
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed locales/*.yaml
+var localeFiles embed.FS
+
+// Locale represents a language the bot can talk.
+type Locale string
+
+// Supported locales.
+const (
+	French  Locale = "fr"
+	English Locale = "en"
+)
+
+// Default is the locale used when none has been chosen.
+const Default = French
+
+type catalog struct {
+	// Commands maps the name of each command to its localized name.
+	Commands map[string]string `yaml:"commands"`
+	// Keywords maps the name of each keyword given in the arguments of a command, like `until`, to its localized name.
+	Keywords map[string]string `yaml:"keywords"`
+	// Messages maps the key of each message to its localized format.
+	Messages map[string]string `yaml:"messages"`
+}
+
+var catalogs = mustLoad()
+
+// Locales returns the supported locales.
+func Locales() []Locale {
+	return []Locale{French, English}
+}
+
+// ParseLocale parses a locale, regional variants like en-US are accepted.
+func ParseLocale(s string) (Locale, error) {
+	lang := strings.ToLower(strings.SplitN(s, "-", 2)[0])
+
+	for _, l := range Locales() {
+		if string(l) == lang {
+			return l, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown locale %q", s)
+}
+
+// Resolve returns the first supported locale among the candidates, or the default locale when there is none.
+func Resolve(candidates ...string) Locale {
+	for _, candidate := range candidates {
+		if l, err := ParseLocale(candidate); err == nil {
+			return l
+		}
+	}
+
+	return Default
+}
+
+// T returns the message of the given key in the locale, formatted with args.
+// The message of the default locale is used when the locale is unknown.
+func T(l Locale, key string, args ...interface{}) string {
+	msg, ok := catalogs[l].Messages[key]
+	if !ok {
+		msg, ok = catalogs[Default].Messages[key]
+	}
+
+	if !ok {
+		return key
+	}
+
+	if len(args) == 0 {
+		return msg
+	}
+
+	return fmt.Sprintf(msg, args...)
+}
+
+// Command returns the name of the command with the given localized name, in any locale.
+func Command(localized string) (string, bool) {
+	return lookup(localized, func(c catalog) map[string]string { return c.Commands })
+}
+
+// Keyword returns the name of the keyword with the given localized name, in any locale.
+func Keyword(localized string) (string, bool) {
+	return lookup(localized, func(c catalog) map[string]string { return c.Keywords })
+}
+
+// lookup returns the name with the given localized name in the names of the catalogs.
+func lookup(localized string, names func(c catalog) map[string]string) (string, bool) {
+	for _, l := range Locales() {
+		for name, alias := range names(catalogs[l]) {
+			if alias == localized {
+				return name, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func load() (map[Locale]catalog, error) {
+	catalogs := make(map[Locale]catalog)
+
+	for _, l := range Locales() {
+		data, err := localeFiles.ReadFile("locales/" + string(l) + ".yaml")
+		if err != nil {
+			return nil, fmt.Errorf("read locale %q: %w", l, err)
+		}
+
+		var c catalog
+		if err = yaml.Unmarshal(data, &c); err != nil {
+			return nil, fmt.Errorf("parse locale %q: %w", l, err)
+		}
+
+		catalogs[l] = c
+	}
+
+	return catalogs, nil
+}
+
+func mustLoad() map[Locale]catalog {
+	catalogs, err := load()
+	if err != nil {
+		panic(err)
+	}
+
+	return catalogs
+}
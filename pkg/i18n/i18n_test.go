@@ -0,0 +1,146 @@
+package i18n
+
+import (
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var verbRegexp = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+func TestCatalogs(t *testing.T) {
+	def := catalogs[Default]
+
+	for _, l := range Locales() {
+		c, ok := catalogs[l]
+		require.True(t, ok, l)
+
+		assert.Equal(t, keys(def.Commands), keys(c.Commands), l)
+		assert.Equal(t, keys(def.Keywords), keys(c.Keywords), l)
+		assert.Equal(t, keys(def.Messages), keys(c.Messages), l)
+
+		// Each message must be formatted with the same arguments in every locale.
+		for key, msg := range def.Messages {
+			assert.Equal(t, verbRegexp.FindAllString(msg, -1), verbRegexp.FindAllString(c.Messages[key], -1), "%s: %s", l, key)
+		}
+	}
+}
+
+func TestParseLocale(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Locale
+		wantErr bool
+	}{
+		{value: "fr", want: French},
+		{value: "en", want: English},
+		{value: "en-US", want: English},
+		{value: "FR", want: French},
+		{value: "de", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.value, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseLocale(test.value)
+			if test.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	assert.Equal(t, English, Resolve("", "de", "en-GB", "fr"))
+	assert.Equal(t, Default, Resolve("", "de"))
+	assert.Equal(t, Default, Resolve())
+}
+
+func TestT(t *testing.T) {
+	assert.Equal(t, `<@1> Rappel "42" supprimé`, T(French, "remove.done", "1", "42"))
+	assert.Equal(t, `<@1> Reminder "42" removed`, T(English, "remove.done", "1", "42"))
+
+	// Unknown locales fall back to the default one.
+	assert.Equal(t, T(Default, "help"), T(Locale("de"), "help"))
+	assert.Equal(t, "unknown.key", T(English, "unknown.key"))
+}
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   string
+		wantOk bool
+	}{
+		{name: "familiers", want: "pets", wantOk: true},
+		{name: "pets", want: "pets", wantOk: true},
+		{name: "langue", want: "language", wantOk: true},
+		{name: "language", want: "language", wantOk: true},
+		{name: "aide", want: "help", wantOk: true},
+		{name: "remind", want: "remind", wantOk: true},
+		{name: "rappel", want: "remind", wantOk: true},
+		{name: "historique", want: "history", wantOk: true},
+		{name: "notifier", want: "notify", wantOk: true},
+		{name: "configurer", want: "setup", wantOk: true},
+		{name: "salon"},
+		{name: "pouet"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := Command(test.name)
+			assert.Equal(t, test.wantOk, ok)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestKeyword(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   string
+		wantOk bool
+	}{
+		{name: "salon", want: "channel", wantOk: true},
+		{name: "channel", want: "channel", wantOk: true},
+		{name: "langue", want: "language", wantOk: true},
+		{name: "jusqu'au", want: "until", wantOk: true},
+		{name: "personnage", want: "character", wantOk: true},
+		{name: "aide"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := Keyword(test.name)
+			assert.Equal(t, test.wantOk, ok)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func keys(m map[string]string) []string {
+	k := make([]string, 0, len(m))
+	for key := range m {
+		k = append(k, key)
+	}
+
+	sort.Strings(k)
+
+	return k
+}
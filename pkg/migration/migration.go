@@ -0,0 +1,173 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const migrationCollection = "schema_migrations"
+
+// Migration represents a schema migration.
+// Up must be idempotent: it can be run again if the migration could not be recorded.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+}
+
+// Status represents the status of a migration.
+type Status struct {
+	Migration Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+type record struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"appliedAt"`
+}
+
+// Migrator runs the migrations against a database.
+type Migrator struct {
+	db         *mongo.Database
+	records    *mongo.Collection
+	migrations []Migration
+}
+
+// New creates a new Migrator.
+// Migrations must be sorted by strictly increasing version.
+func New(db *mongo.Database, migrations []Migration) (*Migrator, error) {
+	if err := validate(migrations); err != nil {
+		return nil, fmt.Errorf("validate migrations: %w", err)
+	}
+
+	return &Migrator{
+		db:         db,
+		records:    db.Collection(migrationCollection),
+		migrations: migrations,
+	}, nil
+}
+
+func validate(migrations []Migration) error {
+	for i, m := range migrations {
+		if m.Version <= 0 {
+			return fmt.Errorf("migration %q: version must be positive", m.Description)
+		}
+
+		if m.Up == nil {
+			return fmt.Errorf("migration %d: up function is missing", m.Version)
+		}
+
+		if i > 0 && m.Version <= migrations[i-1].Version {
+			return fmt.Errorf("migration %d: versions must be strictly increasing", m.Version)
+		}
+	}
+
+	return nil
+}
+
+// Status returns the status of every known migration.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	res, err := m.records.Find(ctx, bson.D{})
+	if err != nil {
+		return nil, fmt.Errorf("find migrations: %w", err)
+	}
+
+	var records []record
+	if err = res.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("decode migrations: %w", err)
+	}
+
+	applied := make(map[int]time.Time, len(records))
+	for _, r := range records {
+		applied[r.Version] = r.AppliedAt
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		appliedAt, ok := applied[migration.Version]
+
+		statuses = append(statuses, Status{
+			Migration: migration,
+			Applied:   ok,
+			AppliedAt: appliedAt,
+		})
+	}
+
+	return statuses, nil
+}
+
+// Pending returns the migrations which have not been applied yet.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("status: %w", err)
+	}
+
+	var pending []Migration
+	for _, s := range statuses {
+		if !s.Applied {
+			pending = append(pending, s.Migration)
+		}
+	}
+
+	return pending, nil
+}
+
+// Up applies all the pending migrations, in order.
+// It returns the migrations which were applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("pending migrations: %w", err)
+	}
+
+	var applied []Migration
+
+	for _, migration := range pending {
+		logger := log.With().Int("version", migration.Version).Str("description", migration.Description).Logger()
+		logger.Info().Msg("Applying migration")
+
+		if err = migration.Up(ctx, m.db); err != nil {
+			return applied, fmt.Errorf("migration %d: %w", migration.Version, err)
+		}
+
+		r := record{
+			Version:     migration.Version,
+			Description: migration.Description,
+			AppliedAt:   time.Now(),
+		}
+
+		if _, err = m.records.InsertOne(ctx, r); err != nil {
+			if !isDuplicateError(err) {
+				return applied, fmt.Errorf("record migration %d: %w", migration.Version, err)
+			}
+
+			logger.Debug().Msg("Migration already recorded")
+		}
+
+		applied = append(applied, migration)
+	}
+
+	return applied, nil
+}
+
+func isDuplicateError(err error) bool {
+	var writeException mongo.WriteException
+	if errors.As(err, &writeException) {
+		for _, we := range writeException.WriteErrors {
+			if we.Code == 11000 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
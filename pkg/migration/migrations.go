@@ -0,0 +1,100 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// All returns all the migrations, sorted by version.
+// New migrations must be appended at the end with a greater version.
+// Version 1 set counters the reminds have always had, it has been removed.
+func All() []Migration {
+	return []Migration{
+		{
+			Version:     2,
+			Description: "Backfill the fields added to the reminds",
+			Up:          backfillRemindFields,
+		},
+	}
+}
+
+// backfillRemindFields sets the fields added to the reminds on the documents written before them.
+// The guild and the channel of these reminds are unknown, so they are left empty.
+func backfillRemindFields(ctx context.Context, db *mongo.Database) error {
+	reminds := db.Collection("reminds")
+
+	defaults := []bson.E{
+		{Key: "snoozedUntil", Value: time.Time{}},
+		{Key: "warnings", Value: bson.A{}},
+		{Key: "paused", Value: false},
+		{Key: "pausedUntil", Value: time.Time{}},
+	}
+
+	for _, field := range defaults {
+		filter := bson.D{{Key: field.Key, Value: bson.D{{Key: "$exists", Value: false}}}}
+		update := bson.D{{Key: "$set", Value: bson.D{field}}}
+
+		if _, err := reminds.UpdateMany(ctx, filter, update); err != nil {
+			return fmt.Errorf("set default %q: %w", field.Key, err)
+		}
+	}
+
+	if err := backfillCycleStart(ctx, db); err != nil {
+		return fmt.Errorf("backfill cycle start: %w", err)
+	}
+
+	return nil
+}
+
+// backfillCycleStart sets the start of the current cycle of the reminds to their last feed, resume or missed meal.
+// It is left zero for the reminds without any of them, the bot then derives it from their feeding window.
+func backfillCycleStart(ctx context.Context, db *mongo.Database) error {
+	reminds := db.Collection("reminds")
+	events := db.Collection("remind_events")
+
+	filter := bson.D{{Key: "cycleStart", Value: bson.D{{Key: "$exists", Value: false}}}}
+
+	res, err := reminds.Find(ctx, filter, options.Find().SetProjection(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return fmt.Errorf("find reminds: %w", err)
+	}
+
+	var docs []struct {
+		ID bson.RawValue `bson:"_id"`
+	}
+	if err = res.All(ctx, &docs); err != nil {
+		return fmt.Errorf("decode reminds: %w", err)
+	}
+
+	opts := options.FindOne().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	for _, doc := range docs {
+		eventFilter := bson.D{
+			{Key: "remindId", Value: doc.ID},
+			{Key: "type", Value: bson.D{{Key: "$in", Value: bson.A{"fed", "missed", "resumed"}}}},
+		}
+
+		var last struct {
+			CreatedAt time.Time `bson:"createdAt"`
+		}
+
+		err = events.FindOne(ctx, eventFilter, opts).Decode(&last)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return fmt.Errorf("find last event: %w", err)
+		}
+
+		update := bson.D{{Key: "$set", Value: bson.D{{Key: "cycleStart", Value: last.CreatedAt}}}}
+
+		if _, err = reminds.UpdateOne(ctx, bson.D{{Key: "_id", Value: doc.ID}}, update); err != nil {
+			return fmt.Errorf("set cycle start: %w", err)
+		}
+	}
+
+	return nil
+}
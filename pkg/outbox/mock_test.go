@@ -0,0 +1,79 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/skwair/harmony/discord"
+	"github.com/stretchr/testify/mock"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/i18n"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/store"
+)
+
+type discordMock struct {
+	mock.Mock
+}
+
+func (d *discordMock) SendMessage(_ context.Context, text string) (*discord.Message, error) {
+	ret := d.Called(text)
+
+	return ret.Get(0).(*discord.Message), ret.Error(1)
+}
+
+type remindDiscordMock struct {
+	discordMock
+}
+
+func (d *remindDiscordMock) SendRemindMessage(_ context.Context, remindID store.ID, l i18n.Locale, text string) (*discord.Message, error) {
+	ret := d.Called(remindID, l, text)
+
+	return ret.Get(0).(*discord.Message), ret.Error(1)
+}
+
+type storerMock struct {
+	mock.Mock
+}
+
+func (s *storerMock) CreateOutboxMessage(_ context.Context, msg store.OutboxMessage) error {
+	return s.Called(msg).Error(0)
+}
+
+func (s *storerMock) UpdateOutboxMessage(_ context.Context, msg store.OutboxMessage) error {
+	return s.Called(msg).Error(0)
+}
+
+func (s *storerMock) ListPendingOutboxMessages(_ context.Context) ([]store.OutboxMessage, error) {
+	ret := s.Called()
+
+	return ret.Get(0).([]store.OutboxMessage), ret.Error(1)
+}
+
+func (s *storerMock) PruneOutboxMessages(_ context.Context, before time.Time) error {
+	return s.Called(before).Error(0)
+}
+
+func (s *storerMock) CreateRemindMessage(_ context.Context, msg store.RemindMessage) error {
+	return s.Called(msg).Error(0)
+}
+
+func (s *storerMock) GetUserSettings(_ context.Context, userID string) (store.UserSettings, error) {
+	ret := s.Called(userID)
+
+	return ret.Get(0).(store.UserSettings), ret.Error(1)
+}
+
+func (s *storerMock) GetGuildSettings(_ context.Context, guildID string) (store.GuildSettings, error) {
+	ret := s.Called(guildID)
+
+	return ret.Get(0).(store.GuildSettings), ret.Error(1)
+}
+
+type directDiscordMock struct {
+	mock.Mock
+}
+
+func (d *directDiscordMock) SendDirectMessage(_ context.Context, userID string, remindID store.ID, l i18n.Locale, text string) (*discord.Message, error) {
+	ret := d.Called(userID, remindID, l, text)
+
+	return ret.Get(0).(*discord.Message), ret.Error(1)
+}
@@ -0,0 +1,299 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/skwair/harmony/discord"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/clock"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/i18n"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/store"
+)
+
+const (
+	// MaxAttempts is the number of failed deliveries after which a message is dead-lettered.
+	// With the backoff capped to maxDelay, the attempts span about a day, so a message outlives a long Discord outage.
+	MaxAttempts = 160
+
+	baseDelay  = time.Second
+	maxDelay   = 10 * time.Minute
+	retryDelay = 10 * time.Second
+	// idleDelay is the delay between two deliveries when no message is pending.
+	idleDelay = time.Minute
+
+	// SentRetention is how long the sent messages are kept before being pruned.
+	SentRetention = 24 * time.Hour
+	pruneInterval = time.Hour
+
+	// errCodeCannotSendToUser is the Discord error code returned when a user does not accept direct messages.
+	errCodeCannotSendToUser = 50007
+)
+
+// Storer is capable of interacting with the store.
+type Storer interface {
+	CreateOutboxMessage(ctx context.Context, msg store.OutboxMessage) error
+	UpdateOutboxMessage(ctx context.Context, msg store.OutboxMessage) error
+	ListPendingOutboxMessages(ctx context.Context) ([]store.OutboxMessage, error)
+	PruneOutboxMessages(ctx context.Context, before time.Time) error
+	CreateRemindMessage(ctx context.Context, msg store.RemindMessage) error
+	GetUserSettings(ctx context.Context, userID string) (store.UserSettings, error)
+	GetGuildSettings(ctx context.Context, guildID string) (store.GuildSettings, error)
+}
+
+// Discord is capable of sending messages to a discord channel.
+type Discord interface {
+	SendMessage(ctx context.Context, text string) (*discord.Message, error)
+}
+
+// RemindDiscord is implemented by the Discords able to attach the buttons acting on a remind to its messages.
+type RemindDiscord interface {
+	SendRemindMessage(ctx context.Context, remindID store.ID, l i18n.Locale, text string) (*discord.Message, error)
+}
+
+// DirectDiscord is capable of sending direct messages to the Discord users.
+type DirectDiscord interface {
+	SendDirectMessage(ctx context.Context, userID string, remindID store.ID, l i18n.Locale, text string) (*discord.Message, error)
+}
+
+// Outbox persists the notifications before delivering them to Discord,
+// so they are not lost when Discord is unavailable or the bot restarts.
+type Outbox struct {
+	store          Storer
+	channels       func(channelID string) Discord
+	defaultChannel string
+	direct         DirectDiscord
+	clock          clock.Clock
+
+	wake      chan struct{}
+	lastPrune time.Time
+}
+
+// New creates a new Outbox.
+// channels returns the Discord sending messages to the given channel, the messages without a channel are sent to defaultChannel.
+// The direct messages are not supported when dd is nil, every message is then sent to its channel.
+func New(s Storer, channels func(channelID string) Discord, defaultChannel string, dd DirectDiscord, c clock.Clock) *Outbox {
+	return &Outbox{
+		store:          s,
+		channels:       channels,
+		defaultChannel: defaultChannel,
+		direct:         dd,
+		clock:          c,
+		wake:           make(chan struct{}, 1),
+	}
+}
+
+// Notify stores a notification for the given recipient about the given remind, it is delivered by Run.
+// The remind ID can be empty when the notification is not about a single remind.
+func (o *Outbox) Notify(ctx context.Context, to store.Recipient, remindID store.ID, text string) error {
+	msg := store.NewOutboxMessage(remindID, text, o.clock.Now())
+	msg.To = to
+	if err := o.store.CreateOutboxMessage(ctx, msg); err != nil {
+		return fmt.Errorf("create outbox message: %w", err)
+	}
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// Run delivers the pending messages, until the context is done.
+func (o *Outbox) Run(ctx context.Context) {
+	t := o.clock.NewTimer(0)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-t.C():
+
+		case <-o.wake:
+			if !t.Stop() {
+				<-t.C()
+			}
+		}
+
+		t.Reset(o.Deliver(ctx))
+	}
+}
+
+// Deliver sends the pending messages which are due, in order, and prunes the old sent messages from time to time.
+// It returns the delay until the next delivery.
+func (o *Outbox) Deliver(ctx context.Context) time.Duration {
+	o.prune(ctx)
+
+	msgs, err := o.store.ListPendingOutboxMessages(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Unable to list outbox messages")
+
+		return retryDelay
+	}
+
+	now := o.clock.Now()
+	wait := idleDelay
+
+	for _, msg := range msgs {
+		if msg.NextAttempt.After(now) {
+			if d := msg.NextAttempt.Sub(now); d < wait {
+				wait = d
+			}
+
+			continue
+		}
+
+		msg = o.deliver(ctx, msg)
+
+		if msg.Status == store.OutboxPending {
+			if d := msg.NextAttempt.Sub(now); d < wait {
+				wait = d
+			}
+		}
+	}
+
+	return wait
+}
+
+// prune removes the messages sent more than SentRetention ago, at most once per pruneInterval.
+func (o *Outbox) prune(ctx context.Context) {
+	now := o.clock.Now()
+	if now.Sub(o.lastPrune) < pruneInterval {
+		return
+	}
+
+	if err := o.store.PruneOutboxMessages(ctx, now.Add(-SentRetention)); err != nil {
+		log.Error().Err(err).Msg("Unable to prune outbox messages")
+
+		return
+	}
+
+	o.lastPrune = now
+}
+
+// deliver sends the message, and records the result of the attempt.
+func (o *Outbox) deliver(ctx context.Context, msg store.OutboxMessage) store.OutboxMessage {
+	logger := log.With().Str("id", msg.ID.String()).Logger()
+
+	msg.Attempts++
+
+	sent, err := o.send(ctx, msg)
+
+	switch {
+	case err == nil:
+		msg.Status = store.OutboxSent
+		msg.LastError = ""
+
+		o.recordMessage(ctx, sent, msg.RemindID)
+
+	case msg.Attempts >= MaxAttempts:
+		logger.Error().Err(err).Int("attempts", msg.Attempts).Msg("Unable to send message, giving up")
+
+		msg.Status = store.OutboxDead
+		msg.LastError = err.Error()
+
+	default:
+		logger.Warn().Err(err).Int("attempts", msg.Attempts).Msg("Unable to send message, will retry")
+
+		msg.NextAttempt = o.clock.Now().Add(backoff(msg.Attempts))
+		msg.LastError = err.Error()
+	}
+
+	if err = o.store.UpdateOutboxMessage(ctx, msg); err != nil {
+		logger.Error().Err(err).Msg("Unable to update outbox message")
+	}
+
+	return msg
+}
+
+// send sends the message, with the buttons acting on its remind when possible.
+// The message is sent by direct message when its user asked for it, unless the user does not accept them.
+func (o *Outbox) send(ctx context.Context, msg store.OutboxMessage) (*discord.Message, error) {
+	if o.direct != nil && msg.To.UserID != "" {
+		settings, err := o.store.GetUserSettings(ctx, msg.To.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("get user settings: %w", err)
+		}
+
+		if settings.Notify == store.NotifyDM {
+			sent, err := o.direct.SendDirectMessage(ctx, msg.To.UserID, msg.RemindID, i18n.Resolve(msg.To.Locale), msg.Content)
+
+			var apiErr *discord.APIError
+			if !errors.As(err, &apiErr) || apiErr.Code != errCodeCannotSendToUser {
+				return sent, err
+			}
+
+			log.Warn().Err(err).Str("user", msg.To.UserID).Msg("Unable to send direct message, falling back to the channel")
+		}
+	}
+
+	channelID, err := o.channelID(ctx, msg.To)
+	if err != nil {
+		return nil, err
+	}
+
+	d := o.channels(channelID)
+
+	if rd, ok := d.(RemindDiscord); ok && msg.RemindID != "" {
+		return rd.SendRemindMessage(ctx, msg.RemindID, i18n.Resolve(msg.To.Locale), msg.Content)
+	}
+
+	return d.SendMessage(ctx, msg.Content)
+}
+
+// channelID returns the channel the message has to be sent to: the channel its remind was created in,
+// the default channel of its guild when it has none, like the legacy reminds, and the default channel otherwise.
+func (o *Outbox) channelID(ctx context.Context, to store.Recipient) (string, error) {
+	if to.ChannelID != "" {
+		return to.ChannelID, nil
+	}
+
+	if to.GuildID != "" {
+		settings, err := o.store.GetGuildSettings(ctx, to.GuildID)
+		if err != nil {
+			return "", fmt.Errorf("get guild settings: %w", err)
+		}
+
+		if settings.ChannelID != "" {
+			return settings.ChannelID, nil
+		}
+	}
+
+	return o.defaultChannel, nil
+}
+
+// recordMessage records the remind a delivered message is about, so reactions to it can be resolved.
+func (o *Outbox) recordMessage(ctx context.Context, sent *discord.Message, remindID store.ID) {
+	if remindID == "" {
+		return
+	}
+
+	msg := store.RemindMessage{
+		MessageID: sent.ID,
+		RemindID:  remindID,
+		CreatedAt: o.clock.Now(),
+	}
+
+	if err := o.store.CreateRemindMessage(ctx, msg); err != nil {
+		log.Error().Err(err).Str("id", remindID.String()).Msg("Unable to record remind message")
+	}
+}
+
+// backoff returns the delay before the next attempt, doubling after each failed attempt.
+func backoff(attempts int) time.Duration {
+	d := baseDelay
+	for i := 1; i < attempts && d < maxDelay; i++ {
+		d *= 2
+	}
+
+	if d > maxDelay {
+		return maxDelay
+	}
+
+	return d
+}
@@ -0,0 +1,407 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/skwair/harmony/discord"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/clock"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/i18n"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/store"
+)
+
+var testNow = time.Date(2022, time.January, 18, 10, 0, 0, 0, time.UTC)
+
+const testChannelID = "42"
+
+// channels returns a channel factory sending the messages of every channel with d.
+func channels(d Discord) func(string) Discord {
+	return func(string) Discord { return d }
+}
+
+func TestOutbox_Deliver(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMemory()
+	c := clock.NewFake(testNow)
+
+	d := &discordMock{}
+	d.On("SendMessage", "first").Return(&discord.Message{}, errors.New("boom")).Twice()
+	d.On("SendMessage", "first").Return(&discord.Message{ID: "123"}, nil).Once()
+	d.On("SendMessage", "second").Return(&discord.Message{}, nil).Once()
+
+	o := New(s, channels(d), testChannelID, nil, c)
+
+	remindID := store.NewID()
+	require.NoError(t, o.Notify(ctx, store.Recipient{}, remindID, "first"))
+	require.NoError(t, o.Notify(ctx, store.Recipient{}, "", "second"))
+
+	// The first attempt fails, the second message is still delivered.
+	wait := o.Deliver(ctx)
+	assert.Equal(t, time.Second, wait)
+
+	msgs, err := s.ListPendingOutboxMessages(ctx)
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, remindID, msgs[0].RemindID)
+	assert.Equal(t, 1, msgs[0].Attempts)
+	assert.Equal(t, "boom", msgs[0].LastError)
+	assert.Equal(t, testNow.Add(time.Second), msgs[0].NextAttempt)
+
+	// Nothing is due before the backoff elapsed.
+	c.Advance(500 * time.Millisecond)
+	assert.Equal(t, 500*time.Millisecond, o.Deliver(ctx))
+
+	c.Advance(500 * time.Millisecond)
+	assert.Equal(t, 2*time.Second, o.Deliver(ctx))
+
+	c.Advance(2 * time.Second)
+	assert.Equal(t, idleDelay, o.Deliver(ctx))
+
+	msgs, err = s.ListPendingOutboxMessages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, msgs)
+
+	// The delivered message can be resolved to its remind.
+	remindMsg, err := s.GetRemindMessage(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, remindID, remindMsg.RemindID)
+
+	d.AssertExpectations(t)
+}
+
+func TestOutbox_Deliver_remindButtons(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMemory()
+
+	remindID := store.NewID()
+
+	d := &remindDiscordMock{}
+	d.On("SendRemindMessage", remindID, i18n.English, "reminder").Return(&discord.Message{ID: "123"}, nil).Once()
+	d.On("SendMessage", "summary").Return(&discord.Message{ID: "456"}, nil).Once()
+
+	o := New(s, channels(d), testChannelID, nil, clock.NewFake(testNow))
+	require.NoError(t, o.Notify(ctx, store.Recipient{Locale: "en"}, remindID, "reminder"))
+	require.NoError(t, o.Notify(ctx, store.Recipient{}, "", "summary"))
+
+	o.Deliver(ctx)
+
+	d.AssertExpectations(t)
+}
+
+func TestOutbox_Deliver_directMessage(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMemory()
+
+	for _, userID := range []string{"dm", "closed", "error"} {
+		require.NoError(t, s.UpsertUserSettings(ctx, store.UserSettings{UserID: userID, Notify: store.NotifyDM}))
+	}
+
+	remindID := store.NewID()
+
+	d := &discordMock{}
+	d.On("SendMessage", "channel").Return(&discord.Message{}, nil).Once()
+	d.On("SendMessage", "closed").Return(&discord.Message{}, nil).Once()
+
+	dd := &directDiscordMock{}
+	dd.On("SendDirectMessage", "dm", remindID, i18n.Default, "dm").Return(&discord.Message{ID: "123"}, nil).Once()
+	dd.On("SendDirectMessage", "closed", store.ID(""), i18n.Default, "closed").Return(&discord.Message{}, &discord.APIError{HTTPCode: 403, Code: 50007}).Once()
+	dd.On("SendDirectMessage", "error", store.ID(""), i18n.Default, "error").Return(&discord.Message{}, errors.New("boom")).Once()
+
+	o := New(s, channels(d), testChannelID, dd, clock.NewFake(testNow))
+	require.NoError(t, o.Notify(ctx, store.Recipient{UserID: "dm"}, remindID, "dm"))
+	require.NoError(t, o.Notify(ctx, store.Recipient{UserID: "channel"}, "", "channel"))
+	require.NoError(t, o.Notify(ctx, store.Recipient{UserID: "closed"}, "", "closed"))
+	require.NoError(t, o.Notify(ctx, store.Recipient{UserID: "error"}, "", "error"))
+
+	o.Deliver(ctx)
+
+	// Only the message which failed for another reason than closed direct messages is retried.
+	msgs, err := s.ListPendingOutboxMessages(ctx)
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, "error", msgs[0].To.UserID)
+
+	remindMsg, err := s.GetRemindMessage(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, remindID, remindMsg.RemindID)
+
+	d.AssertExpectations(t)
+	dd.AssertExpectations(t)
+}
+
+func TestOutbox_Deliver_channels(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMemory()
+
+	require.NoError(t, s.UpsertGuildSettings(ctx, store.GuildSettings{GuildID: "setup", ChannelID: "notifications"}))
+
+	ds := map[string]*discordMock{
+		testChannelID:   {},
+		"remind":        {},
+		"notifications": {},
+	}
+	ds[testChannelID].On("SendMessage", "default").Return(&discord.Message{}, nil).Once()
+	ds[testChannelID].On("SendMessage", "guild without setup").Return(&discord.Message{}, nil).Once()
+	ds["remind"].On("SendMessage", "remind").Return(&discord.Message{}, nil).Once()
+	ds["remind"].On("SendMessage", "guild with setup").Return(&discord.Message{}, nil).Once()
+	ds["notifications"].On("SendMessage", "legacy").Return(&discord.Message{}, nil).Once()
+
+	o := New(s, func(channelID string) Discord { return ds[channelID] }, testChannelID, nil, clock.NewFake(testNow))
+	require.NoError(t, o.Notify(ctx, store.Recipient{UserID: "1"}, "", "default"))
+	require.NoError(t, o.Notify(ctx, store.Recipient{UserID: "1", GuildID: "guild"}, "", "guild without setup"))
+	require.NoError(t, o.Notify(ctx, store.Recipient{UserID: "1", GuildID: "guild", ChannelID: "remind"}, "", "remind"))
+	// The channel of the remind wins over the default channel of its guild.
+	require.NoError(t, o.Notify(ctx, store.Recipient{UserID: "1", GuildID: "setup", ChannelID: "remind"}, "", "guild with setup"))
+	require.NoError(t, o.Notify(ctx, store.Recipient{UserID: "1", GuildID: "setup"}, "", "legacy"))
+
+	o.Deliver(ctx)
+
+	msgs, err := s.ListPendingOutboxMessages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, msgs)
+
+	for _, d := range ds {
+		d.AssertExpectations(t)
+	}
+}
+
+func TestOutbox_Deliver_deadLetter(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMemory()
+	c := clock.NewFake(testNow)
+
+	d := &discordMock{}
+	d.On("SendMessage", "message").Return(&discord.Message{}, errors.New("boom"))
+
+	o := New(s, channels(d), testChannelID, nil, c)
+	require.NoError(t, o.Notify(ctx, store.Recipient{}, store.NewID(), "message"))
+
+	for i := 0; i < MaxAttempts; i++ {
+		o.Deliver(ctx)
+		c.Advance(maxDelay)
+	}
+
+	d.AssertNumberOfCalls(t, "SendMessage", MaxAttempts)
+
+	msgs, err := s.ListPendingOutboxMessages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, msgs)
+
+	// A dead message is not retried.
+	assert.Equal(t, idleDelay, o.Deliver(ctx))
+	d.AssertNumberOfCalls(t, "SendMessage", MaxAttempts)
+}
+
+func TestOutbox_Deliver_prune(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMemory()
+	c := clock.NewFake(testNow)
+
+	d := &discordMock{}
+	d.On("SendMessage", "message").Return(&discord.Message{}, nil).Once()
+
+	o := New(s, channels(d), testChannelID, nil, c)
+	require.NoError(t, o.Notify(ctx, store.Recipient{}, store.NewID(), "message"))
+
+	o.Deliver(ctx)
+
+	sent := store.NewOutboxMessage("", "sent", testNow)
+	sent.Status = store.OutboxSent
+	require.NoError(t, s.CreateOutboxMessage(ctx, sent))
+
+	// The sent messages are kept until the end of the retention.
+	c.Advance(SentRetention)
+	o.Deliver(ctx)
+	assert.NoError(t, s.UpdateOutboxMessage(ctx, sent))
+
+	c.Advance(pruneInterval)
+	o.Deliver(ctx)
+	assert.ErrorAs(t, s.UpdateOutboxMessage(ctx, sent), &store.NotFoundError{})
+
+	d.AssertExpectations(t)
+}
+
+func TestOutbox_Deliver_pruneError(t *testing.T) {
+	s := &storerMock{}
+	s.On("PruneOutboxMessages", testNow.Add(-SentRetention)).Return(errors.New("boom")).Twice()
+	s.On("ListPendingOutboxMessages").Return([]store.OutboxMessage(nil), nil).Twice()
+
+	o := New(s, channels(&discordMock{}), testChannelID, nil, clock.NewFake(testNow))
+
+	// The messages are still delivered, and the prune is attempted again.
+	assert.Equal(t, idleDelay, o.Deliver(context.Background()))
+	assert.Equal(t, idleDelay, o.Deliver(context.Background()))
+
+	s.AssertExpectations(t)
+}
+
+func TestOutbox_Deliver_requeue(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMemory()
+	c := clock.NewFake(testNow)
+
+	d := &discordMock{}
+	d.On("SendMessage", "message").Return(&discord.Message{}, errors.New("boom")).Times(MaxAttempts)
+	d.On("SendMessage", "message").Return(&discord.Message{}, nil).Once()
+
+	o := New(s, channels(d), testChannelID, nil, c)
+	require.NoError(t, o.Notify(ctx, store.Recipient{}, store.NewID(), "message"))
+
+	for i := 0; i < MaxAttempts; i++ {
+		o.Deliver(ctx)
+		c.Advance(maxDelay)
+	}
+
+	// Once Discord is back, the dead message is delivered after being requeued.
+	n, err := s.RequeueDeadOutboxMessages(ctx, c.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	o.Deliver(ctx)
+
+	msgs, err := s.ListPendingOutboxMessages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, msgs)
+
+	d.AssertExpectations(t)
+}
+
+func TestOutbox_Deliver_noMessageLost(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMemory()
+	c := clock.NewFake(testNow)
+
+	d := &discordMock{}
+	o := New(s, channels(d), testChannelID, nil, c)
+
+	want := []string{"a", "b", "c", "d", "e"}
+	for i, text := range want {
+		// Each message fails as many times as its position before being sent.
+		if i > 0 {
+			d.On("SendMessage", text).Return(&discord.Message{}, errors.New("boom")).Times(i)
+		}
+
+		d.On("SendMessage", text).Return(&discord.Message{ID: text}, nil).Once()
+
+		require.NoError(t, o.Notify(ctx, store.Recipient{}, store.NewID(), text))
+	}
+
+	for i := 0; i < 10; i++ {
+		c.Advance(o.Deliver(ctx))
+	}
+
+	d.AssertExpectations(t)
+	d.AssertNumberOfCalls(t, "SendMessage", 15)
+
+	msgs, err := s.ListPendingOutboxMessages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, msgs)
+}
+
+func TestOutbox_Deliver_listError(t *testing.T) {
+	s := &storerMock{}
+	s.On("PruneOutboxMessages", testNow.Add(-SentRetention)).Return(nil).Once()
+	s.On("ListPendingOutboxMessages").Return([]store.OutboxMessage(nil), errors.New("boom")).Once()
+
+	o := New(s, channels(&discordMock{}), testChannelID, nil, clock.NewFake(testNow))
+
+	assert.Equal(t, retryDelay, o.Deliver(context.Background()))
+
+	s.AssertExpectations(t)
+}
+
+func TestOutbox_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := store.NewMemory()
+	c := clock.NewFake(testNow)
+
+	sent := make(chan string)
+
+	d := &discordMock{}
+	d.On("SendMessage", "first").Return(&discord.Message{}, errors.New("boom")).Once()
+	d.On("SendMessage", mock.Anything).
+		Return(&discord.Message{}, nil).
+		Run(func(args mock.Arguments) { sent <- args.String(0) }).
+		Twice()
+
+	o := New(s, channels(d), testChannelID, nil, c)
+
+	require.NoError(t, o.Notify(ctx, store.Recipient{}, store.NewID(), "first"))
+	// The message stored before Run is delivered at startup, without a wake up.
+	<-o.wake
+
+	go o.Run(ctx)
+
+	// Wait for the outbox to sleep until the retry.
+	c.BlockUntil(1)
+	c.Advance(time.Second)
+	assert.Equal(t, "first", receive(t, sent))
+
+	// A new message wakes the outbox up.
+	require.NoError(t, o.Notify(ctx, store.Recipient{}, "", "second"))
+	assert.Equal(t, "second", receive(t, sent))
+
+	cancel()
+
+	d.AssertExpectations(t)
+}
+
+func receive(t *testing.T, sent <-chan string) string {
+	t.Helper()
+
+	select {
+	case text := <-sent:
+		return text
+	case <-time.After(5 * time.Second):
+		t.Fatal("message not sent")
+
+		return ""
+	}
+}
+
+func TestOutbox_Notify_createError(t *testing.T) {
+	s := &storerMock{}
+	s.On("CreateOutboxMessage", mock.Anything).Return(errors.New("boom")).Once()
+
+	o := New(s, channels(&discordMock{}), testChannelID, nil, clock.NewFake(testNow))
+
+	err := o.Notify(context.Background(), store.Recipient{}, store.NewID(), "message")
+	assert.Error(t, err)
+
+	s.AssertExpectations(t)
+}
+
+func TestMaxAttempts(t *testing.T) {
+	var span time.Duration
+	for attempts := 1; attempts < MaxAttempts; attempts++ {
+		span += backoff(attempts)
+	}
+
+	// A message is given up only when Discord has been failing for about a day.
+	assert.GreaterOrEqual(t, span, 24*time.Hour)
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Second},
+		{attempts: 2, want: 2 * time.Second},
+		{attempts: 5, want: 16 * time.Second},
+		{attempts: 10, want: 8*time.Minute + 32*time.Second},
+		{attempts: 11, want: maxDelay},
+		{attempts: 100, want: maxDelay},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, backoff(test.attempts), "attempts: %d", test.attempts)
+	}
+}
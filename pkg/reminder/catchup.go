@@ -0,0 +1,173 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/i18n"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/store"
+)
+
+// CatchUpPolicy defines how the reminds which timed out while the bot was offline are notified.
+type CatchUpPolicy string
+
+// Catch-up policies.
+const (
+	// CatchUpNotify sends a missed meal message for each remind.
+	CatchUpNotify CatchUpPolicy = "notify"
+	// CatchUpSilent only records the missed meals.
+	CatchUpSilent CatchUpPolicy = "silent"
+	// CatchUpSummary sends a single message per user listing all the missed meals.
+	CatchUpSummary CatchUpPolicy = "summary"
+)
+
+// ParseCatchUpPolicy parses a catch-up policy.
+func ParseCatchUpPolicy(s string) (CatchUpPolicy, error) {
+	switch policy := CatchUpPolicy(s); policy {
+	case CatchUpNotify, CatchUpSilent, CatchUpSummary:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown catch-up policy %q", s)
+	}
+}
+
+// catchUp records the meals missed by the reminds which timed out while the bot was offline.
+// Every FoodMaxDuration elapsed since the timeout counts as another missed meal,
+// and the remind restarts from the last missed meal, as if the bot had never stopped.
+// It returns the reminds, updated when they could be caught up.
+func (r *Reminder) catchUp(ctx context.Context, reminds []store.Remind) []store.Remind {
+	now := r.clock.Now()
+	pets := make(map[string]store.Pet)
+
+	var caughtUp []caughtUpRemind
+
+	for i, remind := range reminds {
+		// Paused reminds do not miss any meal, they start a new cycle when they are resumed.
+		if remind.Paused || !remind.TimeoutRemind.Before(now) {
+			continue
+		}
+
+		logger := log.With().Str("id", remind.ID.String()).Logger()
+
+		pet, ok := pets[remind.PetName]
+		if !ok {
+			var err error
+			if pet, err = r.store.GetPet(ctx, remind.PetName); err != nil {
+				logger.Error().Err(err).Msg("Unable to get pet")
+
+				continue
+			}
+
+			pets[remind.PetName] = pet
+		}
+
+		updated, missed := catchUpRemind(remind, pet, now)
+
+		if err := r.store.UpdateRemind(ctx, updated); err != nil {
+			logger.Error().Err(err).Msg("Unable to update remind")
+
+			continue
+		}
+
+		for _, m := range missed {
+			r.recordEvent(ctx, m, store.EventMissed, m.TimeoutRemind)
+		}
+
+		logger.Info().Int("missed", len(missed)).Msg("Missed meals caught up")
+
+		reminds[i] = updated
+		caughtUp = append(caughtUp, caughtUpRemind{remind: updated, missed: len(missed)})
+	}
+
+	r.notifyCaughtUp(ctx, caughtUp)
+
+	return reminds
+}
+
+// catchUpRemind returns the remind restarted from its last missed meal,
+// and the remind as it was at each missed meal.
+func catchUpRemind(remind store.Remind, pet store.Pet, now time.Time) (store.Remind, []store.Remind) {
+	var missed []store.Remind
+
+	for !remind.TimeoutRemind.After(now) {
+		missed = append(missed, remind)
+
+		timeout := remind.TimeoutRemind
+
+		remind.ReminderSent = false
+		remind.MissedReminder++
+		remind.CycleStart = timeout
+		remind.NextRemind = timeout.Add(pet.FoodMinDuration)
+		remind.TimeoutRemind = timeout.Add(pet.FoodMaxDuration)
+		remind.Warnings = nil
+	}
+
+	return remind, missed
+}
+
+type caughtUpRemind struct {
+	remind store.Remind
+	missed int
+}
+
+func (r *Reminder) notifyCaughtUp(ctx context.Context, caughtUp []caughtUpRemind) {
+	switch r.catchUpPolicy {
+	case CatchUpSilent:
+		return
+
+	case CatchUpNotify:
+		for _, c := range caughtUp {
+			prefs := r.preferences(ctx, c.remind.Recipient())
+
+			if err := r.notifier.Notify(ctx, prefs.recipient(c.remind), c.remind.ID, missedMessage(prefs, c.remind)); err != nil {
+				log.Error().Err(err).Msg("Unable to send reminder message")
+			}
+		}
+
+	default:
+		// One summary is sent per user, however many channels and guilds their reminds belong to.
+		// It is sent where their first caught up remind is notified, or by direct message when they asked for it.
+		var users []string
+
+		summaries := make(map[string]*catchUpSummary)
+
+		for _, c := range caughtUp {
+			user := c.remind.DiscordUserID
+
+			summary, ok := summaries[user]
+			if !ok {
+				prefs := r.preferences(ctx, c.remind.Recipient())
+
+				summary = &catchUpSummary{
+					to:    prefs.recipient(c.remind),
+					prefs: prefs,
+					lines: []string{i18n.T(prefs.locale, "catchUp.header", user)},
+				}
+
+				users = append(users, user)
+				summaries[user] = summary
+			}
+
+			line := i18n.T(summary.prefs.locale, "catchUp.line", c.remind.ID, c.remind.PetName, c.remind.Character, c.missed, c.remind.NextRemind.In(summary.prefs.location).Format(time.RFC1123))
+			summary.lines = append(summary.lines, line)
+		}
+
+		for _, user := range users {
+			summary := summaries[user]
+
+			if err := r.notifier.Notify(ctx, summary.to, "", strings.Join(summary.lines, "\n")); err != nil {
+				log.Error().Err(err).Msg("Unable to send catch-up message")
+			}
+		}
+	}
+}
+
+// catchUpSummary is the summary of the meals a user missed while the bot was offline.
+type catchUpSummary struct {
+	to    store.Recipient
+	prefs userPreferences
+	lines []string
+}
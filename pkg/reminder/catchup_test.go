@@ -0,0 +1,207 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/clock"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/store"
+)
+
+func TestParseCatchUpPolicy(t *testing.T) {
+	for _, policy := range []CatchUpPolicy{CatchUpNotify, CatchUpSilent, CatchUpSummary} {
+		got, err := ParseCatchUpPolicy(string(policy))
+		require.NoError(t, err)
+		assert.Equal(t, policy, got)
+	}
+
+	_, err := ParseCatchUpPolicy("burst")
+	assert.Error(t, err)
+}
+
+func TestCatchUpRemind(t *testing.T) {
+	pet := store.Pet{Name: "pet", FoodMinDuration: 5 * time.Hour, FoodMaxDuration: 18 * time.Hour}
+
+	remind := store.Remind{
+		ID:             store.NewID(),
+		PetName:        "pet",
+		MissedReminder: 2,
+		NextRemind:     testNow.Add(-53 * time.Hour),
+		TimeoutRemind:  testNow.Add(-40 * time.Hour),
+	}
+
+	updated, missed := catchUpRemind(remind, pet, testNow)
+
+	require.Len(t, missed, 3)
+	assert.Equal(t, remind, missed[0])
+	assert.Equal(t, testNow.Add(-22*time.Hour), missed[1].TimeoutRemind)
+	assert.Equal(t, testNow.Add(-4*time.Hour), missed[2].TimeoutRemind)
+
+	assert.Equal(t, 5, updated.MissedReminder)
+	assert.False(t, updated.ReminderSent)
+	assert.Equal(t, testNow.Add(time.Hour), updated.NextRemind)
+	assert.Equal(t, testNow.Add(14*time.Hour), updated.TimeoutRemind)
+}
+
+func TestReminder_LoadReminds_catchUp(t *testing.T) {
+	tests := []struct {
+		desc         string
+		policy       CatchUpPolicy
+		wantMessages int
+	}{
+		{
+			desc:         "notify",
+			policy:       CatchUpNotify,
+			wantMessages: 3,
+		},
+		{
+			desc:   "silent",
+			policy: CatchUpSilent,
+		},
+		{
+			desc:         "summary",
+			policy:       CatchUpSummary,
+			wantMessages: 2,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			s := store.NewMemory()
+			err := s.Bootstrap(ctx, store.Pets{{Name: "pet", FoodMinDuration: 5 * time.Hour, FoodMaxDuration: 18 * time.Hour}})
+			require.NoError(t, err)
+
+			offline := func(user string, timeout time.Duration) store.Remind {
+				remind := store.Remind{
+					ID:            store.NewID(),
+					DiscordUserID: user,
+					PetName:       "pet",
+					Character:     "character",
+					ReminderSent:  true,
+					NextRemind:    testNow.Add(timeout - 13*time.Hour),
+					TimeoutRemind: testNow.Add(timeout),
+				}
+
+				err = s.CreateRemind(ctx, remind)
+				require.NoError(t, err)
+
+				return remind
+			}
+
+			first := offline("user1", -40*time.Hour)
+			offline("user1", -time.Hour)
+			last := offline("user2", -time.Minute)
+			upcoming := offline("user2", 30*time.Minute)
+
+			// Paused reminds do not miss any meal.
+			paused := store.Remind{
+				ID:            store.NewID(),
+				DiscordUserID: "user3",
+				PetName:       "pet",
+				Character:     "character",
+				NextRemind:    testNow.Add(-50 * time.Hour),
+				TimeoutRemind: testNow.Add(-40 * time.Hour),
+				Paused:        true,
+			}
+			err = s.CreateRemind(ctx, paused)
+			require.NoError(t, err)
+
+			n := &notifierMock{}
+			if test.policy == CatchUpSummary {
+				n.On("Notify", mock.MatchedBy(func(msg string) bool {
+					return strings.HasPrefix(msg, "<@user1>") && strings.Count(msg, "\n") == 2
+				})).Return(nil).Once()
+
+				want := fmt.Sprintf("<@user2> Repas râtés pendant l'absence du bot:\n  - %s - pet sur character a râté 1 repas - Prochain rappel: Tue, 18 Jan 2022 14:59:00 UTC", last.ID)
+				n.On("Notify", want).Return(nil).Once()
+			} else if test.wantMessages > 0 {
+				n.On("Notify", mock.Anything).Return(nil)
+			}
+
+			r, err := New(s, n, clock.NewFake(testNow), test.policy, time.UTC)
+			require.NoError(t, err)
+
+			err = r.LoadReminds(ctx)
+			require.NoError(t, err)
+
+			n.AssertNumberOfCalls(t, "Notify", test.wantMessages)
+			n.AssertExpectations(t)
+
+			events, err := s.ListRemindEvents(ctx, first.ID.String(), 0, 0)
+			require.NoError(t, err)
+			assert.Len(t, events, 3)
+
+			got, err := s.GetRemind(ctx, first.ID.String())
+			require.NoError(t, err)
+			assert.Equal(t, 3, got.MissedReminder)
+			assert.Equal(t, testNow.Add(time.Hour), got.NextRemind)
+
+			got, err = s.GetRemind(ctx, upcoming.ID.String())
+			require.NoError(t, err)
+			assert.Equal(t, upcoming, got)
+
+			got, err = s.GetRemind(ctx, paused.ID.String())
+			require.NoError(t, err)
+			assert.Equal(t, paused, got)
+			assert.False(t, r.queue.has(paused.ID))
+
+			due, ok := r.queue.next()
+			require.True(t, ok)
+			assert.Equal(t, upcoming.TimeoutRemind, due)
+		})
+	}
+}
+
+func TestReminder_LoadReminds_catchUpSummarySeveralChannels(t *testing.T) {
+	ctx := context.Background()
+
+	s := store.NewMemory()
+	err := s.Bootstrap(ctx, store.Pets{{Name: "pet", FoodMinDuration: 5 * time.Hour, FoodMaxDuration: 18 * time.Hour}})
+	require.NoError(t, err)
+
+	first := store.Remind{
+		ID:            store.NewID(),
+		DiscordUserID: "user",
+		GuildID:       "guild1",
+		ChannelID:     "channel1",
+		PetName:       "pet",
+		Character:     "character",
+		ReminderSent:  true,
+		NextRemind:    testNow.Add(-14 * time.Hour),
+		TimeoutRemind: testNow.Add(-time.Hour),
+	}
+	require.NoError(t, s.CreateRemind(ctx, first))
+
+	second := first
+	second.ID = store.NewID()
+	second.GuildID = "guild2"
+	second.ChannelID = "channel2"
+	second.Character = "other"
+	require.NoError(t, s.CreateRemind(ctx, second))
+
+	n := &notifierMock{}
+	want := fmt.Sprintf("<@user> Repas râtés pendant l'absence du bot:\n"+
+		"  - %s - pet sur character a râté 1 repas - Prochain rappel: Tue, 18 Jan 2022 14:00:00 UTC\n"+
+		"  - %s - pet sur other a râté 1 repas - Prochain rappel: Tue, 18 Jan 2022 14:00:00 UTC", first.ID, second.ID)
+	n.On("Notify", want).Return(nil).Once()
+
+	r, err := New(s, n, clock.NewFake(testNow), CatchUpSummary, time.UTC)
+	require.NoError(t, err)
+
+	err = r.LoadReminds(ctx)
+	require.NoError(t, err)
+
+	n.AssertExpectations(t)
+	assert.Equal(t, []store.Recipient{{UserID: "user", GuildID: "guild1", ChannelID: "channel1", Locale: "fr"}}, n.recipients)
+}
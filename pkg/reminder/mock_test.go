@@ -2,8 +2,8 @@ package reminder
 
 import (
 	"context"
+	"sync"
 
-	"github.com/skwair/harmony/discord"
 	"github.com/stretchr/testify/mock"
 	"github.com/youkoulayley/pet-reminder-bot/pkg/store"
 )
@@ -28,12 +28,33 @@ func (s *storerMock) UpdateRemind(_ context.Context, remind store.Remind) error
 	return s.Called(remind).Error(0)
 }
 
-type discordMock struct {
+func (s *storerMock) CreateRemindEvent(_ context.Context, event store.RemindEvent) error {
+	return s.Called(event).Error(0)
+}
+
+type notifierMock struct {
 	mock.Mock
+
+	mu         sync.Mutex
+	recipients []store.Recipient
+}
+
+func (n *notifierMock) Notify(_ context.Context, to store.Recipient, _ store.ID, text string) error {
+	n.mu.Lock()
+	n.recipients = append(n.recipients, to)
+	n.mu.Unlock()
+
+	return n.Called(text).Error(0)
+}
+
+func (s *storerMock) GetUserSettings(_ context.Context, userID string) (store.UserSettings, error) {
+	ret := s.Called(userID)
+
+	return ret.Get(0).(store.UserSettings), ret.Error(1)
 }
 
-func (d *discordMock) SendMessage(_ context.Context, text string) (*discord.Message, error) {
-	ret := d.Called(text)
+func (s *storerMock) GetGuildSettings(_ context.Context, guildID string) (store.GuildSettings, error) {
+	ret := s.Called(guildID)
 
-	return ret.Get(0).(*discord.Message), ret.Error(1)
+	return ret.Get(0).(store.GuildSettings), ret.Error(1)
 }
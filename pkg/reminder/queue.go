@@ -0,0 +1,172 @@
+package reminder
+
+import (
+	"container/heap"
+	"time"
+
+	"github.com/youkoulayley/pet-reminder-bot/pkg/store"
+)
+
+// scheduled reports whether the remind has to be processed, reminds paused until they are resumed by hand are not.
+func scheduled(remind store.Remind) bool {
+	return !remind.Paused || !remind.PausedUntil.IsZero()
+}
+
+// dueAt returns the next instant the remind has to be processed at:
+// NextRemind, or SnoozedUntil when snoozed, until the reminder has been sent,
+// then the instant of its next warning, and TimeoutRemind once all of them have been sent.
+// The warnings falling during a snooze are still sent, so the remind is due at the first of them when snoozed.
+// Paused reminds are due when they have to be resumed.
+func dueAt(remind store.Remind) time.Time {
+	if remind.Paused {
+		return remind.PausedUntil
+	}
+
+	if remind.ReminderSent {
+		if at, ok := nextWarning(remind); ok {
+			return at
+		}
+
+		return remind.TimeoutRemind
+	}
+
+	if remind.SnoozedUntil.After(remind.NextRemind) {
+		if at, ok := nextWarning(remind); ok && at.Before(remind.SnoozedUntil) {
+			return at
+		}
+
+		return remind.SnoozedUntil
+	}
+
+	return remind.NextRemind
+}
+
+// nextWarning returns the instant the next warning of the remind has to be sent at.
+func nextWarning(remind store.Remind) (time.Time, bool) {
+	if len(remind.Warnings) == 0 {
+		return time.Time{}, false
+	}
+
+	return remind.TimeoutRemind.Add(-remind.Warnings[0]), true
+}
+
+// pendingWarnings returns the lead times of the warnings which have not to be sent yet at the given instant.
+func pendingWarnings(leads []time.Duration, timeout, now time.Time) []time.Duration {
+	var warnings []time.Duration
+
+	for _, lead := range leads {
+		if lead > 0 && timeout.Add(-lead).After(now) {
+			warnings = append(warnings, lead)
+		}
+	}
+
+	return warnings
+}
+
+type item struct {
+	remind store.Remind
+	due    time.Time
+	index  int
+}
+
+// queue is a min-heap of reminds keyed on their due instant.
+// Reminds are indexed by ID so they can be updated or removed in O(log n).
+type queue struct {
+	items []*item
+	byID  map[store.ID]*item
+}
+
+func newQueue() *queue {
+	return &queue{byID: make(map[store.ID]*item)}
+}
+
+// Len implements heap.Interface.
+func (q *queue) Len() int { return len(q.items) }
+
+// Less implements heap.Interface.
+func (q *queue) Less(i, j int) bool { return q.items[i].due.Before(q.items[j].due) }
+
+// Swap implements heap.Interface.
+func (q *queue) Swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+	q.items[i].index = i
+	q.items[j].index = j
+}
+
+// Push implements heap.Interface.
+func (q *queue) Push(x interface{}) {
+	it := x.(*item)
+	it.index = len(q.items)
+	q.items = append(q.items, it)
+	q.byID[it.remind.ID] = it
+}
+
+// Pop implements heap.Interface.
+func (q *queue) Pop() interface{} {
+	n := len(q.items)
+	it := q.items[n-1]
+	q.items[n-1] = nil
+	q.items = q.items[:n-1]
+	delete(q.byID, it.remind.ID)
+
+	return it
+}
+
+// set inserts the remind, or updates it if it is already queued.
+func (q *queue) set(remind store.Remind, due time.Time) {
+	if it, ok := q.byID[remind.ID]; ok {
+		it.remind = remind
+		it.due = due
+		heap.Fix(q, it.index)
+
+		return
+	}
+
+	heap.Push(q, &item{remind: remind, due: due})
+}
+
+// has reports whether the remind is queued.
+func (q *queue) has(id store.ID) bool {
+	_, ok := q.byID[id]
+
+	return ok
+}
+
+// remove removes the remind if it is queued.
+func (q *queue) remove(id store.ID) {
+	if it, ok := q.byID[id]; ok {
+		heap.Remove(q, it.index)
+	}
+}
+
+// reset replaces all the queued reminds, the reminds which are not scheduled are left out.
+func (q *queue) reset(reminds []store.Remind) {
+	q.items = make([]*item, 0, len(reminds))
+	q.byID = make(map[store.ID]*item, len(reminds))
+
+	for _, remind := range reminds {
+		if scheduled(remind) {
+			q.Push(&item{remind: remind, due: dueAt(remind)})
+		}
+	}
+
+	heap.Init(q)
+}
+
+// next returns the due instant of the earliest remind.
+func (q *queue) next() (time.Time, bool) {
+	if len(q.items) == 0 {
+		return time.Time{}, false
+	}
+
+	return q.items[0].due, true
+}
+
+// popDue removes and returns the earliest remind if it is due at the given instant.
+func (q *queue) popDue(now time.Time) (store.Remind, bool) {
+	if len(q.items) == 0 || q.items[0].due.After(now) {
+		return store.Remind{}, false
+	}
+
+	return heap.Pop(q).(*item).remind, true
+}
@@ -0,0 +1,226 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/clock"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/store"
+)
+
+func TestQueue(t *testing.T) {
+	now := time.Now()
+
+	a := store.Remind{ID: store.NewID(), NextRemind: now.Add(3 * time.Hour)}
+	b := store.Remind{ID: store.NewID(), NextRemind: now.Add(time.Hour)}
+	c := store.Remind{ID: store.NewID(), ReminderSent: true, NextRemind: now.Add(-time.Hour), TimeoutRemind: now.Add(2 * time.Hour)}
+
+	q := newQueue()
+	q.reset([]store.Remind{a, b, c})
+
+	due, ok := q.next()
+	require.True(t, ok)
+	assert.Equal(t, b.NextRemind, due)
+
+	_, ok = q.popDue(now)
+	assert.False(t, ok)
+
+	b.NextRemind = now.Add(4 * time.Hour)
+	q.set(b, dueAt(b))
+
+	q.remove(a.ID)
+	assert.False(t, q.has(a.ID))
+
+	got, ok := q.popDue(now.Add(5 * time.Hour))
+	require.True(t, ok)
+	assert.Equal(t, c, got)
+
+	got, ok = q.popDue(now.Add(5 * time.Hour))
+	require.True(t, ok)
+	assert.Equal(t, b, got)
+
+	_, ok = q.next()
+	assert.False(t, ok)
+}
+
+type discardNotifier struct{}
+
+func (discardNotifier) Notify(context.Context, store.Recipient, store.ID, string) error {
+	return nil
+}
+
+// benchStore is an in-memory storer with constant time updates, so benchmarks measure the reminder only.
+type benchStore struct {
+	reminds []store.Remind
+	index   map[store.ID]int
+}
+
+func (s *benchStore) GetPet(context.Context, string) (store.Pet, error) {
+	return store.Pet{Name: "pet", FoodMinDuration: time.Hour, FoodMaxDuration: 2 * time.Hour}, nil
+}
+
+func (s *benchStore) ListAllReminds(context.Context) ([]store.Remind, error) {
+	return append([]store.Remind(nil), s.reminds...), nil
+}
+
+func (s *benchStore) UpdateRemind(_ context.Context, remind store.Remind) error {
+	s.reminds[s.index[remind.ID]] = remind
+
+	return nil
+}
+
+func (s *benchStore) CreateRemindEvent(context.Context, store.RemindEvent) error {
+	return nil
+}
+
+func (s *benchStore) GetUserSettings(_ context.Context, userID string) (store.UserSettings, error) {
+	return store.DefaultUserSettings(userID), nil
+}
+
+func (s *benchStore) GetGuildSettings(_ context.Context, guildID string) (store.GuildSettings, error) {
+	return store.GuildSettings{GuildID: guildID}, nil
+}
+
+const benchmarkReminds = 100000
+
+func createBenchmarkStore(b *testing.B) (*benchStore, []store.Remind) {
+	b.Helper()
+
+	s := &benchStore{
+		reminds: make([]store.Remind, benchmarkReminds),
+		index:   make(map[store.ID]int, benchmarkReminds),
+	}
+
+	for i := range s.reminds {
+		s.reminds[i] = store.Remind{
+			ID:            store.NewID(),
+			DiscordUserID: fmt.Sprint(i),
+			PetName:       "pet",
+			Character:     "character",
+			NextRemind:    time.Now().Add(24 * time.Hour),
+			TimeoutRemind: time.Now().Add(48 * time.Hour),
+		}
+		s.index[s.reminds[i].ID] = i
+	}
+
+	return s, append([]store.Remind(nil), s.reminds...)
+}
+
+// scanReminder is the previous implementation of the reminder: every tick scans all the reminds,
+// and the whole collection is reloaded after each change.
+type scanReminder struct {
+	Reminder
+
+	reminds []store.Remind
+}
+
+func (r *scanReminder) process(ctx context.Context) {
+	var needUpdate bool
+
+	for _, remind := range r.reminds {
+		if remind.NextRemind.Before(time.Now()) && !remind.ReminderSent {
+			updated, ok := r.handle(ctx, remind)
+			if ok && dueAt(updated).Before(time.Now()) {
+				r.handle(ctx, updated)
+			}
+
+			needUpdate = true
+		}
+	}
+
+	if needUpdate {
+		r.reminds, _ = r.store.ListAllReminds(ctx)
+	}
+}
+
+// BenchmarkReminder compares the queue with a full scan, with one remind becoming due at every tick.
+func BenchmarkReminder(b *testing.B) {
+	ctx := context.Background()
+
+	b.Run("scan", func(b *testing.B) {
+		s, reminds := createBenchmarkStore(b)
+
+		r := &scanReminder{Reminder: Reminder{store: s, notifier: discardNotifier{}, clock: clock.New()}}
+		r.reminds, _ = s.ListAllReminds(ctx)
+
+		b.ResetTimer()
+		b.StopTimer()
+
+		for i := 0; i < b.N; i++ {
+			remind := reminds[i%len(reminds)]
+			remind.NextRemind = time.Now().Add(-time.Second)
+			remind.ReminderSent = false
+
+			_ = s.UpdateRemind(ctx, remind)
+
+			b.StartTimer()
+			r.reminds, _ = s.ListAllReminds(ctx)
+			r.process(ctx)
+			b.StopTimer()
+		}
+	})
+
+	b.Run("queue", func(b *testing.B) {
+		s, reminds := createBenchmarkStore(b)
+
+		r, err := New(s, discardNotifier{}, clock.New(), CatchUpSummary, time.UTC)
+		require.NoError(b, err)
+
+		err = r.LoadReminds(ctx)
+		require.NoError(b, err)
+
+		b.ResetTimer()
+		b.StopTimer()
+
+		for i := 0; i < b.N; i++ {
+			remind := reminds[i%len(reminds)]
+			remind.NextRemind = time.Now().Add(-time.Second)
+			remind.ReminderSent = false
+
+			_ = s.UpdateRemind(ctx, remind)
+
+			b.StartTimer()
+			r.Upsert(remind)
+			r.Process(ctx)
+			b.StopTimer()
+		}
+	})
+}
+
+// BenchmarkReminder_idle compares the queue with a full scan, when no remind is due.
+func BenchmarkReminder_idle(b *testing.B) {
+	ctx := context.Background()
+
+	b.Run("scan", func(b *testing.B) {
+		s, _ := createBenchmarkStore(b)
+
+		r := &scanReminder{Reminder: Reminder{store: s, notifier: discardNotifier{}, clock: clock.New()}}
+		r.reminds, _ = s.ListAllReminds(ctx)
+
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			r.process(ctx)
+		}
+	})
+
+	b.Run("queue", func(b *testing.B) {
+		s, _ := createBenchmarkStore(b)
+
+		r, err := New(s, discardNotifier{}, clock.New(), CatchUpSummary, time.UTC)
+		require.NoError(b, err)
+
+		err = r.LoadReminds(ctx)
+		require.NoError(b, err)
+
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			r.Process(ctx)
+		}
+	})
+}
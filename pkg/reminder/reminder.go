@@ -7,9 +7,9 @@ import (
 	"time"
 
 	"github.com/rs/zerolog/log"
-	"github.com/skwair/harmony/discord"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/clock"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/i18n"
 	"github.com/youkoulayley/pet-reminder-bot/pkg/store"
-	"go.uber.org/atomic"
 )
 
 // Storer is capable of interacting with the store.
@@ -17,57 +17,128 @@ type Storer interface {
 	GetPet(ctx context.Context, name string) (store.Pet, error)
 	ListAllReminds(ctx context.Context) ([]store.Remind, error)
 	UpdateRemind(ctx context.Context, remind store.Remind) error
+	CreateRemindEvent(ctx context.Context, event store.RemindEvent) error
+	GetUserSettings(ctx context.Context, userID string) (store.UserSettings, error)
+	GetGuildSettings(ctx context.Context, guildID string) (store.GuildSettings, error)
 }
 
-// Discord is capable of interacting with discord.
-type Discord interface {
-	SendMessage(ctx context.Context, text string) (*discord.Message, error)
+// Notifier is capable of delivering notifications to discord.
+type Notifier interface {
+	Notify(ctx context.Context, to store.Recipient, remindID store.ID, text string) error
 }
 
+const (
+	// retryDelay is the delay before handling again a remind which could not be handled.
+	retryDelay = 10 * time.Second
+	// idleDelay is the delay between two processings when no remind is queued.
+	idleDelay = time.Hour
+)
+
 // Reminder represents the reminder.
+// Reminds are kept in a queue ordered by due instant, and the reminder sleeps until the next one is due.
 type Reminder struct {
-	reminds   []store.Remind
-	remindsMu sync.Mutex
-
-	needUpdate *atomic.Bool
-
-	store   Storer
-	discord Discord
+	queue    *queue
+	loaded   bool
+	pending  []func(q *queue)
+	inFlight map[store.ID]bool
+	mu       sync.Mutex
+
+	wake chan struct{}
+
+	store         Storer
+	notifier      Notifier
+	clock         clock.Clock
+	catchUpPolicy CatchUpPolicy
+	timezone      *time.Location
 }
 
 // New creates a new Reminder.
-func New(s Storer, d Discord) (*Reminder, error) {
+// The catch-up policy defines how the reminds which timed out while the bot was offline are notified.
+// The times are displayed in the timezone of each user, tz is used for the users who have not set any.
+func New(s Storer, n Notifier, c clock.Clock, policy CatchUpPolicy, tz *time.Location) (*Reminder, error) {
 	reminder := Reminder{
-		store:      s,
-		discord:    d,
-		needUpdate: atomic.NewBool(true),
+		store:         s,
+		notifier:      n,
+		clock:         c,
+		catchUpPolicy: policy,
+		timezone:      tz,
+		queue:         newQueue(),
+		inFlight:      make(map[store.ID]bool),
+		wake:          make(chan struct{}, 1),
 	}
 
 	return &reminder, nil
 }
 
-// SetUpdate notify the reminder to load the new reminds.
-func (r *Reminder) SetUpdate() {
-	r.needUpdate.Store(true)
+// Upsert notifies the reminder that the remind has been created or updated.
+func (r *Reminder) Upsert(remind store.Remind) {
+	r.apply(func(q *queue) {
+		if !scheduled(remind) {
+			q.remove(remind.ID)
+
+			return
+		}
+
+		q.set(remind, dueAt(remind))
+	})
+}
+
+// Remove notifies the reminder that the remind has been removed.
+func (r *Reminder) Remove(id store.ID) {
+	r.apply(func(q *queue) {
+		q.remove(id)
+
+		if _, ok := r.inFlight[id]; ok {
+			r.inFlight[id] = false
+		}
+	})
+}
+
+// apply applies a notification to the queue, and wakes up the reminder.
+// Notifications received before the reminds are loaded are applied again after loading,
+// so they are not lost if the store was read before the change.
+func (r *Reminder) apply(fn func(q *queue)) {
+	r.mu.Lock()
+	fn(r.queue)
+
+	if !r.loaded {
+		r.pending = append(r.pending, fn)
+	}
+	r.mu.Unlock()
+
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
 }
 
 // LoadReminds loads the reminds stored in the storer and load it to the memory.
+// The meals missed while the bot was offline are caught up beforehand.
 func (r *Reminder) LoadReminds(ctx context.Context) error {
 	reminds, err := r.store.ListAllReminds(ctx)
 	if err != nil {
 		return fmt.Errorf("list reminds: %w", err)
 	}
 
-	r.remindsMu.Lock()
-	r.reminds = reminds
-	r.remindsMu.Unlock()
+	reminds = r.catchUp(ctx, reminds)
+
+	r.mu.Lock()
+	r.queue.reset(reminds)
+
+	for _, fn := range r.pending {
+		fn(r.queue)
+	}
+
+	r.pending = nil
+	r.loaded = true
+	r.mu.Unlock()
 
 	return nil
 }
 
-// Run starts the ticker.
+// Run processes the reminds when they are due, until the context is done.
 func (r *Reminder) Run(ctx context.Context) {
-	t := time.NewTicker(time.Second)
+	t := r.clock.NewTimer(0)
 	defer t.Stop()
 
 	for {
@@ -75,78 +146,299 @@ func (r *Reminder) Run(ctx context.Context) {
 		case <-ctx.Done():
 			return
 
-		case <-t.C:
-			r.Process(ctx)
+		case <-t.C():
+
+		case <-r.wake:
+			if !t.Stop() {
+				<-t.C()
+			}
 		}
+
+		r.Process(ctx)
+
+		t.Reset(r.wait())
 	}
 }
 
-// Process handles the reminder logic.
+// wait returns the delay until the next remind is due.
+func (r *Reminder) wait() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.loaded {
+		return retryDelay
+	}
+
+	due, ok := r.queue.next()
+	if !ok {
+		return idleDelay
+	}
+
+	if d := due.Sub(r.clock.Now()); d > 0 {
+		return d
+	}
+
+	return 0
+}
+
+// Process handles all the reminds which are due.
 func (r *Reminder) Process(ctx context.Context) {
-	if r.needUpdate.Load() {
+	r.mu.Lock()
+	loaded := r.loaded
+	r.mu.Unlock()
+
+	if !loaded {
 		if err := r.LoadReminds(ctx); err != nil {
 			log.Error().Err(err).Msg("Unable to load reminder")
 
 			return
 		}
+	}
+
+	now := r.clock.Now()
+
+	for {
+		remind, ok := r.popDue(now)
+		if !ok {
+			return
+		}
 
-		r.needUpdate.Store(false)
+		if updated, ok := r.handle(ctx, remind); ok {
+			r.requeue(updated, dueAt(updated))
+		} else {
+			r.requeue(remind, r.clock.Now().Add(retryDelay))
+		}
 	}
+}
 
-	var needUpdate bool
+// popDue removes the earliest remind from the queue if it is due, and marks it in flight.
+func (r *Reminder) popDue(now time.Time) (store.Remind, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	for _, remind := range r.reminds {
-		if remind.NextRemind.Before(time.Now()) && !remind.ReminderSent {
-			message := fmt.Sprintf("<@%s> Il faut nourrir %q sur %s\nID: %s", remind.DiscordUserID, remind.PetName, remind.Character, remind.ID.Hex())
-			if _, err := r.discord.SendMessage(ctx, message); err != nil {
-				log.Error().Err(err).Msg("Unable to send reminder message")
+	remind, ok := r.queue.popDue(now)
+	if ok {
+		r.inFlight[remind.ID] = true
+	}
 
-				continue
-			}
+	return remind, ok
+}
 
-			remind.ReminderSent = true
-			if err := r.store.UpdateRemind(ctx, remind); err != nil {
-				log.Error().Err(err).Msg("Unable to update remind")
+// requeue puts back a remind which was in flight, unless it has been updated or removed meanwhile.
+func (r *Reminder) requeue(remind store.Remind, due time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-				continue
-			}
+	keep := r.inFlight[remind.ID]
+	delete(r.inFlight, remind.ID)
 
-			needUpdate = true
-		}
+	if keep && scheduled(remind) && !r.queue.has(remind.ID) {
+		r.queue.set(remind, due)
+	}
+}
 
-		if remind.TimeoutRemind.Before(time.Now()) && remind.ReminderSent {
-			pet, err := r.store.GetPet(ctx, remind.PetName)
-			if err != nil {
-				log.Error().Err(err).Msg("Unable to get pet")
+// handle sends the reminder, the warnings or the missed meal message of a due remind, or resumes it.
+// It returns the updated remind, and false if the remind must be handled again later.
+func (r *Reminder) handle(ctx context.Context, remind store.Remind) (store.Remind, bool) {
+	if remind.Paused {
+		return r.resume(ctx, remind)
+	}
 
-				continue
-			}
+	if !remind.ReminderSent && r.clock.Now().Before(remind.SnoozedUntil) {
+		if at, ok := nextWarning(remind); ok {
+			return r.warn(ctx, remind, at)
+		}
+	}
 
-			remind.ReminderSent = false
-			remind.MissedReminder++
-			remind.NextRemind = time.Now().Add(pet.FoodMinDuration)
-			remind.TimeoutRemind = time.Now().Add(pet.FoodMaxDuration)
+	if !remind.ReminderSent {
+		prefs := r.preferences(ctx, remind.Recipient())
 
-			if err = r.store.UpdateRemind(ctx, remind); err != nil {
-				log.Error().Err(err).Msg("Unable to update remind")
+		message := i18n.T(prefs.locale, "reminder.due", remind.DiscordUserID, remind.PetName, remind.Character, remind.ID)
+		if err := r.notifier.Notify(ctx, prefs.recipient(remind), remind.ID, message); err != nil {
+			log.Error().Err(err).Msg("Unable to send reminder message")
 
-				continue
-			}
+			return remind, false
+		}
 
-			needUpdate = true
+		remind.ReminderSent = true
+		remind.SnoozedUntil = time.Time{}
+		// The warnings whose instant has passed, like the ones sent before a snooze, are not sent again.
+		remind.Warnings = pendingWarnings(prefs.warnings, remind.TimeoutRemind, r.clock.Now())
 
-			message := fmt.Sprintf("<@%s> %q sur %s a râté %d repas.\nProchain rappel: %s\nID: %s", remind.DiscordUserID, remind.PetName, remind.Character, remind.MissedReminder, remind.NextRemind.Format(time.RFC1123), remind.ID.Hex())
-			if _, err = r.discord.SendMessage(ctx, message); err != nil {
-				log.Error().Err(err).Msg("Unable to send reminder message")
+		if err := r.store.UpdateRemind(ctx, remind); err != nil {
+			log.Error().Err(err).Msg("Unable to update remind")
 
-				continue
-			}
+			return remind, false
 		}
+
+		r.recordEvent(ctx, remind, store.EventReminderSent, r.clock.Now())
+
+		return remind, true
 	}
 
-	if needUpdate {
-		if err := r.LoadReminds(ctx); err != nil {
-			log.Error().Err(err).Msg("Unable to load remind")
+	if at, ok := nextWarning(remind); ok && r.clock.Now().Before(remind.TimeoutRemind) {
+		return r.warn(ctx, remind, at)
+	}
+
+	pet, err := r.store.GetPet(ctx, remind.PetName)
+	if err != nil {
+		log.Error().Err(err).Msg("Unable to get pet")
+
+		return remind, false
+	}
+
+	missed := remind
+	now := r.clock.Now()
+
+	remind.ReminderSent = false
+	remind.MissedReminder++
+	remind.CycleStart = now
+	remind.NextRemind = now.Add(pet.FoodMinDuration)
+	remind.TimeoutRemind = now.Add(pet.FoodMaxDuration)
+	remind.Warnings = nil
+
+	prefs := r.preferences(ctx, remind.Recipient())
+
+	// The notification is stored before the remind, so it cannot be lost once the remind moved to its next cycle.
+	if err = r.notifier.Notify(ctx, prefs.recipient(remind), remind.ID, missedMessage(prefs, remind)); err != nil {
+		log.Error().Err(err).Msg("Unable to send reminder message")
+
+		return missed, false
+	}
+
+	if err = r.store.UpdateRemind(ctx, remind); err != nil {
+		log.Error().Err(err).Msg("Unable to update remind")
+
+		return missed, false
+	}
+
+	r.recordEvent(ctx, missed, store.EventMissed, now)
+
+	return remind, true
+}
+
+// resume restarts the remind which was paused until now with a new cycle.
+func (r *Reminder) resume(ctx context.Context, remind store.Remind) (store.Remind, bool) {
+	pet, err := r.store.GetPet(ctx, remind.PetName)
+	if err != nil {
+		log.Error().Err(err).Msg("Unable to get pet")
+
+		return remind, false
+	}
+
+	now := r.clock.Now()
+
+	updated := remind
+	updated.Paused = false
+	updated.PausedUntil = time.Time{}
+	updated.MissedReminder = 0
+	updated.ReminderSent = false
+	updated.CycleStart = now
+	updated.NextRemind = now.Add(pet.FoodMinDuration)
+	updated.TimeoutRemind = now.Add(pet.FoodMaxDuration)
+	updated.SnoozedUntil = time.Time{}
+	updated.Warnings = nil
+
+	prefs := r.preferences(ctx, remind.Recipient())
+
+	message := i18n.T(prefs.locale, "reminder.resumed", updated.DiscordUserID, updated.PetName, updated.Character, updated.NextRemind.In(prefs.location).Format(time.RFC1123), updated.ID)
+	if err = r.notifier.Notify(ctx, prefs.recipient(updated), updated.ID, message); err != nil {
+		log.Error().Err(err).Msg("Unable to send resume message")
+
+		return remind, false
+	}
+
+	if err = r.store.UpdateRemind(ctx, updated); err != nil {
+		log.Error().Err(err).Msg("Unable to update remind")
+
+		return remind, false
+	}
+
+	r.recordEvent(ctx, updated, store.EventResumed, now)
+
+	return updated, true
+}
+
+// warn sends the warning due at the given instant, the warnings whose instant has passed meanwhile are skipped.
+func (r *Reminder) warn(ctx context.Context, remind store.Remind, at time.Time) (store.Remind, bool) {
+	prefs := r.preferences(ctx, remind.Recipient())
+
+	message := i18n.T(prefs.locale, "reminder.warning", remind.DiscordUserID, remind.PetName, remind.Character, remind.TimeoutRemind.In(prefs.location).Format(time.RFC1123), remind.ID)
+	if err := r.notifier.Notify(ctx, prefs.recipient(remind), remind.ID, message); err != nil {
+		log.Error().Err(err).Msg("Unable to send warning message")
+
+		return remind, false
+	}
+
+	updated := remind
+	updated.Warnings = pendingWarnings(remind.Warnings, remind.TimeoutRemind, maxTime(at, r.clock.Now()))
+
+	if err := r.store.UpdateRemind(ctx, updated); err != nil {
+		log.Error().Err(err).Msg("Unable to update remind")
+
+		return remind, false
+	}
+
+	return updated, true
+}
+
+func missedMessage(prefs userPreferences, remind store.Remind) string {
+	return i18n.T(prefs.locale, "reminder.missed", remind.DiscordUserID, remind.PetName, remind.Character, remind.MissedReminder, remind.NextRemind.In(prefs.location).Format(time.RFC1123), remind.ID)
+}
+
+// userPreferences holds the preferences notifications are sent with.
+type userPreferences struct {
+	location *time.Location
+	locale   i18n.Locale
+	warnings []time.Duration
+}
+
+// recipient returns the recipient of the notifications about the remind, in the locale of the preferences.
+func (p userPreferences) recipient(remind store.Remind) store.Recipient {
+	to := remind.Recipient()
+	to.Locale = string(p.locale)
+
+	return to
+}
+
+// preferences returns the timezone, the locale and the warnings of the recipient.
+// The default timezone is used when it cannot be got, and the locale of the guild when the user has not chosen one.
+func (r *Reminder) preferences(ctx context.Context, to store.Recipient) userPreferences {
+	settings, err := r.store.GetUserSettings(ctx, to.UserID)
+	if err != nil {
+		log.Error().Err(err).Str("user", to.UserID).Msg("Unable to get user settings")
+
+		return userPreferences{location: r.timezone, locale: i18n.Default}
+	}
+
+	locale := settings.Locale
+	if locale == "" && to.GuildID != "" {
+		guild, err := r.store.GetGuildSettings(ctx, to.GuildID)
+		if err != nil {
+			log.Error().Err(err).Str("guild", to.GuildID).Msg("Unable to get guild settings")
 		}
+
+		locale = guild.Locale
+	}
+
+	return userPreferences{
+		location: settings.Location(r.timezone),
+		locale:   i18n.Resolve(locale),
+		warnings: settings.Warnings,
+	}
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
+
+func (r *Reminder) recordEvent(ctx context.Context, remind store.Remind, typ store.EventType, at time.Time) {
+	event := store.NewRemindEvent(remind, typ, "", at)
+	if err := r.store.CreateRemindEvent(ctx, event); err != nil {
+		log.Error().Err(err).Str("id", remind.ID.String()).Msg("Unable to record remind event")
 	}
 }
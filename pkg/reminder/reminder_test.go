@@ -4,38 +4,134 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"reflect"
-	"strings"
 	"testing"
 	"time"
 
-	"github.com/skwair/harmony/discord"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"github.com/youkoulayley/pet-reminder-bot/pkg/clock"
 	"github.com/youkoulayley/pet-reminder-bot/pkg/store"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-func TestReminder_SetUpdate(t *testing.T) {
-	r, err := New(nil, nil)
+var testNow = time.Date(2022, 1, 18, 10, 0, 0, 0, time.UTC)
+
+func TestReminder_Upsert(t *testing.T) {
+	r, err := New(nil, nil, clock.NewFake(testNow), CatchUpSummary, time.UTC)
 	require.NoError(t, err)
 
-	assert.Equal(t, true, r.needUpdate.Load())
+	remind := store.Remind{ID: store.NewID(), NextRemind: testNow.Add(time.Hour), TimeoutRemind: testNow.Add(2 * time.Hour)}
+
+	r.Upsert(remind)
+
+	due, ok := r.queue.next()
+	require.True(t, ok)
+	assert.Equal(t, remind.NextRemind, due)
+	assert.Len(t, r.wake, 1)
+
+	remind.ReminderSent = true
+	r.Upsert(remind)
+
+	due, ok = r.queue.next()
+	require.True(t, ok)
+	assert.Equal(t, remind.TimeoutRemind, due)
+	assert.Equal(t, 1, r.queue.Len())
+
+	remind.ReminderSent = false
+	remind.SnoozedUntil = testNow.Add(90 * time.Minute)
+	r.Upsert(remind)
+
+	due, ok = r.queue.next()
+	require.True(t, ok)
+	assert.Equal(t, remind.SnoozedUntil, due)
+
+	remind.Paused = true
+	remind.PausedUntil = testNow.Add(48 * time.Hour)
+	r.Upsert(remind)
+
+	due, ok = r.queue.next()
+	require.True(t, ok)
+	assert.Equal(t, remind.PausedUntil, due)
+
+	// Reminds paused until they are resumed by hand are not processed.
+	remind.PausedUntil = time.Time{}
+	r.Upsert(remind)
+
+	assert.Equal(t, 0, r.queue.Len())
+}
+
+func TestReminder_Remove(t *testing.T) {
+	r, err := New(nil, nil, clock.NewFake(testNow), CatchUpSummary, time.UTC)
+	require.NoError(t, err)
 
-	r.needUpdate.Store(false)
-	assert.Equal(t, false, r.needUpdate.Load())
+	remind := store.Remind{ID: store.NewID(), NextRemind: testNow.Add(time.Hour)}
+	r.Upsert(remind)
 
-	r.SetUpdate()
+	r.Remove(remind.ID)
 
-	assert.Equal(t, true, r.needUpdate.Load())
+	assert.Equal(t, 0, r.queue.Len())
+}
+
+func TestReminder_requeue(t *testing.T) {
+	remind := store.Remind{ID: store.NewID(), NextRemind: testNow}
+
+	tests := []struct {
+		desc      string
+		notify    func(r *Reminder)
+		wantDue   time.Time
+		wantQueue bool
+	}{
+		{
+			desc:      "requeued",
+			notify:    func(*Reminder) {},
+			wantDue:   testNow.Add(time.Hour),
+			wantQueue: true,
+		},
+		{
+			desc: "updated meanwhile",
+			notify: func(r *Reminder) {
+				updated := remind
+				updated.NextRemind = testNow.Add(2 * time.Hour)
+				r.Upsert(updated)
+			},
+			wantDue:   testNow.Add(2 * time.Hour),
+			wantQueue: true,
+		},
+		{
+			desc:   "removed meanwhile",
+			notify: func(r *Reminder) { r.Remove(remind.ID) },
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := New(nil, nil, clock.NewFake(testNow), CatchUpSummary, time.UTC)
+			require.NoError(t, err)
+
+			r.Upsert(remind)
+
+			got, ok := r.popDue(testNow)
+			require.True(t, ok)
+
+			test.notify(r)
+			r.requeue(got, testNow.Add(time.Hour))
+
+			due, ok := r.queue.next()
+			require.Equal(t, test.wantQueue, ok)
+			assert.Equal(t, test.wantDue, due)
+			assert.Empty(t, r.inFlight)
+		})
+	}
 }
 
 func TestReminder_Process_loadRemindsError(t *testing.T) {
 	s := &storerMock{}
 	s.On("ListAllReminds").Return([]store.Remind{}, errors.New("boom")).Once()
 
-	r, err := New(s, nil)
+	r, err := New(s, nil, clock.NewFake(testNow), CatchUpSummary, time.UTC)
 	require.NoError(t, err)
 
 	r.Process(context.Background())
@@ -44,99 +140,227 @@ func TestReminder_Process_loadRemindsError(t *testing.T) {
 }
 
 func TestReminder_Process_sendRemind(t *testing.T) {
-	id := primitive.NewObjectID()
+	id := store.NewID()
 
 	remind := store.Remind{
 		ID:            id,
 		DiscordUserID: "discordUser",
 		PetName:       "pet",
 		Character:     "character",
-		TimeoutRemind: time.Now().Add(time.Hour),
+		TimeoutRemind: testNow.Add(time.Hour),
 	}
 
 	s := &storerMock{}
-	s.On("ListAllReminds").Return([]store.Remind{remind}, nil).Twice()
+	s.On("ListAllReminds").Return([]store.Remind{remind}, nil).Once()
+	s.On("GetUserSettings", "discordUser").Return(store.DefaultUserSettings("discordUser"), nil).Once()
 
-	d := &discordMock{}
-	d.On("SendMessage", fmt.Sprintf("<@discordUser> Il faut nourrir \"pet\" sur character\nID: %s", id.Hex())).
-		Return(&discord.Message{}, nil).
+	n := &notifierMock{}
+	n.On("Notify", fmt.Sprintf("<@discordUser> Il faut nourrir \"pet\" sur character\nID: %s", id.String())).
+		Return(nil).
 		Once()
 
 	updatedRemind := remind
 	updatedRemind.ReminderSent = true
 	s.On("UpdateRemind", updatedRemind).Return(nil).Once()
+	s.On("CreateRemindEvent", mock.MatchedBy(func(e store.RemindEvent) bool {
+		return e.RemindID == id && e.Type == store.EventReminderSent
+	})).Return(nil).Once()
 
-	r, err := New(s, d)
+	r, err := New(s, n, clock.NewFake(testNow), CatchUpSummary, time.UTC)
 	require.NoError(t, err)
 
 	r.Process(context.Background())
 
+	due, ok := r.queue.next()
+	require.True(t, ok)
+	assert.Equal(t, remind.TimeoutRemind, due)
+
 	s.AssertExpectations(t)
-	d.AssertExpectations(t)
+	n.AssertExpectations(t)
 }
 
-func TestReminder_Process_sendRemind_sendMessageError(t *testing.T) {
-	id := primitive.NewObjectID()
+func TestReminder_Process_sendRemind_snoozed(t *testing.T) {
+	id := store.NewID()
 
 	remind := store.Remind{
 		ID:            id,
 		DiscordUserID: "discordUser",
 		PetName:       "pet",
 		Character:     "character",
-		TimeoutRemind: time.Now().Add(time.Hour),
+		NextRemind:    testNow.Add(-time.Hour),
+		SnoozedUntil:  testNow,
+		TimeoutRemind: testNow.Add(time.Hour),
 	}
 
 	s := &storerMock{}
 	s.On("ListAllReminds").Return([]store.Remind{remind}, nil).Once()
+	s.On("GetUserSettings", "discordUser").Return(store.DefaultUserSettings("discordUser"), nil).Once()
 
-	d := &discordMock{}
-	d.On("SendMessage", fmt.Sprintf("<@discordUser> Il faut nourrir \"pet\" sur character\nID: %s", id.Hex())).
-		Return(&discord.Message{}, errors.New("boom")).
+	n := &notifierMock{}
+	n.On("Notify", fmt.Sprintf("<@discordUser> Il faut nourrir \"pet\" sur character\nID: %s", id.String())).
+		Return(nil).
 		Once()
 
-	r, err := New(s, d)
+	updatedRemind := remind
+	updatedRemind.ReminderSent = true
+	updatedRemind.SnoozedUntil = time.Time{}
+	s.On("UpdateRemind", updatedRemind).Return(nil).Once()
+	s.On("CreateRemindEvent", mock.MatchedBy(func(e store.RemindEvent) bool {
+		return e.RemindID == id && e.Type == store.EventReminderSent
+	})).Return(nil).Once()
+
+	r, err := New(s, n, clock.NewFake(testNow), CatchUpSummary, time.UTC)
 	require.NoError(t, err)
 
 	r.Process(context.Background())
 
+	due, ok := r.queue.next()
+	require.True(t, ok)
+	assert.Equal(t, remind.TimeoutRemind, due)
+
 	s.AssertExpectations(t)
-	d.AssertExpectations(t)
+	n.AssertExpectations(t)
+}
+
+func TestReminder_Process_sendRemind_createRemindEventError(t *testing.T) {
+	id := store.NewID()
+
+	remind := store.Remind{
+		ID:            id,
+		DiscordUserID: "discordUser",
+		PetName:       "pet",
+		Character:     "character",
+		TimeoutRemind: testNow.Add(time.Hour),
+	}
+
+	s := &storerMock{}
+	s.On("ListAllReminds").Return([]store.Remind{remind}, nil).Once()
+	s.On("GetUserSettings", "discordUser").Return(store.DefaultUserSettings("discordUser"), nil).Once()
+
+	n := &notifierMock{}
+	n.On("Notify", fmt.Sprintf("<@discordUser> Il faut nourrir \"pet\" sur character\nID: %s", id.String())).
+		Return(nil).
+		Once()
+
+	updatedRemind := remind
+	updatedRemind.ReminderSent = true
+	s.On("UpdateRemind", updatedRemind).Return(nil).Once()
+	s.On("CreateRemindEvent", mock.Anything).Return(errors.New("boom")).Once()
+
+	r, err := New(s, n, clock.NewFake(testNow), CatchUpSummary, time.UTC)
+	require.NoError(t, err)
+
+	r.Process(context.Background())
+
+	s.AssertExpectations(t)
+	n.AssertExpectations(t)
+}
+
+func TestReminder_Process_sendRemind_notifyError(t *testing.T) {
+	id := store.NewID()
+
+	remind := store.Remind{
+		ID:            id,
+		DiscordUserID: "discordUser",
+		PetName:       "pet",
+		Character:     "character",
+		TimeoutRemind: testNow.Add(time.Hour),
+	}
+
+	s := &storerMock{}
+	s.On("ListAllReminds").Return([]store.Remind{remind}, nil).Once()
+	s.On("GetUserSettings", "discordUser").Return(store.DefaultUserSettings("discordUser"), nil).Once()
+
+	n := &notifierMock{}
+	n.On("Notify", fmt.Sprintf("<@discordUser> Il faut nourrir \"pet\" sur character\nID: %s", id.String())).
+		Return(errors.New("boom")).
+		Once()
+
+	r, err := New(s, n, clock.NewFake(testNow), CatchUpSummary, time.UTC)
+	require.NoError(t, err)
+
+	r.Process(context.Background())
+
+	due, ok := r.queue.next()
+	require.True(t, ok)
+	assert.Equal(t, testNow.Add(retryDelay), due)
+
+	s.AssertExpectations(t)
+	n.AssertExpectations(t)
 }
 
 func TestReminder_Process_sendRemind_updateRemindError(t *testing.T) {
-	id := primitive.NewObjectID()
+	id := store.NewID()
 
 	remind := store.Remind{
 		ID:            id,
 		DiscordUserID: "discordUser",
 		PetName:       "pet",
 		Character:     "character",
-		TimeoutRemind: time.Now().Add(time.Hour),
+		TimeoutRemind: testNow.Add(time.Hour),
 	}
 
 	s := &storerMock{}
 	s.On("ListAllReminds").Return([]store.Remind{remind}, nil).Once()
+	s.On("GetUserSettings", "discordUser").Return(store.DefaultUserSettings("discordUser"), nil).Once()
 
-	d := &discordMock{}
-	d.On("SendMessage", fmt.Sprintf("<@discordUser> Il faut nourrir \"pet\" sur character\nID: %s", id.Hex())).
-		Return(&discord.Message{}, nil).
+	n := &notifierMock{}
+	n.On("Notify", fmt.Sprintf("<@discordUser> Il faut nourrir \"pet\" sur character\nID: %s", id.String())).
+		Return(nil).
 		Once()
 
 	updatedRemind := remind
 	updatedRemind.ReminderSent = true
 	s.On("UpdateRemind", updatedRemind).Return(errors.New("boom")).Once()
 
-	r, err := New(s, d)
+	r, err := New(s, n, clock.NewFake(testNow), CatchUpSummary, time.UTC)
+	require.NoError(t, err)
+
+	r.Process(context.Background())
+
+	s.AssertExpectations(t)
+	n.AssertExpectations(t)
+}
+
+func TestReminder_Process_sendRemind_guildLocale(t *testing.T) {
+	id := store.NewID()
+
+	remind := store.Remind{
+		ID:            id,
+		DiscordUserID: "discordUser",
+		GuildID:       "guild",
+		PetName:       "pet",
+		Character:     "character",
+		TimeoutRemind: testNow.Add(time.Hour),
+	}
+
+	s := &storerMock{}
+	s.On("ListAllReminds").Return([]store.Remind{remind}, nil).Once()
+	s.On("GetUserSettings", "discordUser").Return(store.DefaultUserSettings("discordUser"), nil).Once()
+	s.On("GetGuildSettings", "guild").Return(store.GuildSettings{GuildID: "guild", Locale: "en"}, nil).Once()
+	s.On("UpdateRemind", mock.Anything).Return(nil).Once()
+	s.On("CreateRemindEvent", mock.Anything).Return(nil).Once()
+
+	n := &notifierMock{}
+	n.On("Notify", fmt.Sprintf("<@discordUser> Time to feed \"pet\" on character\nID: %s", id.String())).
+		Return(nil).
+		Once()
+
+	r, err := New(s, n, clock.NewFake(testNow), CatchUpSummary, time.UTC)
 	require.NoError(t, err)
 
 	r.Process(context.Background())
 
+	// The buttons of the message are labelled in the same locale.
+	require.Len(t, n.recipients, 1)
+	assert.Equal(t, store.Recipient{UserID: "discordUser", GuildID: "guild", Locale: "en"}, n.recipients[0])
+
 	s.AssertExpectations(t)
-	d.AssertExpectations(t)
+	n.AssertExpectations(t)
 }
 
 func TestReminder_Process_sendTimeoutRemind(t *testing.T) {
-	id := primitive.NewObjectID()
+	id := store.NewID()
 
 	remind := store.Remind{
 		ID:            id,
@@ -144,6 +368,7 @@ func TestReminder_Process_sendTimeoutRemind(t *testing.T) {
 		PetName:       "pet",
 		Character:     "character",
 		ReminderSent:  true,
+		TimeoutRemind: testNow,
 	}
 
 	pet := store.Pet{
@@ -153,64 +378,42 @@ func TestReminder_Process_sendTimeoutRemind(t *testing.T) {
 	}
 
 	s := &storerMock{}
-	s.On("ListAllReminds").Return([]store.Remind{remind}, nil).Twice()
+	s.On("ListAllReminds").Return([]store.Remind{remind}, nil).Once()
 	s.On("GetPet", "pet").Return(pet, nil).Once()
 
 	updatedRemind := remind
 	updatedRemind.ReminderSent = false
 	updatedRemind.MissedReminder = 1
+	updatedRemind.CycleStart = testNow
+	updatedRemind.NextRemind = testNow.Add(pet.FoodMinDuration)
+	updatedRemind.TimeoutRemind = testNow.Add(pet.FoodMaxDuration)
 
-	s.On("UpdateRemind", mock.MatchedBy(func(r store.Remind) bool {
-		if time.Now().Add(pet.FoodMinDuration).Sub(r.NextRemind) > time.Minute {
-			return false
-		}
-
-		if time.Now().Add(pet.FoodMaxDuration).Sub(r.TimeoutRemind) > time.Minute {
-			return false
-		}
-
-		updatedRemind.NextRemind = r.NextRemind
-		updatedRemind.TimeoutRemind = r.TimeoutRemind
-
-		return reflect.DeepEqual(updatedRemind, r)
+	s.On("UpdateRemind", updatedRemind).Return(nil).Once()
+	s.On("CreateRemindEvent", mock.MatchedBy(func(e store.RemindEvent) bool {
+		return e.RemindID == id && e.Type == store.EventMissed && e.TimeoutRemind.Equal(remind.TimeoutRemind)
 	})).Return(nil).Once()
 
-	d := &discordMock{}
-	d.On("SendMessage", mock.MatchedBy(func(msg string) bool {
-		parts := strings.Split(msg, "\n")
-		reminderPart := parts[0]
-		datePart := parts[1]
-		idPart := parts[2]
-
-		if reminderPart != "<@discordUser> \"pet\" sur character a râté 1 repas." {
-			return false
-		}
-
-		dateParts := strings.SplitN(datePart, ": ", 2)
-		date, err := time.Parse(time.RFC1123, dateParts[1])
-		if err != nil {
-			return false
-		}
-
-		if time.Now().Add(pet.FoodMinDuration).Sub(date) > time.Minute {
-			return false
-		}
-
-		return idPart == "ID: "+id.Hex()
-	})).Return(&discord.Message{}, nil).
+	// The next remind is displayed in the timezone of the user.
+	s.On("GetUserSettings", "discordUser").
+		Return(store.UserSettings{UserID: "discordUser", Timezone: "Europe/Paris"}, nil).
+		Once()
+
+	n := &notifierMock{}
+	wantMessage := fmt.Sprintf("<@discordUser> \"pet\" sur character a râté 1 repas.\nProchain rappel: Tue, 18 Jan 2022 12:00:00 CET\nID: %s", id)
+	n.On("Notify", wantMessage).Return(nil).
 		Once()
 
-	r, err := New(s, d)
+	r, err := New(s, n, clock.NewFake(testNow), CatchUpSummary, time.UTC)
 	require.NoError(t, err)
 
 	r.Process(context.Background())
 
 	s.AssertExpectations(t)
-	d.AssertExpectations(t)
+	n.AssertExpectations(t)
 }
 
 func TestReminder_Process_sendTimeoutRemind_getPetError(t *testing.T) {
-	id := primitive.NewObjectID()
+	id := store.NewID()
 
 	remind := store.Remind{
 		ID:            id,
@@ -218,13 +421,14 @@ func TestReminder_Process_sendTimeoutRemind_getPetError(t *testing.T) {
 		PetName:       "pet",
 		Character:     "character",
 		ReminderSent:  true,
+		TimeoutRemind: testNow,
 	}
 
 	s := &storerMock{}
 	s.On("ListAllReminds").Return([]store.Remind{remind}, nil).Once()
 	s.On("GetPet", "pet").Return(store.Pet{}, errors.New("boom")).Once()
 
-	r, err := New(s, nil)
+	r, err := New(s, nil, clock.NewFake(testNow), CatchUpSummary, time.UTC)
 	require.NoError(t, err)
 
 	r.Process(context.Background())
@@ -233,7 +437,7 @@ func TestReminder_Process_sendTimeoutRemind_getPetError(t *testing.T) {
 }
 
 func TestReminder_Process_sendTimeoutRemind_updateRemindError(t *testing.T) {
-	id := primitive.NewObjectID()
+	id := store.NewID()
 
 	remind := store.Remind{
 		ID:            id,
@@ -241,6 +445,7 @@ func TestReminder_Process_sendTimeoutRemind_updateRemindError(t *testing.T) {
 		PetName:       "pet",
 		Character:     "character",
 		ReminderSent:  true,
+		TimeoutRemind: testNow,
 	}
 
 	pet := store.Pet{
@@ -252,36 +457,227 @@ func TestReminder_Process_sendTimeoutRemind_updateRemindError(t *testing.T) {
 	s := &storerMock{}
 	s.On("ListAllReminds").Return([]store.Remind{remind}, nil).Once()
 	s.On("GetPet", "pet").Return(pet, nil).Once()
+	s.On("GetUserSettings", "discordUser").Return(store.DefaultUserSettings("discordUser"), nil).Once()
 
 	updatedRemind := remind
 	updatedRemind.ReminderSent = false
 	updatedRemind.MissedReminder = 1
+	updatedRemind.CycleStart = testNow
+	updatedRemind.NextRemind = testNow.Add(pet.FoodMinDuration)
+	updatedRemind.TimeoutRemind = testNow.Add(pet.FoodMaxDuration)
 
-	s.On("UpdateRemind", mock.MatchedBy(func(r store.Remind) bool {
-		if time.Now().Add(pet.FoodMinDuration).Sub(r.NextRemind) > time.Minute {
-			return false
-		}
+	s.On("UpdateRemind", updatedRemind).Return(errors.New("boom")).Once()
 
-		if time.Now().Add(pet.FoodMaxDuration).Sub(r.TimeoutRemind) > time.Minute {
-			return false
-		}
+	n := &notifierMock{}
+	n.On("Notify", mock.Anything).Return(nil).Once()
 
-		updatedRemind.NextRemind = r.NextRemind
-		updatedRemind.TimeoutRemind = r.TimeoutRemind
+	r, err := New(s, n, clock.NewFake(testNow), CatchUpSummary, time.UTC)
+	require.NoError(t, err)
 
-		return reflect.DeepEqual(updatedRemind, r)
-	})).Return(errors.New("boom")).Once()
+	r.Process(context.Background())
+
+	s.AssertExpectations(t)
+	n.AssertExpectations(t)
+}
+
+func TestReminder_Process_sendTimeoutRemind_notifyError(t *testing.T) {
+	id := store.NewID()
 
-	r, err := New(s, nil)
+	remind := store.Remind{
+		ID:            id,
+		DiscordUserID: "discordUser",
+		PetName:       "pet",
+		Character:     "character",
+		ReminderSent:  true,
+		TimeoutRemind: testNow,
+	}
+
+	pet := store.Pet{
+		Name:            "pet",
+		FoodMinDuration: 1 * time.Hour,
+		FoodMaxDuration: 2 * time.Hour,
+	}
+
+	s := &storerMock{}
+	s.On("ListAllReminds").Return([]store.Remind{remind}, nil).Once()
+	s.On("GetPet", "pet").Return(pet, nil).Once()
+	s.On("GetUserSettings", "discordUser").Return(store.DefaultUserSettings("discordUser"), nil).Once()
+
+	n := &notifierMock{}
+	wantMessage := fmt.Sprintf("<@discordUser> \"pet\" sur character a râté 1 repas.\nProchain rappel: Tue, 18 Jan 2022 11:00:00 UTC\nID: %s", id)
+	n.On("Notify", wantMessage).Return(errors.New("boom")).
+		Once()
+
+	r, err := New(s, n, clock.NewFake(testNow), CatchUpSummary, time.UTC)
 	require.NoError(t, err)
 
 	r.Process(context.Background())
 
 	s.AssertExpectations(t)
+	n.AssertExpectations(t)
+}
+
+func TestReminder_Run(t *testing.T) {
+	remind := store.Remind{
+		ID:            store.NewID(),
+		DiscordUserID: "discordUser",
+		PetName:       "pet",
+		Character:     "character",
+		NextRemind:    testNow.Add(time.Hour),
+		TimeoutRemind: testNow.Add(2 * time.Hour),
+	}
+
+	s := &storerMock{}
+	s.On("ListAllReminds").Return([]store.Remind{}, nil).Once()
+	s.On("GetUserSettings", "discordUser").Return(store.DefaultUserSettings("discordUser"), nil).Once()
+	s.On("UpdateRemind", mock.Anything).Return(nil).Once()
+
+	sent := make(chan struct{})
+	s.On("CreateRemindEvent", mock.Anything).
+		Return(nil).
+		Run(func(mock.Arguments) { close(sent) }).
+		Once()
+
+	n := &notifierMock{}
+	n.On("Notify", fmt.Sprintf("<@discordUser> Il faut nourrir \"pet\" sur character\nID: %s", remind.ID)).
+		Return(nil).
+		Once()
+
+	clk := clock.NewFake(testNow)
+
+	r, err := New(s, n, clk, CatchUpSummary, time.UTC)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r.Upsert(remind)
+
+	go r.Run(ctx)
+
+	// Wait for the reminder to sleep until the remind is due.
+	clk.BlockUntil(1)
+	clk.Advance(time.Hour)
+
+	select {
+	case <-sent:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reminder not sent")
+	}
+
+	cancel()
+
+	s.AssertExpectations(t)
+	n.AssertExpectations(t)
 }
 
-func TestReminder_Process_sendTimeoutRemind_sendMessageError(t *testing.T) {
-	id := primitive.NewObjectID()
+func TestReminder_Process_feedingCycles(t *testing.T) {
+	ctx := context.Background()
+
+	s := store.NewMemory()
+	err := s.Bootstrap(ctx, store.Pets{{Name: "pet", FoodMinDuration: 5 * time.Hour, FoodMaxDuration: 18 * time.Hour}})
+	require.NoError(t, err)
+
+	remind := store.Remind{
+		ID:            store.NewID(),
+		DiscordUserID: "discordUser",
+		PetName:       "pet",
+		Character:     "character",
+		NextRemind:    testNow.Add(5 * time.Hour),
+		TimeoutRemind: testNow.Add(18 * time.Hour),
+	}
+	err = s.CreateRemind(ctx, remind)
+	require.NoError(t, err)
+
+	n := &notifierMock{}
+	n.On("Notify", mock.Anything).Return(nil)
+
+	clk := clock.NewFake(testNow)
+
+	r, err := New(s, n, clk, CatchUpSummary, time.UTC)
+	require.NoError(t, err)
+
+	// Nobody feeds the pet for three days.
+	for i := 0; i < 3*24*60; i++ {
+		clk.Advance(time.Minute)
+		r.Process(ctx)
+	}
+
+	events, err := s.ListRemindEvents(ctx, remind.ID.String(), 0, 0)
+	require.NoError(t, err)
+
+	var got []string
+	for i := len(events) - 1; i >= 0; i-- {
+		got = append(got, fmt.Sprintf("%s %s", events[i].Type, events[i].CreatedAt.Sub(testNow)))
+	}
+
+	want := []string{
+		"reminderSent 5h0m0s",
+		"missed 18h0m0s",
+		"reminderSent 23h0m0s",
+		"missed 36h0m0s",
+		"reminderSent 41h0m0s",
+		"missed 54h0m0s",
+		"reminderSent 59h0m0s",
+		"missed 72h0m0s",
+	}
+	assert.Equal(t, want, got)
+
+	remind, err = s.GetRemind(ctx, remind.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, 4, remind.MissedReminder)
+	assert.Equal(t, testNow.Add(77*time.Hour), remind.NextRemind)
+
+	n.AssertNumberOfCalls(t, "Notify", 8)
+}
+
+func TestReminder_Process_sendRemind_warnings(t *testing.T) {
+	id := store.NewID()
+
+	remind := store.Remind{
+		ID:            id,
+		DiscordUserID: "discordUser",
+		PetName:       "pet",
+		Character:     "character",
+		TimeoutRemind: testNow.Add(90 * time.Minute),
+	}
+
+	// The warning two hours before the end of the feeding window is already past.
+	settings := store.DefaultUserSettings("discordUser")
+	settings.Warnings = []time.Duration{2 * time.Hour, time.Hour, 15 * time.Minute}
+
+	s := &storerMock{}
+	s.On("ListAllReminds").Return([]store.Remind{remind}, nil).Once()
+	s.On("GetUserSettings", "discordUser").Return(settings, nil).Once()
+
+	n := &notifierMock{}
+	n.On("Notify", fmt.Sprintf("<@discordUser> Il faut nourrir \"pet\" sur character\nID: %s", id.String())).
+		Return(nil).
+		Once()
+
+	updatedRemind := remind
+	updatedRemind.ReminderSent = true
+	updatedRemind.Warnings = []time.Duration{time.Hour, 15 * time.Minute}
+	s.On("UpdateRemind", updatedRemind).Return(nil).Once()
+	s.On("CreateRemindEvent", mock.MatchedBy(func(e store.RemindEvent) bool {
+		return e.RemindID == id && e.Type == store.EventReminderSent
+	})).Return(nil).Once()
+
+	r, err := New(s, n, clock.NewFake(testNow), CatchUpSummary, time.UTC)
+	require.NoError(t, err)
+
+	r.Process(context.Background())
+
+	due, ok := r.queue.next()
+	require.True(t, ok)
+	assert.Equal(t, testNow.Add(30*time.Minute), due)
+
+	s.AssertExpectations(t)
+	n.AssertExpectations(t)
+}
+
+func TestReminder_Process_sendWarning(t *testing.T) {
+	id := store.NewID()
 
 	remind := store.Remind{
 		ID:            id,
@@ -289,6 +685,237 @@ func TestReminder_Process_sendTimeoutRemind_sendMessageError(t *testing.T) {
 		PetName:       "pet",
 		Character:     "character",
 		ReminderSent:  true,
+		TimeoutRemind: testNow.Add(time.Hour),
+		Warnings:      []time.Duration{time.Hour, 15 * time.Minute},
+	}
+
+	s := &storerMock{}
+	s.On("ListAllReminds").Return([]store.Remind{remind}, nil).Once()
+	s.On("GetUserSettings", "discordUser").
+		Return(store.UserSettings{UserID: "discordUser", Timezone: "Europe/Paris"}, nil).
+		Once()
+
+	n := &notifierMock{}
+	wantMessage := fmt.Sprintf("<@discordUser> Il faut nourrir \"pet\" sur character avant le Tue, 18 Jan 2022 12:00:00 CET, sinon il ratera son repas.\nID: %s", id)
+	n.On("Notify", wantMessage).Return(nil).Once()
+
+	updatedRemind := remind
+	updatedRemind.Warnings = []time.Duration{15 * time.Minute}
+	s.On("UpdateRemind", updatedRemind).Return(nil).Once()
+
+	r, err := New(s, n, clock.NewFake(testNow), CatchUpSummary, time.UTC)
+	require.NoError(t, err)
+
+	r.Process(context.Background())
+
+	due, ok := r.queue.next()
+	require.True(t, ok)
+	assert.Equal(t, testNow.Add(45*time.Minute), due)
+
+	s.AssertExpectations(t)
+	n.AssertExpectations(t)
+}
+
+func TestReminder_Process_sendWarning_notifyError(t *testing.T) {
+	remind := store.Remind{
+		ID:            store.NewID(),
+		DiscordUserID: "discordUser",
+		PetName:       "pet",
+		Character:     "character",
+		ReminderSent:  true,
+		TimeoutRemind: testNow.Add(time.Hour),
+		Warnings:      []time.Duration{time.Hour},
+	}
+
+	s := &storerMock{}
+	s.On("ListAllReminds").Return([]store.Remind{remind}, nil).Once()
+	s.On("GetUserSettings", "discordUser").Return(store.DefaultUserSettings("discordUser"), nil).Once()
+
+	n := &notifierMock{}
+	n.On("Notify", mock.Anything).Return(errors.New("boom")).Once()
+
+	r, err := New(s, n, clock.NewFake(testNow), CatchUpSummary, time.UTC)
+	require.NoError(t, err)
+
+	r.Process(context.Background())
+
+	// The warning is sent again later.
+	due, ok := r.queue.next()
+	require.True(t, ok)
+	assert.Equal(t, testNow.Add(retryDelay), due)
+
+	s.AssertExpectations(t)
+	n.AssertExpectations(t)
+}
+
+func TestReminder_Process_warningCycles(t *testing.T) {
+	ctx := context.Background()
+
+	s := store.NewMemory()
+	err := s.Bootstrap(ctx, store.Pets{{Name: "pet", FoodMinDuration: 5 * time.Hour, FoodMaxDuration: 18 * time.Hour}})
+	require.NoError(t, err)
+
+	settings := store.DefaultUserSettings("discordUser")
+	settings.Warnings = []time.Duration{time.Hour, 15 * time.Minute}
+	err = s.UpsertUserSettings(ctx, settings)
+	require.NoError(t, err)
+
+	remind := store.Remind{
+		ID:            store.NewID(),
+		DiscordUserID: "discordUser",
+		PetName:       "pet",
+		Character:     "character",
+		NextRemind:    testNow.Add(5 * time.Hour),
+		TimeoutRemind: testNow.Add(18 * time.Hour),
+	}
+	err = s.CreateRemind(ctx, remind)
+	require.NoError(t, err)
+
+	clk := clock.NewFake(testNow)
+
+	var got []string
+
+	n := &notifierMock{}
+	n.On("Notify", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		got = append(got, clk.Now().Sub(testNow).String())
+	})
+
+	r, err := New(s, n, clk, CatchUpSummary, time.UTC)
+	require.NoError(t, err)
+
+	// Nobody feeds the pet for two cycles.
+	for i := 0; i < 36*60; i++ {
+		clk.Advance(time.Minute)
+		r.Process(ctx)
+	}
+
+	// Each cycle notifies the reminder, each warning once, then the missed meal.
+	want := []string{
+		"5h0m0s", "17h0m0s", "17h45m0s", "18h0m0s",
+		"23h0m0s", "35h0m0s", "35h45m0s", "36h0m0s",
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestReminder_Process_warningSnoozed(t *testing.T) {
+	ctx := context.Background()
+
+	s := store.NewMemory()
+	err := s.Bootstrap(ctx, store.Pets{{Name: "pet", FoodMinDuration: 5 * time.Hour, FoodMaxDuration: 18 * time.Hour}})
+	require.NoError(t, err)
+
+	settings := store.DefaultUserSettings("discordUser")
+	settings.Warnings = []time.Duration{time.Hour}
+	err = s.UpsertUserSettings(ctx, settings)
+	require.NoError(t, err)
+
+	// The reminder has been snoozed after the instant of the warning.
+	remind := store.Remind{
+		ID:            store.NewID(),
+		DiscordUserID: "discordUser",
+		PetName:       "pet",
+		Character:     "character",
+		NextRemind:    testNow.Add(-time.Hour),
+		SnoozedUntil:  testNow.Add(90 * time.Minute),
+		TimeoutRemind: testNow.Add(2 * time.Hour),
+		Warnings:      []time.Duration{time.Hour},
+	}
+	err = s.CreateRemind(ctx, remind)
+	require.NoError(t, err)
+
+	clk := clock.NewFake(testNow)
+
+	var got []string
+
+	n := &notifierMock{}
+	n.On("Notify", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		got = append(got, clk.Now().Sub(testNow).String())
+	})
+
+	r, err := New(s, n, clk, CatchUpSummary, time.UTC)
+	require.NoError(t, err)
+
+	for i := 0; i < 2*60; i++ {
+		clk.Advance(time.Minute)
+		r.Process(ctx)
+	}
+
+	// The warning is sent during the snooze, then the reminder, then the missed meal.
+	assert.Equal(t, []string{"1h0m0s", "1h30m0s", "2h0m0s"}, got)
+}
+
+func TestReminder_Process_warningsRestart(t *testing.T) {
+	ctx := context.Background()
+
+	s := store.NewMemory()
+	err := s.Bootstrap(ctx, store.Pets{{Name: "pet", FoodMinDuration: 5 * time.Hour, FoodMaxDuration: 18 * time.Hour}})
+	require.NoError(t, err)
+
+	settings := store.DefaultUserSettings("discordUser")
+	settings.Warnings = []time.Duration{time.Hour, 15 * time.Minute}
+	err = s.UpsertUserSettings(ctx, settings)
+	require.NoError(t, err)
+
+	remind := store.Remind{
+		ID:            store.NewID(),
+		DiscordUserID: "discordUser",
+		PetName:       "pet",
+		Character:     "character",
+		NextRemind:    testNow.Add(5 * time.Hour),
+		TimeoutRemind: testNow.Add(18 * time.Hour),
+	}
+	err = s.CreateRemind(ctx, remind)
+	require.NoError(t, err)
+
+	clk := clock.NewFake(testNow)
+
+	var got []string
+
+	n := &notifierMock{}
+	n.On("Notify", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		got = append(got, clk.Now().Sub(testNow).String())
+	})
+
+	r, err := New(s, n, clk, CatchUpSummary, time.UTC)
+	require.NoError(t, err)
+
+	// All the warnings of the cycle are sent.
+	for i := 0; i < 17*60+50; i++ {
+		clk.Advance(time.Minute)
+		r.Process(ctx)
+	}
+
+	stored, err := s.GetRemind(ctx, remind.ID.String())
+	require.NoError(t, err)
+	assert.Empty(t, stored.Warnings)
+
+	// The bot restarts from the stored reminds, and does not warn again.
+	r, err = New(s, n, clk, CatchUpSummary, time.UTC)
+	require.NoError(t, err)
+
+	for i := 0; i < 15; i++ {
+		clk.Advance(time.Minute)
+		r.Process(ctx)
+	}
+
+	want := []string{"5h0m0s", "17h0m0s", "17h45m0s", "18h0m0s"}
+	assert.Equal(t, want, got)
+}
+
+func TestReminder_Process_resume(t *testing.T) {
+	id := store.NewID()
+
+	remind := store.Remind{
+		ID:             id,
+		DiscordUserID:  "discordUser",
+		PetName:        "pet",
+		Character:      "character",
+		MissedReminder: 2,
+		ReminderSent:   true,
+		NextRemind:     testNow.Add(-72 * time.Hour),
+		TimeoutRemind:  testNow.Add(-60 * time.Hour),
+		Paused:         true,
+		PausedUntil:    testNow,
 	}
 
 	pet := store.Pet{
@@ -298,58 +925,63 @@ func TestReminder_Process_sendTimeoutRemind_sendMessageError(t *testing.T) {
 	}
 
 	s := &storerMock{}
-	s.On("ListAllReminds").Return([]store.Remind{remind}, nil).Twice()
+	s.On("ListAllReminds").Return([]store.Remind{remind}, nil).Once()
 	s.On("GetPet", "pet").Return(pet, nil).Once()
+	s.On("GetUserSettings", "discordUser").Return(store.DefaultUserSettings("discordUser"), nil).Once()
 
 	updatedRemind := remind
+	updatedRemind.Paused = false
+	updatedRemind.PausedUntil = time.Time{}
+	updatedRemind.MissedReminder = 0
 	updatedRemind.ReminderSent = false
-	updatedRemind.MissedReminder = 1
+	updatedRemind.CycleStart = testNow
+	updatedRemind.NextRemind = testNow.Add(pet.FoodMinDuration)
+	updatedRemind.TimeoutRemind = testNow.Add(pet.FoodMaxDuration)
 
-	s.On("UpdateRemind", mock.MatchedBy(func(r store.Remind) bool {
-		if time.Now().Add(pet.FoodMinDuration).Sub(r.NextRemind) > time.Minute {
-			return false
-		}
+	s.On("UpdateRemind", updatedRemind).Return(nil).Once()
+	s.On("CreateRemindEvent", mock.MatchedBy(func(e store.RemindEvent) bool {
+		return e.RemindID == id && e.Type == store.EventResumed
+	})).Return(nil).Once()
 
-		if time.Now().Add(pet.FoodMaxDuration).Sub(r.TimeoutRemind) > time.Minute {
-			return false
-		}
+	n := &notifierMock{}
+	wantMessage := fmt.Sprintf("<@discordUser> Fin de la pause: rappel de \"pet\" sur character repris.\nProchain rappel: Tue, 18 Jan 2022 11:00:00 UTC\nID: %s", id)
+	n.On("Notify", wantMessage).Return(nil).Once()
 
-		updatedRemind.NextRemind = r.NextRemind
-		updatedRemind.TimeoutRemind = r.TimeoutRemind
+	r, err := New(s, n, clock.NewFake(testNow), CatchUpSummary, time.UTC)
+	require.NoError(t, err)
 
-		return reflect.DeepEqual(updatedRemind, r)
-	})).Return(nil).Once()
+	r.Process(context.Background())
 
-	d := &discordMock{}
-	d.On("SendMessage", mock.MatchedBy(func(msg string) bool {
-		parts := strings.Split(msg, "\n")
-		reminderPart := parts[0]
-		datePart := parts[1]
-		idPart := parts[2]
-
-		if reminderPart != "<@discordUser> \"pet\" sur character a râté 1 repas." {
-			return false
-		}
-
-		dateParts := strings.SplitN(datePart, ": ", 2)
-		date, err := time.Parse(time.RFC1123, dateParts[1])
-		if err != nil {
-			return false
-		}
-
-		if time.Now().Add(pet.FoodMinDuration).Sub(date) > time.Minute {
-			return false
-		}
-
-		return idPart == "ID: "+id.Hex()
-	})).Return(&discord.Message{}, errors.New("boom")).
-		Once()
+	due, ok := r.queue.next()
+	require.True(t, ok)
+	assert.Equal(t, updatedRemind.NextRemind, due)
+
+	s.AssertExpectations(t)
+	n.AssertExpectations(t)
+}
 
-	r, err := New(s, d)
+func TestReminder_Process_resume_getPetError(t *testing.T) {
+	remind := store.Remind{
+		ID:            store.NewID(),
+		DiscordUserID: "discordUser",
+		PetName:       "pet",
+		Paused:        true,
+		PausedUntil:   testNow,
+	}
+
+	s := &storerMock{}
+	s.On("ListAllReminds").Return([]store.Remind{remind}, nil).Once()
+	s.On("GetPet", "pet").Return(store.Pet{}, errors.New("boom")).Once()
+
+	r, err := New(s, &notifierMock{}, clock.NewFake(testNow), CatchUpSummary, time.UTC)
 	require.NoError(t, err)
 
 	r.Process(context.Background())
 
+	// The remind is resumed again later.
+	due, ok := r.queue.next()
+	require.True(t, ok)
+	assert.Equal(t, testNow.Add(retryDelay), due)
+
 	s.AssertExpectations(t)
-	d.AssertExpectations(t)
 }
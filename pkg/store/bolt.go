@@ -0,0 +1,591 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var (
+	petBucket       = []byte(petCollection)
+	petNameBucket   = []byte(petCollection + "_uniq_name")
+	remindBucket    = []byte(remindCollection)
+	eventBucket     = []byte(eventCollection)
+	outboxBucket    = []byte(outboxCollection)
+	messageBucket   = []byte(messageCollection)
+	settingsBucket  = []byte(settingsCollection)
+	guildBucket     = []byte(guildCollection)
+	errDuplicateKey = errors.New("duplicate key")
+)
+
+// Bolt represents a store backed by a single bbolt file.
+type Bolt struct {
+	db *bolt.DB
+}
+
+// NewBolt opens, or creates, the bbolt file at the given path.
+// The buckets are created along with the file, so the store can be read before being bootstrapped.
+func NewBolt(path string) (*Bolt, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open %q: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{petBucket, petNameBucket, remindBucket, eventBucket, outboxBucket, messageBucket, settingsBucket, guildBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return fmt.Errorf("create bucket %q: %w", name, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+
+		return nil, fmt.Errorf("create buckets: %w", err)
+	}
+
+	return &Bolt{db: db}, nil
+}
+
+// Close closes the underlying file.
+func (b *Bolt) Close() error {
+	return b.db.Close()
+}
+
+// Bootstrap boostraps the database and upserts the given pets.
+func (b *Bolt) Bootstrap(_ context.Context, pets Pets) error {
+	if err := b.initData(pets); err != nil {
+		return fmt.Errorf("init data: %w", err)
+	}
+
+	return nil
+}
+
+func (b *Bolt) initData(pets Pets) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		for _, pet := range pets {
+			if err := upsertPet(tx, pet); err != nil {
+				return fmt.Errorf("upsert pet %q: %w", pet.Name, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// upsertPet inserts or updates the pet, using the name as a unique key.
+func upsertPet(tx *bolt.Tx, pet Pet) error {
+	names := tx.Bucket(petNameBucket)
+
+	if id := names.Get([]byte(pet.Name)); id != nil {
+		pet.ID = ID(id)
+	} else {
+		pet.ID = NewID()
+		if err := names.Put([]byte(pet.Name), []byte(pet.ID)); err != nil {
+			return fmt.Errorf("put pet name: %w", err)
+		}
+	}
+
+	data, err := bson.Marshal(pet)
+	if err != nil {
+		return fmt.Errorf("marshal pet: %w", err)
+	}
+
+	if err = tx.Bucket(petBucket).Put([]byte(pet.ID), data); err != nil {
+		return fmt.Errorf("put pet: %w", err)
+	}
+
+	return nil
+}
+
+// ListPets lists all pets.
+func (b *Bolt) ListPets(_ context.Context) (Pets, error) {
+	var pets Pets
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(petBucket).ForEach(func(_, v []byte) error {
+			var pet Pet
+			if err := bson.Unmarshal(v, &pet); err != nil {
+				return fmt.Errorf("decode pet: %w", err)
+			}
+
+			pets = append(pets, pet)
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list pets: %w", err)
+	}
+
+	return pets, nil
+}
+
+// GetPet returns a pet by the given name.
+func (b *Bolt) GetPet(_ context.Context, name string) (Pet, error) {
+	var pet Pet
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		id := tx.Bucket(petNameBucket).Get([]byte(name))
+		if id == nil {
+			return NotFoundError{Err: errors.New("pet not found")}
+		}
+
+		data := tx.Bucket(petBucket).Get(id)
+		if data == nil {
+			return NotFoundError{Err: errors.New("pet not found")}
+		}
+
+		return bson.Unmarshal(data, &pet)
+	})
+	if err != nil {
+		if errors.As(err, &NotFoundError{}) {
+			return Pet{}, err
+		}
+
+		return Pet{}, fmt.Errorf("find: %w", err)
+	}
+
+	return pet, nil
+}
+
+// CreateRemind creates a new remind.
+func (b *Bolt) CreateRemind(_ context.Context, remind Remind) error {
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(remindBucket)
+		if bucket.Get([]byte(remind.ID)) != nil {
+			return errDuplicateKey
+		}
+
+		return putRemind(bucket, remind)
+	})
+	if err != nil {
+		return fmt.Errorf("create remind: %w", err)
+	}
+
+	return nil
+}
+
+// GetRemind gets a remind with the given ID.
+func (b *Bolt) GetRemind(_ context.Context, id string) (Remind, error) {
+	remindID, err := ParseID(id)
+	if err != nil {
+		return Remind{}, fmt.Errorf("parse id: %w", err)
+	}
+
+	var remind Remind
+
+	err = b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(remindBucket).Get([]byte(remindID))
+		if data == nil {
+			return NotFoundError{Err: errors.New("remind not found")}
+		}
+
+		return bson.Unmarshal(data, &remind)
+	})
+	if err != nil {
+		if errors.As(err, &NotFoundError{}) {
+			return Remind{}, err
+		}
+
+		return Remind{}, fmt.Errorf("find remind: %w", err)
+	}
+
+	return remind, nil
+}
+
+// UpdateRemind updates the given remind.
+func (b *Bolt) UpdateRemind(_ context.Context, remind Remind) error {
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(remindBucket)
+		if bucket.Get([]byte(remind.ID)) == nil {
+			return nil
+		}
+
+		return putRemind(bucket, remind)
+	})
+	if err != nil {
+		return fmt.Errorf("update remind: %w", err)
+	}
+
+	return nil
+}
+
+func putRemind(bucket *bolt.Bucket, remind Remind) error {
+	data, err := bson.Marshal(remind)
+	if err != nil {
+		return fmt.Errorf("marshal remind: %w", err)
+	}
+
+	return bucket.Put([]byte(remind.ID), data)
+}
+
+// ListAllReminds lists all the reminds.
+func (b *Bolt) ListAllReminds(_ context.Context) ([]Remind, error) {
+	return b.listReminds(func(Remind) bool { return true })
+}
+
+// ListRemindsByID lists all the reminds for the given user ID.
+func (b *Bolt) ListRemindsByID(_ context.Context, id string) ([]Remind, error) {
+	return b.listReminds(func(r Remind) bool { return r.DiscordUserID == id })
+}
+
+func (b *Bolt) listReminds(filter func(Remind) bool) ([]Remind, error) {
+	var reminds []Remind
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(remindBucket).ForEach(func(_, v []byte) error {
+			var remind Remind
+			if err := bson.Unmarshal(v, &remind); err != nil {
+				return fmt.Errorf("decode remind: %w", err)
+			}
+
+			if filter(remind) {
+				reminds = append(reminds, remind)
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find reminds: %w", err)
+	}
+
+	return reminds, nil
+}
+
+// RemoveRemind removes the remind with the given id.
+func (b *Bolt) RemoveRemind(_ context.Context, id string) error {
+	remindID, err := ParseID(id)
+	if err != nil {
+		return fmt.Errorf("parse id: %w", err)
+	}
+
+	err = b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(remindBucket)
+		if bucket.Get([]byte(remindID)) == nil {
+			return NotFoundError{Err: errors.New("remind not found")}
+		}
+
+		return bucket.Delete([]byte(remindID))
+	})
+	if err != nil {
+		if errors.As(err, &NotFoundError{}) {
+			return err
+		}
+
+		return fmt.Errorf("delete remind: %w", err)
+	}
+
+	return nil
+}
+
+// CreateRemindEvent records a new remind event.
+// Events are stored in a nested bucket per remind.
+func (b *Bolt) CreateRemindEvent(_ context.Context, event RemindEvent) error {
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.Bucket(eventBucket).CreateBucketIfNotExists([]byte(event.RemindID))
+		if err != nil {
+			return fmt.Errorf("create remind bucket: %w", err)
+		}
+
+		data, err := bson.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("marshal event: %w", err)
+		}
+
+		return bucket.Put([]byte(event.ID), data)
+	})
+	if err != nil {
+		return fmt.Errorf("create remind event: %w", err)
+	}
+
+	return nil
+}
+
+// ListRemindEvents lists the events of the given remind, newest first.
+func (b *Bolt) ListRemindEvents(_ context.Context, remindID string, skip, limit int) ([]RemindEvent, error) {
+	id, err := ParseID(remindID)
+	if err != nil {
+		return nil, fmt.Errorf("parse id: %w", err)
+	}
+
+	var events []RemindEvent
+
+	err = b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(eventBucket).Bucket([]byte(id))
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(_, v []byte) error {
+			var event RemindEvent
+			if err := bson.Unmarshal(v, &event); err != nil {
+				return fmt.Errorf("decode event: %w", err)
+			}
+
+			events = append(events, event)
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find remind events: %w", err)
+	}
+
+	return pageEvents(events, skip, limit), nil
+}
+
+// CreateOutboxMessage adds a message to the outbox.
+func (b *Bolt) CreateOutboxMessage(_ context.Context, msg OutboxMessage) error {
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		return putOutboxMessage(tx.Bucket(outboxBucket), msg)
+	})
+	if err != nil {
+		return fmt.Errorf("create outbox message: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateOutboxMessage updates a message of the outbox.
+func (b *Bolt) UpdateOutboxMessage(_ context.Context, msg OutboxMessage) error {
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(outboxBucket)
+		if bucket.Get([]byte(msg.ID)) == nil {
+			return NotFoundError{Err: errors.New("outbox message not found")}
+		}
+
+		return putOutboxMessage(bucket, msg)
+	})
+	if err != nil {
+		return fmt.Errorf("update outbox message: %w", err)
+	}
+
+	return nil
+}
+
+// ListPendingOutboxMessages lists the messages waiting to be delivered, by next attempt.
+func (b *Bolt) ListPendingOutboxMessages(_ context.Context) ([]OutboxMessage, error) {
+	var msgs []OutboxMessage
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		var err error
+		msgs, err = outboxMessages(tx.Bucket(outboxBucket), func(msg OutboxMessage) bool { return msg.Status == OutboxPending })
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find outbox messages: %w", err)
+	}
+
+	return pendingOutboxMessages(msgs), nil
+}
+
+// RequeueDeadOutboxMessages gives the dead messages a new series of attempts, starting at the given time.
+// It returns the number of requeued messages.
+func (b *Bolt) RequeueDeadOutboxMessages(_ context.Context, at time.Time) (int, error) {
+	var n int
+
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(outboxBucket)
+
+		dead, err := outboxMessages(bucket, func(msg OutboxMessage) bool { return msg.Status == OutboxDead })
+		if err != nil {
+			return err
+		}
+
+		for _, msg := range dead {
+			if err = putOutboxMessage(bucket, msg.requeue(at)); err != nil {
+				return err
+			}
+		}
+
+		n = len(dead)
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("requeue outbox messages: %w", err)
+	}
+
+	return n, nil
+}
+
+// PruneOutboxMessages removes the sent messages created before the given time.
+func (b *Bolt) PruneOutboxMessages(_ context.Context, before time.Time) error {
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(outboxBucket)
+
+		sent, err := outboxMessages(bucket, func(msg OutboxMessage) bool { return msg.prunable(before) })
+		if err != nil {
+			return err
+		}
+
+		// The bucket cannot be modified while iterating over it.
+		for _, msg := range sent {
+			if err = bucket.Delete([]byte(msg.ID)); err != nil {
+				return fmt.Errorf("delete outbox message: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("prune outbox messages: %w", err)
+	}
+
+	return nil
+}
+
+// outboxMessages returns the messages of the bucket matching the filter.
+func outboxMessages(bucket *bolt.Bucket, filter func(OutboxMessage) bool) ([]OutboxMessage, error) {
+	var msgs []OutboxMessage
+
+	err := bucket.ForEach(func(_, v []byte) error {
+		var msg OutboxMessage
+		if err := bson.Unmarshal(v, &msg); err != nil {
+			return fmt.Errorf("decode outbox message: %w", err)
+		}
+
+		if filter(msg) {
+			msgs = append(msgs, msg)
+		}
+
+		return nil
+	})
+
+	return msgs, err
+}
+
+func putOutboxMessage(bucket *bolt.Bucket, msg OutboxMessage) error {
+	data, err := bson.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal outbox message: %w", err)
+	}
+
+	return bucket.Put([]byte(msg.ID), data)
+}
+
+// CreateRemindMessage records the remind a Discord message is about.
+func (b *Bolt) CreateRemindMessage(_ context.Context, msg RemindMessage) error {
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(messageBucket)
+		if bucket.Get([]byte(msg.MessageID)) != nil {
+			return fmt.Errorf("duplicate message id %q", msg.MessageID)
+		}
+
+		data, err := bson.Marshal(msg)
+		if err != nil {
+			return fmt.Errorf("marshal remind message: %w", err)
+		}
+
+		return bucket.Put([]byte(msg.MessageID), data)
+	})
+	if err != nil {
+		return fmt.Errorf("create remind message: %w", err)
+	}
+
+	return nil
+}
+
+// GetRemindMessage gets the remind message of the given Discord message ID.
+func (b *Bolt) GetRemindMessage(_ context.Context, messageID string) (RemindMessage, error) {
+	var msg RemindMessage
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(messageBucket).Get([]byte(messageID))
+		if data == nil {
+			return NotFoundError{Err: errors.New("remind message not found")}
+		}
+
+		return bson.Unmarshal(data, &msg)
+	})
+	if err != nil {
+		if errors.As(err, &NotFoundError{}) {
+			return RemindMessage{}, err
+		}
+
+		return RemindMessage{}, fmt.Errorf("find remind message: %w", err)
+	}
+
+	return msg, nil
+}
+
+// GetUserSettings gets the settings of the given user, the default settings are returned when none are stored.
+func (b *Bolt) GetUserSettings(_ context.Context, userID string) (UserSettings, error) {
+	settings := DefaultUserSettings(userID)
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(settingsBucket).Get([]byte(userID))
+		if data == nil {
+			return nil
+		}
+
+		return bson.Unmarshal(data, &settings)
+	})
+	if err != nil {
+		return UserSettings{}, fmt.Errorf("find user settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// UpsertUserSettings creates or replaces the settings of a user.
+func (b *Bolt) UpsertUserSettings(_ context.Context, settings UserSettings) error {
+	data, err := bson.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("marshal user settings: %w", err)
+	}
+
+	err = b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(settingsBucket).Put([]byte(settings.UserID), data)
+	})
+	if err != nil {
+		return fmt.Errorf("upsert user settings: %w", err)
+	}
+
+	return nil
+}
+
+// GetGuildSettings gets the settings of the given guild, empty settings are returned when none are stored.
+func (b *Bolt) GetGuildSettings(_ context.Context, guildID string) (GuildSettings, error) {
+	settings := GuildSettings{GuildID: guildID}
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(guildBucket).Get([]byte(guildID))
+		if data == nil {
+			return nil
+		}
+
+		return bson.Unmarshal(data, &settings)
+	})
+	if err != nil {
+		return GuildSettings{}, fmt.Errorf("find guild settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// UpsertGuildSettings creates or replaces the settings of a guild.
+func (b *Bolt) UpsertGuildSettings(_ context.Context, settings GuildSettings) error {
+	data, err := bson.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("marshal guild settings: %w", err)
+	}
+
+	err = b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(guildBucket).Put([]byte(settings.GuildID), data)
+	})
+	if err != nil {
+		return fmt.Errorf("upsert guild settings: %w", err)
+	}
+
+	return nil
+}
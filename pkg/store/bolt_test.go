@@ -0,0 +1,121 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+func createBolt(t *testing.T, reminds []Remind) *Bolt {
+	t.Helper()
+
+	ctx := context.Background()
+
+	store, err := NewBolt(filepath.Join(t.TempDir(), "petreminder.db"))
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, store.Close())
+	})
+
+	err = store.Bootstrap(ctx, testPets(t))
+	require.NoError(t, err)
+
+	for _, q := range reminds {
+		err = store.CreateRemind(ctx, q)
+		require.NoError(t, err)
+	}
+
+	return store
+}
+
+func TestBolt_readBeforeBootstrap(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := NewBolt(filepath.Join(t.TempDir(), "petreminder.db"))
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, s.Close())
+	})
+
+	msgs, err := s.ListPendingOutboxMessages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, msgs)
+
+	reminds, err := s.ListAllReminds(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, reminds)
+
+	pets, err := s.ListPets(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, pets)
+}
+
+func TestBolt_persistence(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "petreminder.db")
+
+	s, err := NewBolt(path)
+	require.NoError(t, err)
+
+	err = s.Bootstrap(ctx, testPets(t))
+	require.NoError(t, err)
+
+	remind := testRemind("discordUser")
+	err = s.CreateRemind(ctx, remind)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Close())
+
+	s, err = NewBolt(path)
+	require.NoError(t, err)
+
+	defer func() { _ = s.Close() }()
+
+	err = s.Bootstrap(ctx, testPets(t))
+	require.NoError(t, err)
+
+	got, err := s.GetRemind(ctx, remind.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, remind, got)
+
+	gotPets, err := s.ListPets(ctx)
+	require.NoError(t, err)
+	assert.Len(t, gotPets, len(testPets(t)))
+}
+
+func TestBolt_upsertPet_uniqueName(t *testing.T) {
+	ctx := context.Background()
+	s := createBolt(t, nil)
+
+	pet, err := s.GetPet(ctx, "Chacha")
+	require.NoError(t, err)
+
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		return upsertPet(tx, Pet{ID: NewID(), Name: "Chacha", FoodMinDuration: time.Hour, FoodMaxDuration: 2 * time.Hour})
+	})
+	require.NoError(t, err)
+
+	got, err := s.GetPet(ctx, "Chacha")
+	require.NoError(t, err)
+	assert.Equal(t, pet.ID, got.ID)
+	assert.Equal(t, time.Hour, got.FoodMinDuration)
+
+	gotPets, err := s.ListPets(ctx)
+	require.NoError(t, err)
+	assert.Len(t, gotPets, len(testPets(t)))
+}
+
+func TestBolt_CreateRemind_duplicateID(t *testing.T) {
+	remind := testRemind("discordUser")
+	s := createBolt(t, []Remind{remind})
+
+	err := s.CreateRemind(context.Background(), remind)
+	require.ErrorIs(t, err, errDuplicateKey)
+}
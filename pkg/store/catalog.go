@@ -0,0 +1,146 @@
+package store
+
+import (
+	_ "embed" // Embeds the default pet catalog.
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed pets.yaml
+var defaultCatalog []byte
+
+var knownStats = map[string]struct{}{
+	"agilite":                       {},
+	"chance":                        {},
+	"dommage":                       {},
+	"force":                         {},
+	"initiative":                    {},
+	"intelligence":                  {},
+	"pods":                          {},
+	"pourcentage_dommage":           {},
+	"pourcentage_resistance_air":    {},
+	"pourcentage_resistance_eau":    {},
+	"pourcentage_resistance_feu":    {},
+	"pourcentage_resistance_neutre": {},
+	"pourcentage_resistance_terre":  {},
+	"prospection":                   {},
+	"sagesse":                       {},
+	"soin":                          {},
+	"vitalite":                      {},
+}
+
+type catalog struct {
+	Pets []catalogPet `yaml:"pets"`
+}
+
+type catalogPet struct {
+	Name            string         `yaml:"name"`
+	Aliases         []string       `yaml:"aliases"`
+	Image           string         `yaml:"image"`
+	FoodMinDuration string         `yaml:"foodMinDuration"`
+	FoodMaxDuration string         `yaml:"foodMaxDuration"`
+	StatsMax        map[string]int `yaml:"statsMax"`
+}
+
+// DefaultPets returns the built-in pet catalog.
+func DefaultPets() (Pets, error) {
+	return ParsePets(defaultCatalog)
+}
+
+// LoadPets loads the pet catalog stored in the given YAML or JSON file.
+func LoadPets(path string) (Pets, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %q: %w", path, err)
+	}
+
+	return ParsePets(data)
+}
+
+// ParsePets parses and validates a YAML or JSON pet catalog.
+func ParsePets(data []byte) (Pets, error) {
+	var c catalog
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("decode catalog: %w", err)
+	}
+
+	if len(c.Pets) == 0 {
+		return nil, errors.New("catalog has no pets")
+	}
+
+	pets := make(Pets, 0, len(c.Pets))
+	names := make(map[string]struct{}, len(c.Pets))
+
+	for i, p := range c.Pets {
+		pet, err := p.toPet()
+		if err != nil {
+			return nil, fmt.Errorf("pet %d (%q): %w", i, p.Name, err)
+		}
+
+		// Names and aliases are compared like they are looked up, so each of them finds a single pet.
+		for _, name := range pet.names() {
+			if _, ok := names[normalizePetName(name)]; ok {
+				return nil, fmt.Errorf("pet %d: duplicate name %q", i, name)
+			}
+
+			names[normalizePetName(name)] = struct{}{}
+		}
+
+		pets = append(pets, pet)
+	}
+
+	return pets, nil
+}
+
+func (p catalogPet) toPet() (Pet, error) {
+	if p.Name == "" {
+		return Pet{}, errors.New("name cannot be empty")
+	}
+
+	minDuration, err := time.ParseDuration(p.FoodMinDuration)
+	if err != nil {
+		return Pet{}, fmt.Errorf("foodMinDuration: %w", err)
+	}
+
+	maxDuration, err := time.ParseDuration(p.FoodMaxDuration)
+	if err != nil {
+		return Pet{}, fmt.Errorf("foodMaxDuration: %w", err)
+	}
+
+	if minDuration <= 0 {
+		return Pet{}, errors.New("foodMinDuration must be positive")
+	}
+
+	if minDuration >= maxDuration {
+		return Pet{}, fmt.Errorf("foodMinDuration (%s) must be lower than foodMaxDuration (%s)", minDuration, maxDuration)
+	}
+
+	for stat, value := range p.StatsMax {
+		if _, ok := knownStats[stat]; !ok {
+			return Pet{}, fmt.Errorf("unknown stat %q", stat)
+		}
+
+		if value <= 0 {
+			return Pet{}, fmt.Errorf("stat %q must be positive", stat)
+		}
+	}
+
+	for _, alias := range p.Aliases {
+		if normalizePetName(alias) == "" {
+			return Pet{}, errors.New("alias cannot be empty")
+		}
+	}
+
+	return Pet{
+		Name:            p.Name,
+		Aliases:         p.Aliases,
+		Image:           p.Image,
+		FoodMinDuration: minDuration,
+		FoodMaxDuration: maxDuration,
+		StatsMax:        p.StatsMax,
+	}, nil
+}
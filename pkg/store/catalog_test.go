@@ -0,0 +1,171 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPets(t *testing.T) Pets {
+	t.Helper()
+
+	pets, err := DefaultPets()
+	require.NoError(t, err)
+
+	return pets
+}
+
+func TestDefaultPets(t *testing.T) {
+	pets, err := DefaultPets()
+	require.NoError(t, err)
+
+	assert.Len(t, pets, 35)
+	assert.Equal(t, Pet{
+		Name:            "Chacha",
+		FoodMinDuration: 5 * time.Hour,
+		FoodMaxDuration: 18 * time.Hour,
+		StatsMax:        map[string]int{"intelligence": 80, "pourcentage_resistance_neutre": 20, "agilite": 80, "vitalite": 80, "force": 80},
+	}, pets[0])
+}
+
+func TestLoadPets(t *testing.T) {
+	tests := []struct {
+		desc    string
+		file    string
+		content string
+	}{
+		{
+			desc: "yaml",
+			file: "pets.yaml",
+			content: `pets:
+  - name: Chacha
+    foodMinDuration: 5h
+    foodMaxDuration: 18h
+    statsMax:
+      force: 80
+`,
+		},
+		{
+			desc:    "json",
+			file:    "pets.json",
+			content: `{"pets": [{"name": "Chacha", "foodMinDuration": "5h", "foodMaxDuration": "18h", "statsMax": {"force": 80}}]}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), test.file)
+			err := os.WriteFile(path, []byte(test.content), 0o600)
+			require.NoError(t, err)
+
+			got, err := LoadPets(path)
+			require.NoError(t, err)
+
+			want := Pets{{
+				Name:            "Chacha",
+				FoodMinDuration: 5 * time.Hour,
+				FoodMaxDuration: 18 * time.Hour,
+				StatsMax:        map[string]int{"force": 80},
+			}}
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestParsePets_validation(t *testing.T) {
+	tests := []struct {
+		desc    string
+		content string
+	}{
+		{
+			desc:    "invalid document",
+			content: `pets: {`,
+		},
+		{
+			desc:    "no pets",
+			content: `pets: []`,
+		},
+		{
+			desc: "empty name",
+			content: `pets:
+  - foodMinDuration: 5h
+    foodMaxDuration: 18h`,
+		},
+		{
+			desc: "invalid duration",
+			content: `pets:
+  - name: Chacha
+    foodMinDuration: 5 heures
+    foodMaxDuration: 18h`,
+		},
+		{
+			desc: "min greater than max",
+			content: `pets:
+  - name: Chacha
+    foodMinDuration: 18h
+    foodMaxDuration: 5h`,
+		},
+		{
+			desc: "min equals max",
+			content: `pets:
+  - name: Chacha
+    foodMinDuration: 5h
+    foodMaxDuration: 5h`,
+		},
+		{
+			desc: "duplicate name",
+			content: `pets:
+  - name: Chacha
+    foodMinDuration: 5h
+    foodMaxDuration: 18h
+  - name: Chacha
+    foodMinDuration: 5h
+    foodMaxDuration: 18h`,
+		},
+		{
+			desc: "alias of another pet",
+			content: `pets:
+  - name: Chacha
+    foodMinDuration: 5h
+    foodMaxDuration: 18h
+  - name: Chacha_Tigre
+    aliases: [chacha]
+    foodMinDuration: 5h
+    foodMaxDuration: 18h`,
+		},
+		{
+			desc: "empty alias",
+			content: `pets:
+  - name: Chacha
+    aliases: [" _ "]
+    foodMinDuration: 5h
+    foodMaxDuration: 18h`,
+		},
+		{
+			desc: "unknown stat",
+			content: `pets:
+  - name: Chacha
+    foodMinDuration: 5h
+    foodMaxDuration: 18h
+    statsMax:
+      puissance: 80`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParsePets([]byte(test.content))
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,437 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type storeFactory func(t *testing.T, reminds []Remind) Store
+
+func TestMongo_conformance(t *testing.T) {
+	testConformance(t, func(t *testing.T, reminds []Remind) Store {
+		t.Helper()
+
+		return createMongo(t, reminds)
+	})
+}
+
+func TestMemory_conformance(t *testing.T) {
+	testConformance(t, func(t *testing.T, reminds []Remind) Store {
+		t.Helper()
+
+		return createMemory(t, reminds)
+	})
+}
+
+func TestBolt_conformance(t *testing.T) {
+	testConformance(t, func(t *testing.T, reminds []Remind) Store {
+		t.Helper()
+
+		return createBolt(t, reminds)
+	})
+}
+
+func testConformance(t *testing.T, factory storeFactory) {
+	t.Helper()
+
+	t.Run("list pets", func(t *testing.T) {
+		s := factory(t, nil)
+
+		got, err := s.ListPets(context.Background())
+		require.NoError(t, err)
+
+		p := testPets(t)
+		require.Len(t, got, len(p))
+
+		for i := range p {
+			p[i].ID = got[i].ID
+		}
+
+		assert.Equal(t, p, got)
+	})
+
+	t.Run("bootstrap twice", func(t *testing.T) {
+		s := factory(t, nil)
+
+		err := s.Bootstrap(context.Background(), testPets(t))
+		require.NoError(t, err)
+
+		got, err := s.ListPets(context.Background())
+		require.NoError(t, err)
+
+		assert.Len(t, got, len(testPets(t)))
+	})
+
+	t.Run("bootstrap upserts pets", func(t *testing.T) {
+		ctx := context.Background()
+		s := factory(t, nil)
+
+		before, err := s.GetPet(ctx, "Chacha")
+		require.NoError(t, err)
+
+		update := Pets{
+			{Name: "Chacha", FoodMinDuration: time.Hour, FoodMaxDuration: 2 * time.Hour, StatsMax: map[string]int{"force": 10}},
+			{Name: "Nouveau", FoodMinDuration: time.Hour, FoodMaxDuration: 3 * time.Hour},
+		}
+
+		err = s.Bootstrap(ctx, update)
+		require.NoError(t, err)
+
+		got, err := s.GetPet(ctx, "Chacha")
+		require.NoError(t, err)
+
+		update[0].ID = before.ID
+		assert.Equal(t, update[0], got)
+
+		got, err = s.GetPet(ctx, "Nouveau")
+		require.NoError(t, err)
+		assert.Equal(t, 3*time.Hour, got.FoodMaxDuration)
+
+		all, err := s.ListPets(ctx)
+		require.NoError(t, err)
+		assert.Len(t, all, len(testPets(t))+1)
+	})
+
+	t.Run("get pet", func(t *testing.T) {
+		s := factory(t, nil)
+
+		got, err := s.GetPet(context.Background(), "Chacha")
+		require.NoError(t, err)
+
+		assert.Equal(t, "Chacha", got.Name)
+		assert.Equal(t, 5*time.Hour, got.FoodMinDuration)
+		assert.Equal(t, 18*time.Hour, got.FoodMaxDuration)
+	})
+
+	t.Run("get unknown pet", func(t *testing.T) {
+		s := factory(t, nil)
+
+		_, err := s.GetPet(context.Background(), "Unknown")
+		require.ErrorAs(t, err, &NotFoundError{})
+	})
+
+	t.Run("create and get remind", func(t *testing.T) {
+		ctx := context.Background()
+		s := factory(t, nil)
+
+		remind := testRemind("discordUser")
+		err := s.CreateRemind(ctx, remind)
+		require.NoError(t, err)
+
+		got, err := s.GetRemind(ctx, remind.ID.String())
+		require.NoError(t, err)
+
+		assert.Equal(t, remind, got)
+	})
+
+	t.Run("get unknown remind", func(t *testing.T) {
+		s := factory(t, nil)
+
+		_, err := s.GetRemind(context.Background(), NewID().String())
+		require.ErrorAs(t, err, &NotFoundError{})
+	})
+
+	t.Run("update remind", func(t *testing.T) {
+		ctx := context.Background()
+		reminds := []Remind{testRemind("discordUser"), testRemind("discordUser2")}
+		s := factory(t, reminds)
+
+		update := reminds[0]
+		update.Character = "Toto"
+		update.MissedReminder = 10
+		update.ReminderSent = true
+		update.NextRemind = time.Time{}.Add(time.Hour)
+		update.TimeoutRemind = time.Time{}.Add(2 * time.Hour)
+		update.Warnings = []time.Duration{time.Hour}
+		update.Paused = true
+		update.PausedUntil = time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+
+		err := s.UpdateRemind(ctx, update)
+		require.NoError(t, err)
+
+		got, err := s.ListAllReminds(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, []Remind{update, reminds[1]}, got)
+	})
+
+	t.Run("update remind clears fields", func(t *testing.T) {
+		ctx := context.Background()
+		remind := testRemind("discordUser")
+		remind.Warnings = []time.Duration{time.Hour}
+		remind.Paused = true
+		remind.PausedUntil = time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+		s := factory(t, []Remind{remind})
+
+		remind.Warnings = nil
+		remind.Paused = false
+		remind.PausedUntil = time.Time{}
+
+		err := s.UpdateRemind(ctx, remind)
+		require.NoError(t, err)
+
+		got, err := s.GetRemind(ctx, remind.ID.String())
+		require.NoError(t, err)
+
+		assert.Equal(t, remind, got)
+	})
+
+	t.Run("list reminds by id", func(t *testing.T) {
+		reminds := []Remind{testRemind("discordUser"), testRemind("discordUser2"), testRemind("discordUser")}
+		s := factory(t, reminds)
+
+		got, err := s.ListRemindsByID(context.Background(), "discordUser")
+		require.NoError(t, err)
+
+		assert.Equal(t, []Remind{reminds[0], reminds[2]}, got)
+	})
+
+	t.Run("remove remind", func(t *testing.T) {
+		ctx := context.Background()
+		reminds := []Remind{testRemind("discordUser"), testRemind("discordUser2")}
+		s := factory(t, reminds)
+
+		err := s.RemoveRemind(ctx, reminds[0].ID.String())
+		require.NoError(t, err)
+
+		got, err := s.ListAllReminds(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, []Remind{reminds[1]}, got)
+	})
+
+	t.Run("remove unknown remind", func(t *testing.T) {
+		s := factory(t, nil)
+
+		err := s.RemoveRemind(context.Background(), NewID().String())
+		require.ErrorAs(t, err, &NotFoundError{})
+	})
+
+	t.Run("list remind events", func(t *testing.T) {
+		ctx := context.Background()
+		remind := testRemind("discordUser")
+		s := factory(t, []Remind{remind})
+
+		start := time.Date(2022, 1, 10, 12, 0, 0, 0, time.UTC)
+		types := []EventType{EventCreated, EventReminderSent, EventFed}
+
+		var events []RemindEvent
+		for i, typ := range types {
+			event := NewRemindEvent(remind, typ, "discordUser", start.Add(time.Duration(i)*time.Hour))
+			events = append(events, event)
+
+			err := s.CreateRemindEvent(ctx, event)
+			require.NoError(t, err)
+		}
+
+		err := s.CreateRemindEvent(ctx, NewRemindEvent(testRemind("discordUser2"), EventMissed, "", start))
+		require.NoError(t, err)
+
+		got, err := s.ListRemindEvents(ctx, remind.ID.String(), 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []RemindEvent{events[2], events[1], events[0]}, got)
+
+		got, err = s.ListRemindEvents(ctx, remind.ID.String(), 0, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []RemindEvent{events[2], events[1]}, got)
+
+		got, err = s.ListRemindEvents(ctx, remind.ID.String(), 2, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []RemindEvent{events[0]}, got)
+
+		got, err = s.ListRemindEvents(ctx, remind.ID.String(), 3, 2)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("remind events outlive the remind", func(t *testing.T) {
+		ctx := context.Background()
+		remind := testRemind("discordUser")
+		s := factory(t, []Remind{remind})
+
+		event := NewRemindEvent(remind, EventRemoved, "discordUser", time.Date(2022, 1, 10, 12, 0, 0, 0, time.UTC))
+		err := s.CreateRemindEvent(ctx, event)
+		require.NoError(t, err)
+
+		err = s.RemoveRemind(ctx, remind.ID.String())
+		require.NoError(t, err)
+
+		got, err := s.ListRemindEvents(ctx, remind.ID.String(), 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []RemindEvent{event}, got)
+	})
+
+	t.Run("outbox", func(t *testing.T) {
+		ctx := context.Background()
+		s := factory(t, nil)
+
+		start := time.Date(2022, 1, 10, 12, 0, 0, 0, time.UTC)
+
+		later := NewOutboxMessage(NewID(), "later", start.Add(time.Hour))
+		later.To = Recipient{UserID: "1", GuildID: "2", ChannelID: "3"}
+		first := NewOutboxMessage("", "first", start)
+		sent := NewOutboxMessage(NewID(), "sent", start)
+
+		for _, msg := range []OutboxMessage{later, first, sent} {
+			err := s.CreateOutboxMessage(ctx, msg)
+			require.NoError(t, err)
+		}
+
+		sent.Status = OutboxSent
+		sent.Attempts = 1
+		err := s.UpdateOutboxMessage(ctx, sent)
+		require.NoError(t, err)
+
+		got, err := s.ListPendingOutboxMessages(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []OutboxMessage{first, later}, got)
+
+		later.NextAttempt = start.Add(-time.Hour)
+		later.Attempts = 2
+		later.LastError = "boom"
+		err = s.UpdateOutboxMessage(ctx, later)
+		require.NoError(t, err)
+
+		got, err = s.ListPendingOutboxMessages(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []OutboxMessage{later, first}, got)
+	})
+
+	t.Run("outbox prune and requeue", func(t *testing.T) {
+		ctx := context.Background()
+		s := factory(t, nil)
+
+		start := time.Date(2022, 1, 10, 12, 0, 0, 0, time.UTC)
+
+		oldSent := NewOutboxMessage(NewID(), "old sent", start)
+		oldSent.Status = OutboxSent
+		recentSent := NewOutboxMessage(NewID(), "recent sent", start.Add(2*time.Hour))
+		recentSent.Status = OutboxSent
+		dead := NewOutboxMessage(NewID(), "dead", start)
+		dead.Status = OutboxDead
+		dead.Attempts = 10
+		dead.LastError = "boom"
+
+		for _, msg := range []OutboxMessage{oldSent, recentSent, dead} {
+			err := s.CreateOutboxMessage(ctx, msg)
+			require.NoError(t, err)
+		}
+
+		err := s.PruneOutboxMessages(ctx, start.Add(time.Hour))
+		require.NoError(t, err)
+
+		err = s.UpdateOutboxMessage(ctx, oldSent)
+		assert.ErrorAs(t, err, &NotFoundError{})
+
+		err = s.UpdateOutboxMessage(ctx, recentSent)
+		require.NoError(t, err)
+
+		n, err := s.RequeueDeadOutboxMessages(ctx, start.Add(3*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+
+		requeued := dead
+		requeued.Status = OutboxPending
+		requeued.Attempts = 0
+		requeued.NextAttempt = start.Add(3 * time.Hour)
+
+		got, err := s.ListPendingOutboxMessages(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []OutboxMessage{requeued}, got)
+	})
+
+	t.Run("update unknown outbox message", func(t *testing.T) {
+		s := factory(t, nil)
+
+		err := s.UpdateOutboxMessage(context.Background(), NewOutboxMessage("", "unknown", time.Now()))
+		require.ErrorAs(t, err, &NotFoundError{})
+	})
+
+	t.Run("remind messages", func(t *testing.T) {
+		ctx := context.Background()
+		s := factory(t, nil)
+
+		msg := RemindMessage{
+			MessageID: "931234567890123456",
+			RemindID:  NewID(),
+			CreatedAt: time.Date(2022, 1, 10, 12, 0, 0, 0, time.UTC),
+		}
+
+		err := s.CreateRemindMessage(ctx, msg)
+		require.NoError(t, err)
+
+		err = s.CreateRemindMessage(ctx, msg)
+		assert.Error(t, err)
+
+		got, err := s.GetRemindMessage(ctx, msg.MessageID)
+		require.NoError(t, err)
+		assert.Equal(t, msg, got)
+
+		_, err = s.GetRemindMessage(ctx, "unknown")
+		require.ErrorAs(t, err, &NotFoundError{})
+	})
+
+	t.Run("user settings", func(t *testing.T) {
+		ctx := context.Background()
+		s := factory(t, nil)
+
+		got, err := s.GetUserSettings(ctx, "1")
+		require.NoError(t, err)
+		assert.Equal(t, DefaultUserSettings("1"), got)
+
+		settings := UserSettings{UserID: "1", Notify: NotifyDM, Timezone: "Europe/Paris", Locale: "en", Warnings: []time.Duration{time.Hour, 15 * time.Minute}}
+
+		err = s.UpsertUserSettings(ctx, settings)
+		require.NoError(t, err)
+
+		got, err = s.GetUserSettings(ctx, "1")
+		require.NoError(t, err)
+		assert.Equal(t, settings, got)
+
+		settings.Notify = NotifyChannel
+
+		err = s.UpsertUserSettings(ctx, settings)
+		require.NoError(t, err)
+
+		got, err = s.GetUserSettings(ctx, "1")
+		require.NoError(t, err)
+		assert.Equal(t, settings, got)
+
+		got, err = s.GetUserSettings(ctx, "2")
+		require.NoError(t, err)
+		assert.Equal(t, DefaultUserSettings("2"), got)
+	})
+
+	t.Run("guild settings", func(t *testing.T) {
+		ctx := context.Background()
+		s := factory(t, nil)
+
+		got, err := s.GetGuildSettings(ctx, "1")
+		require.NoError(t, err)
+		assert.Equal(t, GuildSettings{GuildID: "1"}, got)
+
+		settings := GuildSettings{GuildID: "1", ChannelID: "42", Locale: "en"}
+
+		err = s.UpsertGuildSettings(ctx, settings)
+		require.NoError(t, err)
+
+		got, err = s.GetGuildSettings(ctx, "1")
+		require.NoError(t, err)
+		assert.Equal(t, settings, got)
+	})
+}
+
+func testRemind(discordUserID string) Remind {
+	return Remind{
+		ID:            NewID(),
+		DiscordUserID: discordUserID,
+		GuildID:       "guild",
+		ChannelID:     "channel",
+		PetName:       "pet",
+		Character:     "character",
+	}
+}
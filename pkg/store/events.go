@@ -0,0 +1,122 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EventType represents the type of a remind event.
+type EventType string
+
+// Event types.
+const (
+	EventCreated      EventType = "created"
+	EventReminderSent EventType = "reminderSent"
+	EventFed          EventType = "fed"
+	EventMissed       EventType = "missed"
+	EventRemoved      EventType = "removed"
+	EventPaused       EventType = "paused"
+	EventResumed      EventType = "resumed"
+	EventEdited       EventType = "edited"
+)
+
+// RemindEvent represents an immutable event in the history of a remind.
+// NextRemind and TimeoutRemind hold the feeding window of the cycle the event belongs to.
+type RemindEvent struct {
+	ID            ID        `bson:"_id"`
+	RemindID      ID        `bson:"remindId"`
+	Type          EventType `bson:"type"`
+	DiscordUserID string    `bson:"discordUserId"`
+	ActorID       string    `bson:"actorId,omitempty"`
+	PetName       string    `bson:"petName"`
+	Character     string    `bson:"character"`
+	NextRemind    time.Time `bson:"nextRemind"`
+	TimeoutRemind time.Time `bson:"timeoutRemind"`
+	CreatedAt     time.Time `bson:"createdAt"`
+}
+
+// NewRemindEvent creates an event of the given type for the remind.
+// The actorID is the Discord user who triggered the event, empty when triggered by the bot.
+func NewRemindEvent(remind Remind, typ EventType, actorID string, at time.Time) RemindEvent {
+	return RemindEvent{
+		ID:            NewID(),
+		RemindID:      remind.ID,
+		Type:          typ,
+		DiscordUserID: remind.DiscordUserID,
+		ActorID:       actorID,
+		PetName:       remind.PetName,
+		Character:     remind.Character,
+		NextRemind:    remind.NextRemind,
+		TimeoutRemind: remind.TimeoutRemind,
+		CreatedAt:     at,
+	}
+}
+
+// CreateRemindEvent records a new remind event.
+func (s *Mongo) CreateRemindEvent(ctx context.Context, event RemindEvent) error {
+	if _, err := s.events.InsertOne(ctx, event); err != nil {
+		return fmt.Errorf("create remind event: %w", err)
+	}
+
+	return nil
+}
+
+// ListRemindEvents lists the events of the given remind, newest first.
+// The first skip events are ignored, and at most limit events are returned when limit is positive.
+func (s *Mongo) ListRemindEvents(ctx context.Context, remindID string, skip, limit int) ([]RemindEvent, error) {
+	id, err := ParseID(remindID)
+	if err != nil {
+		return nil, fmt.Errorf("parse id: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(skip))
+
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+
+	res, err := s.events.Find(ctx, bson.D{{Key: "remindId", Value: id}}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find remind events: %w", err)
+	}
+
+	var events []RemindEvent
+	if err = res.All(ctx, &events); err != nil {
+		return nil, fmt.Errorf("decode remind events: %w", err)
+	}
+
+	return events, nil
+}
+
+// pageEvents sorts the events newest first, and returns the requested page.
+func pageEvents(events []RemindEvent, skip, limit int) []RemindEvent {
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].CreatedAt.Equal(events[j].CreatedAt) {
+			return events[i].ID > events[j].ID
+		}
+
+		return events[i].CreatedAt.After(events[j].CreatedAt)
+	})
+
+	if skip < 0 {
+		skip = 0
+	}
+
+	if skip >= len(events) {
+		return nil
+	}
+
+	events = events[skip:]
+	if limit > 0 && limit < len(events) {
+		events = events[:limit]
+	}
+
+	return events
+}
@@ -0,0 +1,175 @@
+package store
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Find returns the pet whose name or one of its aliases matches the given name.
+// The case, the accents and the separators are ignored, so "dragoune rose" matches Dragoune_Rose.
+func (p Pets) Find(name string) (Pet, bool) {
+	name = normalizePetName(name)
+
+	for _, pet := range p {
+		for _, candidate := range pet.names() {
+			if normalizePetName(candidate) == name {
+				return pet, true
+			}
+		}
+	}
+
+	return Pet{}, false
+}
+
+// Suggest returns at most n pets whose name or aliases are the closest to the given name, closest first.
+// Pets too far from the name to be a misspelling of it are left out.
+func (p Pets) Suggest(name string, n int) Pets {
+	query := []rune(normalizePetName(name))
+	if len(query) == 0 {
+		return nil
+	}
+
+	type suggestion struct {
+		pet      Pet
+		distance int
+	}
+
+	var suggestions []suggestion
+
+	for _, pet := range p {
+		distance := -1
+
+		for _, candidate := range pet.names() {
+			if d := nameDistance(query, []rune(normalizePetName(candidate))); distance == -1 || d < distance {
+				distance = d
+			}
+		}
+
+		if distance <= maxNameDistance(len(query)) {
+			suggestions = append(suggestions, suggestion{pet: pet, distance: distance})
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].distance != suggestions[j].distance {
+			return suggestions[i].distance < suggestions[j].distance
+		}
+
+		return suggestions[i].pet.Name < suggestions[j].pet.Name
+	})
+
+	var pets Pets
+
+	for _, s := range suggestions {
+		if len(pets) == n {
+			break
+		}
+
+		pets = append(pets, s.pet)
+	}
+
+	return pets
+}
+
+// StartingWith returns at most n pets whose name or one of its aliases starts with the given prefix.
+// As in Find, the case, the accents and the separators are ignored.
+func (p Pets) StartingWith(prefix string, n int) Pets {
+	prefix = normalizePetName(prefix)
+
+	var pets Pets
+
+	for _, pet := range p {
+		if len(pets) == n {
+			break
+		}
+
+		for _, candidate := range pet.names() {
+			if strings.HasPrefix(normalizePetName(candidate), prefix) {
+				pets = append(pets, pet)
+
+				break
+			}
+		}
+	}
+
+	return pets
+}
+
+// names returns the name of the pet followed by its aliases.
+func (p Pet) names() []string {
+	return append([]string{p.Name}, p.Aliases...)
+}
+
+// normalizePetName lowercases the name, removes its accents, and replaces its separators by single spaces.
+func normalizePetName(name string) string {
+	var b strings.Builder
+
+	for _, r := range norm.NFD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+		case r == '_' || r == '-':
+			b.WriteRune(' ')
+		default:
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// maxNameDistance is the greatest distance between a name and a misspelling of it of the given length.
+func maxNameDistance(length int) int {
+	return 1 + length/4
+}
+
+// nameDistance is the edit distance between the query and the candidate name.
+// A query typed as the start of the candidate, like "dragoune" for "dragoune rose", is as close as its start.
+func nameDistance(query, candidate []rune) int {
+	distance := levenshtein(query, candidate)
+
+	if len(candidate) > len(query) {
+		if d := levenshtein(query, candidate[:len(query)]); d < distance {
+			return d
+		}
+	}
+
+	return distance
+}
+
+// levenshtein returns the number of rune insertions, deletions and substitutions needed to change a into b.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = minInt(prev[j]+1, minInt(curr[j-1]+1, prev[j-1]+cost))
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
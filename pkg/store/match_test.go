@@ -0,0 +1,145 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPets_Find(t *testing.T) {
+	pets := Pets{
+		{Name: "Chacha"},
+		{Name: "Dragoune_Rose"},
+		{Name: "Ecureuil_Chenapan", Aliases: []string{"Écureuil"}},
+	}
+
+	tests := []struct {
+		name   string
+		want   string
+		wantOk bool
+	}{
+		{name: "Chacha", want: "Chacha", wantOk: true},
+		{name: "CHACHA", want: "Chacha", wantOk: true},
+		{name: "dragoune_rose", want: "Dragoune_Rose", wantOk: true},
+		{name: "Dragoune Rose", want: "Dragoune_Rose", wantOk: true},
+		{name: " dragoune-rose ", want: "Dragoune_Rose", wantOk: true},
+		{name: "Écureuil Chenapan", want: "Ecureuil_Chenapan", wantOk: true},
+		{name: "ecureuil", want: "Ecureuil_Chenapan", wantOk: true},
+		{name: "Dragoune"},
+		{name: ""},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := pets.Find(test.name)
+			require.Equal(t, test.wantOk, ok)
+			assert.Equal(t, test.want, got.Name)
+		})
+	}
+}
+
+func TestPets_Suggest(t *testing.T) {
+	pets := Pets{
+		{Name: "Chacha"},
+		{Name: "Chacha_Tigre"},
+		{Name: "Dragoune_Rose"},
+		{Name: "Bwak_Air"},
+		{Name: "Bwak_Eau"},
+		{Name: "Koalak_Sanguin", Aliases: []string{"Koalak"}},
+		{Name: "Nomoon"},
+	}
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{name: "Chachaa", want: []string{"Chacha", "Chacha_Tigre"}},
+		{name: "dragoun", want: []string{"Dragoune_Rose"}},
+		{name: "Bwak", want: []string{"Bwak_Air", "Bwak_Eau"}},
+		{name: "Bwak Ai", want: []string{"Bwak_Air", "Bwak_Eau"}},
+		{name: "koalk", want: []string{"Koalak_Sanguin"}},
+		{name: "Nomon", want: []string{"Nomoon"}},
+		{name: "Crocodaille"},
+		{name: ""},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+			for _, pet := range pets.Suggest(test.name, 3) {
+				got = append(got, pet.Name)
+			}
+
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestPets_Suggest_limit(t *testing.T) {
+	pets := Pets{{Name: "Pioute_Bleu"}, {Name: "Pioute_Jaune"}, {Name: "Pioute_Rouge"}, {Name: "Pioute_Verte"}}
+
+	assert.Len(t, pets.Suggest("Pioute", 3), 3)
+}
+
+func TestPets_StartingWith(t *testing.T) {
+	pets := Pets{
+		{Name: "Chacha"},
+		{Name: "Dragoune_Rose"},
+		{Name: "Ecureuil_Chenapan", Aliases: []string{"Écureuil"}},
+		{Name: "Vilain_Petit_Corbac", Aliases: []string{"Corbac"}},
+	}
+
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{prefix: "ch", want: []string{"Chacha"}},
+		{prefix: "dragoune rose", want: []string{"Dragoune_Rose"}},
+		{prefix: "Dragoune-R", want: []string{"Dragoune_Rose"}},
+		{prefix: "écur", want: []string{"Ecureuil_Chenapan"}},
+		{prefix: "corb", want: []string{"Vilain_Petit_Corbac"}},
+		{prefix: "", want: []string{"Chacha", "Dragoune_Rose", "Ecureuil_Chenapan"}},
+		{prefix: "zz"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.prefix, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+			for _, pet := range pets.StartingWith(test.prefix, 3) {
+				got = append(got, pet.Name)
+			}
+
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "", b: "", want: 0},
+		{a: "chacha", b: "", want: 6},
+		{a: "", b: "chacha", want: 6},
+		{a: "chacha", b: "chacha", want: 0},
+		{a: "chacha", b: "chachas", want: 1},
+		{a: "chacah", b: "chacha", want: 2},
+		{a: "kitten", b: "sitting", want: 3},
+		{a: "écu", b: "ecu", want: 1},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, levenshtein([]rune(test.a), []rune(test.b)), "%s/%s", test.a, test.b)
+	}
+}
@@ -0,0 +1,394 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Memory represents an in-memory store.
+// Everything is lost when the process stops.
+type Memory struct {
+	mu       sync.RWMutex
+	pets     Pets
+	reminds  []Remind
+	events   []RemindEvent
+	outbox   []OutboxMessage
+	messages map[string]RemindMessage
+	settings map[string]UserSettings
+	guilds   map[string]GuildSettings
+}
+
+// NewMemory creates a new Memory store.
+func NewMemory() *Memory {
+	return &Memory{
+		messages: make(map[string]RemindMessage),
+		settings: make(map[string]UserSettings),
+		guilds:   make(map[string]GuildSettings),
+	}
+}
+
+// Bootstrap boostraps the store and upserts the given pets.
+func (m *Memory) Bootstrap(_ context.Context, pets Pets) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, pet := range pets {
+		pet = copyPet(pet)
+
+		if i := m.petIndex(pet.Name); i != -1 {
+			pet.ID = m.pets[i].ID
+			m.pets[i] = pet
+
+			continue
+		}
+
+		pet.ID = NewID()
+		m.pets = append(m.pets, pet)
+	}
+
+	return nil
+}
+
+// ListPets lists all pets.
+func (m *Memory) ListPets(_ context.Context) (Pets, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	pets := make(Pets, 0, len(m.pets))
+	for _, pet := range m.pets {
+		pets = append(pets, copyPet(pet))
+	}
+
+	return pets, nil
+}
+
+// GetPet returns a pet by the given name.
+func (m *Memory) GetPet(_ context.Context, name string) (Pet, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	i := m.petIndex(name)
+	if i == -1 {
+		return Pet{}, NotFoundError{Err: errors.New("pet not found")}
+	}
+
+	return copyPet(m.pets[i]), nil
+}
+
+// CreateRemind creates a new remind.
+func (m *Memory) CreateRemind(_ context.Context, remind Remind) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.remindIndex(remind.ID) != -1 {
+		return fmt.Errorf("create remind: %w", errors.New("duplicate id"))
+	}
+
+	m.reminds = append(m.reminds, copyRemind(remind))
+
+	return nil
+}
+
+// GetRemind gets a remind with the given ID.
+func (m *Memory) GetRemind(_ context.Context, id string) (Remind, error) {
+	remindID, err := ParseID(id)
+	if err != nil {
+		return Remind{}, fmt.Errorf("parse id: %w", err)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	i := m.remindIndex(remindID)
+	if i == -1 {
+		return Remind{}, NotFoundError{Err: errors.New("remind not found")}
+	}
+
+	return copyRemind(m.reminds[i]), nil
+}
+
+// UpdateRemind updates the given remind.
+func (m *Memory) UpdateRemind(_ context.Context, remind Remind) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if i := m.remindIndex(remind.ID); i != -1 {
+		m.reminds[i] = copyRemind(remind)
+	}
+
+	return nil
+}
+
+// ListAllReminds lists all the reminds.
+func (m *Memory) ListAllReminds(_ context.Context) ([]Remind, error) {
+	return m.listReminds(func(Remind) bool { return true }), nil
+}
+
+// ListRemindsByID lists all the reminds for the given user ID.
+func (m *Memory) ListRemindsByID(_ context.Context, id string) ([]Remind, error) {
+	return m.listReminds(func(r Remind) bool { return r.DiscordUserID == id }), nil
+}
+
+func (m *Memory) listReminds(filter func(Remind) bool) []Remind {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var reminds []Remind
+	for _, remind := range m.reminds {
+		if filter(remind) {
+			reminds = append(reminds, copyRemind(remind))
+		}
+	}
+
+	return reminds
+}
+
+// RemoveRemind removes the remind with the given id.
+func (m *Memory) RemoveRemind(_ context.Context, id string) error {
+	remindID, err := ParseID(id)
+	if err != nil {
+		return fmt.Errorf("parse id: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := m.remindIndex(remindID)
+	if i == -1 {
+		return NotFoundError{Err: errors.New("remind not found")}
+	}
+
+	m.reminds = append(m.reminds[:i], m.reminds[i+1:]...)
+
+	return nil
+}
+
+func (m *Memory) petIndex(name string) int {
+	for i, pet := range m.pets {
+		if pet.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (m *Memory) remindIndex(id ID) int {
+	for i, remind := range m.reminds {
+		if remind.ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func copyRemind(remind Remind) Remind {
+	if remind.Warnings != nil {
+		remind.Warnings = append([]time.Duration(nil), remind.Warnings...)
+	}
+
+	return remind
+}
+
+func copyPet(pet Pet) Pet {
+	if pet.Aliases != nil {
+		pet.Aliases = append([]string(nil), pet.Aliases...)
+	}
+
+	if pet.StatsMax == nil {
+		return pet
+	}
+
+	stats := make(map[string]int, len(pet.StatsMax))
+	for k, v := range pet.StatsMax {
+		stats[k] = v
+	}
+
+	pet.StatsMax = stats
+
+	return pet
+}
+
+// CreateRemindEvent records a new remind event.
+func (m *Memory) CreateRemindEvent(_ context.Context, event RemindEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.events = append(m.events, event)
+
+	return nil
+}
+
+// ListRemindEvents lists the events of the given remind, newest first.
+func (m *Memory) ListRemindEvents(_ context.Context, remindID string, skip, limit int) ([]RemindEvent, error) {
+	id, err := ParseID(remindID)
+	if err != nil {
+		return nil, fmt.Errorf("parse id: %w", err)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var events []RemindEvent
+	for _, event := range m.events {
+		if event.RemindID == id {
+			events = append(events, event)
+		}
+	}
+
+	return pageEvents(events, skip, limit), nil
+}
+
+// CreateOutboxMessage adds a message to the outbox.
+func (m *Memory) CreateOutboxMessage(_ context.Context, msg OutboxMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.outbox = append(m.outbox, msg)
+
+	return nil
+}
+
+// UpdateOutboxMessage updates a message of the outbox.
+func (m *Memory) UpdateOutboxMessage(_ context.Context, msg OutboxMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.outbox {
+		if m.outbox[i].ID == msg.ID {
+			m.outbox[i] = msg
+
+			return nil
+		}
+	}
+
+	return NotFoundError{Err: errors.New("outbox message not found")}
+}
+
+// ListPendingOutboxMessages lists the messages waiting to be delivered, by next attempt.
+func (m *Memory) ListPendingOutboxMessages(_ context.Context) ([]OutboxMessage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return pendingOutboxMessages(m.outbox), nil
+}
+
+// RequeueDeadOutboxMessages gives the dead messages a new series of attempts, starting at the given time.
+// It returns the number of requeued messages.
+func (m *Memory) RequeueDeadOutboxMessages(_ context.Context, at time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var n int
+
+	for i, msg := range m.outbox {
+		if msg.Status == OutboxDead {
+			m.outbox[i] = msg.requeue(at)
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// PruneOutboxMessages removes the sent messages created before the given time.
+func (m *Memory) PruneOutboxMessages(_ context.Context, before time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	kept := m.outbox[:0]
+	for _, msg := range m.outbox {
+		if !msg.prunable(before) {
+			kept = append(kept, msg)
+		}
+	}
+
+	m.outbox = kept
+
+	return nil
+}
+
+// CreateRemindMessage records the remind a Discord message is about.
+func (m *Memory) CreateRemindMessage(_ context.Context, msg RemindMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.messages[msg.MessageID]; ok {
+		return fmt.Errorf("create remind message: duplicate message id %q", msg.MessageID)
+	}
+
+	m.messages[msg.MessageID] = msg
+
+	return nil
+}
+
+// GetRemindMessage gets the remind message of the given Discord message ID.
+func (m *Memory) GetRemindMessage(_ context.Context, messageID string) (RemindMessage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	msg, ok := m.messages[messageID]
+	if !ok {
+		return RemindMessage{}, NotFoundError{Err: errors.New("remind message not found")}
+	}
+
+	return msg, nil
+}
+
+// GetUserSettings gets the settings of the given user, the default settings are returned when none are stored.
+func (m *Memory) GetUserSettings(_ context.Context, userID string) (UserSettings, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	settings, ok := m.settings[userID]
+	if !ok {
+		return DefaultUserSettings(userID), nil
+	}
+
+	return copyUserSettings(settings), nil
+}
+
+// UpsertUserSettings creates or replaces the settings of a user.
+func (m *Memory) UpsertUserSettings(_ context.Context, settings UserSettings) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.settings[settings.UserID] = copyUserSettings(settings)
+
+	return nil
+}
+
+// GetGuildSettings gets the settings of the given guild, empty settings are returned when none are stored.
+func (m *Memory) GetGuildSettings(_ context.Context, guildID string) (GuildSettings, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	settings, ok := m.guilds[guildID]
+	if !ok {
+		return GuildSettings{GuildID: guildID}, nil
+	}
+
+	return settings, nil
+}
+
+// UpsertGuildSettings creates or replaces the settings of a guild.
+func (m *Memory) UpsertGuildSettings(_ context.Context, settings GuildSettings) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.guilds[settings.GuildID] = settings
+
+	return nil
+}
+
+func copyUserSettings(settings UserSettings) UserSettings {
+	if settings.Warnings != nil {
+		settings.Warnings = append([]time.Duration(nil), settings.Warnings...)
+	}
+
+	return settings
+}
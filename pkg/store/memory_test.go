@@ -0,0 +1,50 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createMemory(t *testing.T, reminds []Remind) *Memory {
+	t.Helper()
+
+	ctx := context.Background()
+
+	store := NewMemory()
+
+	err := store.Bootstrap(ctx, testPets(t))
+	require.NoError(t, err)
+
+	for _, q := range reminds {
+		err = store.CreateRemind(ctx, q)
+		require.NoError(t, err)
+	}
+
+	return store
+}
+
+func TestMemory_GetPet_returnsCopy(t *testing.T) {
+	ctx := context.Background()
+	s := createMemory(t, nil)
+
+	pet, err := s.GetPet(ctx, "Chacha")
+	require.NoError(t, err)
+
+	pet.StatsMax["force"] = 0
+
+	got, err := s.GetPet(ctx, "Chacha")
+	require.NoError(t, err)
+
+	assert.Equal(t, 80, got.StatsMax["force"])
+}
+
+func TestMemory_CreateRemind_duplicateID(t *testing.T) {
+	remind := testRemind("discordUser")
+	s := createMemory(t, []Remind{remind})
+
+	err := s.CreateRemind(context.Background(), remind)
+	require.Error(t, err)
+}
@@ -0,0 +1,41 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// RemindMessage links a Discord message sent by the bot to the remind it is about.
+type RemindMessage struct {
+	MessageID string    `bson:"_id"`
+	RemindID  ID        `bson:"remindId"`
+	CreatedAt time.Time `bson:"createdAt"`
+}
+
+// CreateRemindMessage records the remind a Discord message is about.
+func (s *Mongo) CreateRemindMessage(ctx context.Context, msg RemindMessage) error {
+	if _, err := s.messages.InsertOne(ctx, msg); err != nil {
+		return fmt.Errorf("create remind message: %w", err)
+	}
+
+	return nil
+}
+
+// GetRemindMessage gets the remind message of the given Discord message ID.
+func (s *Mongo) GetRemindMessage(ctx context.Context, messageID string) (RemindMessage, error) {
+	var msg RemindMessage
+	if err := s.messages.FindOne(ctx, bson.D{{Key: "_id", Value: messageID}}).Decode(&msg); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return RemindMessage{}, NotFoundError{Err: err}
+		}
+
+		return RemindMessage{}, fmt.Errorf("find remind message: %w", err)
+	}
+
+	return msg, nil
+}
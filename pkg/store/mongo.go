@@ -0,0 +1,134 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DatabaseName is the name of the MongoDB database used by the bot.
+const DatabaseName = "pet-reminder-bot"
+
+// NewMongoClient creates a MongoDB client connected to the given URI.
+func NewMongoClient(ctx context.Context, uri string) (*mongo.Client, error) {
+	opts := options.Client().
+		ApplyURI(uri).
+		SetSocketTimeout(2 * time.Second).
+		SetConnectTimeout(10 * time.Second).
+		SetServerSelectionTimeout(10 * time.Second)
+
+	client, err := mongo.NewClient(opts)
+	if err != nil {
+		return nil, fmt.Errorf("create MongoDB client: %w", err)
+	}
+
+	if err = client.Connect(ctx); err != nil {
+		return nil, fmt.Errorf("connect db: %w", err)
+	}
+
+	return client, nil
+}
+
+// Mongo represents a store backed by MongoDB.
+type Mongo struct {
+	client   *mongo.Client
+	pets     *mongo.Collection
+	reminds  *mongo.Collection
+	events   *mongo.Collection
+	outbox   *mongo.Collection
+	messages *mongo.Collection
+	settings *mongo.Collection
+	guilds   *mongo.Collection
+}
+
+// NewMongo creates a new Mongo store.
+func NewMongo(client *mongo.Client, databaseName string) *Mongo {
+	return &Mongo{
+		client:   client,
+		pets:     client.Database(databaseName).Collection(petCollection),
+		reminds:  client.Database(databaseName).Collection(remindCollection),
+		events:   client.Database(databaseName).Collection(eventCollection),
+		outbox:   client.Database(databaseName).Collection(outboxCollection),
+		messages: client.Database(databaseName).Collection(messageCollection),
+		settings: client.Database(databaseName).Collection(settingsCollection),
+		guilds:   client.Database(databaseName).Collection(guildCollection),
+	}
+}
+
+// Bootstrap boostraps the database and upserts the given pets.
+func (s *Mongo) Bootstrap(ctx context.Context, pets Pets) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "name", Value: 1},
+			},
+			Options: options.Index().
+				SetName("_uniq_name").
+				SetUnique(true),
+		},
+	}
+
+	if _, err := s.pets.Indexes().CreateMany(ctx, indexes); err != nil {
+		return fmt.Errorf("create workspace indexes: %w", err)
+	}
+
+	eventIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "remindId", Value: 1},
+			{Key: "createdAt", Value: -1},
+		},
+		Options: options.Index().SetName("_remind_created_at"),
+	}
+
+	if _, err := s.events.Indexes().CreateOne(ctx, eventIndex); err != nil {
+		return fmt.Errorf("create event indexes: %w", err)
+	}
+
+	outboxIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "status", Value: 1},
+			{Key: "nextAttempt", Value: 1},
+		},
+		Options: options.Index().SetName("_status_next_attempt"),
+	}
+
+	if _, err := s.outbox.Indexes().CreateOne(ctx, outboxIndex); err != nil {
+		return fmt.Errorf("create outbox indexes: %w", err)
+	}
+
+	if err := s.initData(ctx, pets); err != nil {
+		return fmt.Errorf("init data: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Mongo) initData(ctx context.Context, pets Pets) error {
+	for _, pet := range pets {
+		filter := bson.D{{Key: "name", Value: pet.Name}}
+		update := bson.D{
+			{Key: "$set", Value: bson.D{
+				{Key: "image", Value: pet.Image},
+				{Key: "aliases", Value: pet.Aliases},
+				{Key: "foodMinDuration", Value: pet.FoodMinDuration},
+				{Key: "foodMaxDuration", Value: pet.FoodMaxDuration},
+				{Key: "statsMax", Value: pet.StatsMax},
+			}},
+			{Key: "$setOnInsert", Value: bson.D{{Key: "_id", Value: NewID()}}},
+		}
+
+		if _, err := s.pets.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+			if isMongoDBDuplicateError(err) {
+				continue
+			}
+
+			return fmt.Errorf("upsert document: %w", err)
+		}
+	}
+
+	return nil
+}
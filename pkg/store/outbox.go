@@ -0,0 +1,169 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// OutboxStatus represents the delivery status of an outbox message.
+type OutboxStatus string
+
+// Outbox statuses.
+const (
+	OutboxPending OutboxStatus = "pending"
+	OutboxSent    OutboxStatus = "sent"
+	OutboxDead    OutboxStatus = "dead"
+)
+
+// Recipient represents who a message is for, and where it has to be delivered.
+// Every field is optional, the message is sent to the default channel when none is set.
+// Locale is the language of the recipient, the buttons attached to the message are labelled in it.
+type Recipient struct {
+	UserID    string `bson:"userId,omitempty"`
+	GuildID   string `bson:"guildId,omitempty"`
+	ChannelID string `bson:"channelId,omitempty"`
+	Locale    string `bson:"locale,omitempty"`
+}
+
+// OutboxMessage represents a Discord message waiting to be delivered.
+// RemindID is empty when the message is not about a single remind.
+type OutboxMessage struct {
+	ID          ID           `bson:"_id"`
+	RemindID    ID           `bson:"remindId,omitempty"`
+	To          Recipient    `bson:",inline"`
+	Content     string       `bson:"content"`
+	Status      OutboxStatus `bson:"status"`
+	Attempts    int          `bson:"attempts"`
+	NextAttempt time.Time    `bson:"nextAttempt"`
+	LastError   string       `bson:"lastError,omitempty"`
+	CreatedAt   time.Time    `bson:"createdAt"`
+}
+
+// NewOutboxMessage creates a pending message, to be delivered as soon as possible.
+func NewOutboxMessage(remindID ID, content string, at time.Time) OutboxMessage {
+	return OutboxMessage{
+		ID:          NewID(),
+		RemindID:    remindID,
+		Content:     content,
+		Status:      OutboxPending,
+		NextAttempt: at,
+		CreatedAt:   at,
+	}
+}
+
+// CreateOutboxMessage adds a message to the outbox.
+func (s *Mongo) CreateOutboxMessage(ctx context.Context, msg OutboxMessage) error {
+	if _, err := s.outbox.InsertOne(ctx, msg); err != nil {
+		return fmt.Errorf("create outbox message: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateOutboxMessage updates a message of the outbox.
+func (s *Mongo) UpdateOutboxMessage(ctx context.Context, msg OutboxMessage) error {
+	res, err := s.outbox.ReplaceOne(ctx, bson.D{{Key: "_id", Value: msg.ID}}, msg)
+	if err != nil {
+		return fmt.Errorf("update outbox message: %w", err)
+	}
+
+	if res.MatchedCount == 0 {
+		return NotFoundError{Err: errors.New("outbox message not found")}
+	}
+
+	return nil
+}
+
+// ListPendingOutboxMessages lists the messages waiting to be delivered, by next attempt.
+func (s *Mongo) ListPendingOutboxMessages(ctx context.Context) ([]OutboxMessage, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "nextAttempt", Value: 1}, {Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+
+	res, err := s.outbox.Find(ctx, bson.D{{Key: "status", Value: OutboxPending}}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find outbox messages: %w", err)
+	}
+
+	var msgs []OutboxMessage
+	if err = res.All(ctx, &msgs); err != nil {
+		return nil, fmt.Errorf("decode outbox messages: %w", err)
+	}
+
+	return msgs, nil
+}
+
+// RequeueDeadOutboxMessages gives the dead messages a new series of attempts, starting at the given time.
+// It returns the number of requeued messages.
+func (s *Mongo) RequeueDeadOutboxMessages(ctx context.Context, at time.Time) (int, error) {
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "status", Value: OutboxPending},
+		{Key: "attempts", Value: 0},
+		{Key: "nextAttempt", Value: at},
+	}}}
+
+	res, err := s.outbox.UpdateMany(ctx, bson.D{{Key: "status", Value: OutboxDead}}, update)
+	if err != nil {
+		return 0, fmt.Errorf("requeue outbox messages: %w", err)
+	}
+
+	return int(res.ModifiedCount), nil
+}
+
+// PruneOutboxMessages removes the sent messages created before the given time.
+func (s *Mongo) PruneOutboxMessages(ctx context.Context, before time.Time) error {
+	filter := bson.D{
+		{Key: "status", Value: OutboxSent},
+		{Key: "createdAt", Value: bson.D{{Key: "$lt", Value: before}}},
+	}
+
+	if _, err := s.outbox.DeleteMany(ctx, filter); err != nil {
+		return fmt.Errorf("delete outbox messages: %w", err)
+	}
+
+	return nil
+}
+
+// requeue returns the dead message as pending, with a new series of attempts starting at the given time.
+func (m OutboxMessage) requeue(at time.Time) OutboxMessage {
+	m.Status = OutboxPending
+	m.Attempts = 0
+	m.NextAttempt = at
+
+	return m
+}
+
+// prunable reports whether the message has been sent before the given time, and is no longer needed.
+func (m OutboxMessage) prunable(before time.Time) bool {
+	return m.Status == OutboxSent && m.CreatedAt.Before(before)
+}
+
+// pendingOutboxMessages returns the pending messages, sorted by next attempt.
+func pendingOutboxMessages(msgs []OutboxMessage) []OutboxMessage {
+	var pending []OutboxMessage
+
+	for _, msg := range msgs {
+		if msg.Status == OutboxPending {
+			pending = append(pending, msg)
+		}
+	}
+
+	sort.SliceStable(pending, func(i, j int) bool {
+		a, b := pending[i], pending[j]
+
+		switch {
+		case !a.NextAttempt.Equal(b.NextAttempt):
+			return a.NextAttempt.Before(b.NextAttempt)
+		case !a.CreatedAt.Equal(b.CreatedAt):
+			return a.CreatedAt.Before(b.CreatedAt)
+		default:
+			return a.ID < b.ID
+		}
+	})
+
+	return pending
+}
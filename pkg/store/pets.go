@@ -7,18 +7,19 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // Pet represents a pet.
+// Aliases are other names the pet can be found with, like its name in the game.
 type Pet struct {
-	ID              primitive.ObjectID `bson:"_id"`
-	Name            string             `bson:"name"`
-	Image           string             `bson:"image"`
-	FoodMinDuration time.Duration      `bson:"foodMinDuration"`
-	FoodMaxDuration time.Duration      `bson:"foodMaxDuration"`
-	StatsMax        map[string]int     `bson:"statsMax"`
+	ID              ID             `bson:"_id"`
+	Name            string         `bson:"name"`
+	Aliases         []string       `bson:"aliases,omitempty"`
+	Image           string         `bson:"image"`
+	FoodMinDuration time.Duration  `bson:"foodMinDuration"`
+	FoodMaxDuration time.Duration  `bson:"foodMaxDuration"`
+	StatsMax        map[string]int `bson:"statsMax"`
 }
 
 // Pets represents a list of pet.
@@ -36,7 +37,7 @@ func (p Pets) String() string {
 }
 
 // ListPets lists all pets.
-func (s *Store) ListPets(ctx context.Context) (Pets, error) {
+func (s *Mongo) ListPets(ctx context.Context) (Pets, error) {
 	req, err := s.pets.Find(ctx, bson.D{})
 	if err != nil {
 		return nil, fmt.Errorf("find: %w", err)
@@ -51,7 +52,7 @@ func (s *Store) ListPets(ctx context.Context) (Pets, error) {
 }
 
 // GetPet returns a pet by the given name.
-func (s *Store) GetPet(ctx context.Context, name string) (Pet, error) {
+func (s *Mongo) GetPet(ctx context.Context, name string) (Pet, error) {
 	filter := bson.D{{Key: "name", Value: name}}
 
 	var pet Pet
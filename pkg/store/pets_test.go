@@ -9,14 +9,14 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func TestStore_ListPets(t *testing.T) {
-	s := createStore(t, nil)
+func TestMongo_ListPets(t *testing.T) {
+	s := createMongo(t, nil)
 
 	got, err := s.ListPets(context.Background())
 	require.NoError(t, err)
 
 	// Fill IDs
-	p := pets()
+	p := testPets(t)
 	for i := range p {
 		p[i].ID = got[i].ID
 	}
@@ -24,8 +24,8 @@ func TestStore_ListPets(t *testing.T) {
 	assert.Equal(t, p, got)
 }
 
-func TestStore_GetPet(t *testing.T) {
-	s := createStore(t, nil)
+func TestMongo_GetPet(t *testing.T) {
+	s := createMongo(t, nil)
 
 	got, err := s.GetPet(context.Background(), "Chacha")
 	require.NoError(t, err)
@@ -40,8 +40,8 @@ func TestStore_GetPet(t *testing.T) {
 	assert.Equal(t, want, got)
 }
 
-func TestStore_GetPet_notFoundError(t *testing.T) {
-	s := createStore(t, nil)
+func TestMongo_GetPet_notFoundError(t *testing.T) {
+	s := createMongo(t, nil)
 
 	_, err := s.GetPet(context.Background(), "Unknown")
 	require.ErrorAs(t, err, &NotFoundError{})
@@ -7,23 +7,43 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // Remind represents a Remind object.
+// GuildID and ChannelID are where the remind has been created, they are empty for the reminds created before they were recorded.
+// Warnings are the lead times before TimeoutRemind of the warnings still to send in the current cycle, greatest first.
+// Paused reminds are not notified, until PausedUntil when it is set.
+// CycleStart is when the current cycle started: the last feed, resume or missed meal. It is zero for the reminds created before it was recorded.
 type Remind struct {
-	ID             primitive.ObjectID `bson:"_id"`
-	DiscordUserID  string             `bson:"discordUserId"`
-	PetName        string             `bson:"petName"`
-	Character      string             `bson:"character"`
-	MissedReminder int                `bson:"missedReminder"`
-	NextRemind     time.Time          `bson:"nextRemind"`
-	ReminderSent   bool               `bson:"reminderSent"`
-	TimeoutRemind  time.Time          `bson:"timeoutRemind"`
+	ID             ID              `bson:"_id"`
+	DiscordUserID  string          `bson:"discordUserId"`
+	GuildID        string          `bson:"guildId,omitempty"`
+	ChannelID      string          `bson:"channelId,omitempty"`
+	PetName        string          `bson:"petName"`
+	Character      string          `bson:"character"`
+	MissedReminder int             `bson:"missedReminder"`
+	CycleStart     time.Time       `bson:"cycleStart"`
+	NextRemind     time.Time       `bson:"nextRemind"`
+	ReminderSent   bool            `bson:"reminderSent"`
+	TimeoutRemind  time.Time       `bson:"timeoutRemind"`
+	SnoozedUntil   time.Time       `bson:"snoozedUntil"`
+	Warnings       []time.Duration `bson:"warnings"`
+	Paused         bool            `bson:"paused"`
+	PausedUntil    time.Time       `bson:"pausedUntil"`
+}
+
+// Recipient returns the recipient of the notifications about the remind.
+func (r Remind) Recipient() Recipient {
+	return Recipient{
+		UserID:    r.DiscordUserID,
+		GuildID:   r.GuildID,
+		ChannelID: r.ChannelID,
+	}
 }
 
 // CreateRemind creates a new remind.
-func (s *Store) CreateRemind(ctx context.Context, remind Remind) error {
+func (s *Mongo) CreateRemind(ctx context.Context, remind Remind) error {
 	if _, err := s.reminds.InsertOne(ctx, remind); err != nil {
 		return fmt.Errorf("create remind: %w", err)
 	}
@@ -32,14 +52,18 @@ func (s *Store) CreateRemind(ctx context.Context, remind Remind) error {
 }
 
 // GetRemind gets a remind with the given ID.
-func (s *Store) GetRemind(ctx context.Context, id string) (Remind, error) {
-	objectID, err := primitive.ObjectIDFromHex(id)
+func (s *Mongo) GetRemind(ctx context.Context, id string) (Remind, error) {
+	remindID, err := ParseID(id)
 	if err != nil {
-		return Remind{}, fmt.Errorf("object id: %w", err)
+		return Remind{}, fmt.Errorf("parse id: %w", err)
 	}
 
 	var remind Remind
-	if err = s.reminds.FindOne(ctx, bson.D{{Key: "_id", Value: objectID}}).Decode(&remind); err != nil {
+	if err = s.reminds.FindOne(ctx, bson.D{{Key: "_id", Value: remindID}}).Decode(&remind); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return Remind{}, NotFoundError{Err: err}
+		}
+
 		return Remind{}, fmt.Errorf("find remind: %w", err)
 	}
 
@@ -47,7 +71,7 @@ func (s *Store) GetRemind(ctx context.Context, id string) (Remind, error) {
 }
 
 // UpdateRemind updates the given remind.
-func (s *Store) UpdateRemind(ctx context.Context, remind Remind) error {
+func (s *Mongo) UpdateRemind(ctx context.Context, remind Remind) error {
 	if _, err := s.reminds.UpdateOne(ctx, bson.D{{Key: "_id", Value: remind.ID}}, bson.D{{Key: "$set", Value: remind}}); err != nil {
 		return fmt.Errorf("create remind: %w", err)
 	}
@@ -56,16 +80,16 @@ func (s *Store) UpdateRemind(ctx context.Context, remind Remind) error {
 }
 
 // ListAllReminds lists all the reminds.
-func (s *Store) ListAllReminds(ctx context.Context) ([]Remind, error) {
+func (s *Mongo) ListAllReminds(ctx context.Context) ([]Remind, error) {
 	return s.listReminds(ctx, bson.D{})
 }
 
 // ListRemindsByID lists all the reminds for the given user ID.
-func (s *Store) ListRemindsByID(ctx context.Context, id string) ([]Remind, error) {
+func (s *Mongo) ListRemindsByID(ctx context.Context, id string) ([]Remind, error) {
 	return s.listReminds(ctx, bson.D{{Key: "discordUserId", Value: id}})
 }
 
-func (s *Store) listReminds(ctx context.Context, filter bson.D) ([]Remind, error) {
+func (s *Mongo) listReminds(ctx context.Context, filter bson.D) ([]Remind, error) {
 	res, err := s.reminds.Find(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("find reminds: %w", err)
@@ -80,13 +104,13 @@ func (s *Store) listReminds(ctx context.Context, filter bson.D) ([]Remind, error
 }
 
 // RemoveRemind removes the remind with the given id.
-func (s *Store) RemoveRemind(ctx context.Context, id string) error {
-	objectID, err := primitive.ObjectIDFromHex(id)
+func (s *Mongo) RemoveRemind(ctx context.Context, id string) error {
+	remindID, err := ParseID(id)
 	if err != nil {
-		return fmt.Errorf("object id: %w", err)
+		return fmt.Errorf("parse id: %w", err)
 	}
 
-	res, err := s.reminds.DeleteOne(ctx, bson.D{{Key: "_id", Value: objectID}})
+	res, err := s.reminds.DeleteOne(ctx, bson.D{{Key: "_id", Value: remindID}})
 	if err != nil {
 		return fmt.Errorf("delete remind: %w", err)
 	}
@@ -8,15 +8,14 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-func TestStore_CreateRemind(t *testing.T) {
+func TestMongo_CreateRemind(t *testing.T) {
 	ctx := context.Background()
-	s := createStore(t, nil)
+	s := createMongo(t, nil)
 
 	remind := Remind{
-		ID:             primitive.NewObjectID(),
+		ID:             NewID(),
 		DiscordUserID:  "discordUser",
 		PetName:        "pet",
 		Character:      "character",
@@ -35,12 +34,12 @@ func TestStore_CreateRemind(t *testing.T) {
 	assert.Equal(t, remind, got)
 }
 
-func TestStore_GetRemind(t *testing.T) {
+func TestMongo_GetRemind(t *testing.T) {
 	ctx := context.Background()
 
 	reminds := []Remind{
 		{
-			ID:             primitive.NewObjectID(),
+			ID:             NewID(),
 			DiscordUserID:  "discordUser",
 			PetName:        "pet",
 			Character:      "character",
@@ -50,7 +49,7 @@ func TestStore_GetRemind(t *testing.T) {
 			TimeoutRemind:  time.Time{},
 		},
 		{
-			ID:             primitive.NewObjectID(),
+			ID:             NewID(),
 			DiscordUserID:  "discordUser2",
 			PetName:        "pet2",
 			Character:      "character2",
@@ -60,20 +59,20 @@ func TestStore_GetRemind(t *testing.T) {
 			TimeoutRemind:  time.Time{},
 		},
 	}
-	s := createStore(t, reminds)
+	s := createMongo(t, reminds)
 
-	remind, err := s.GetRemind(ctx, reminds[0].ID.Hex())
+	remind, err := s.GetRemind(ctx, reminds[0].ID.String())
 	require.NoError(t, err)
 
 	assert.Equal(t, reminds[0], remind)
 }
 
-func TestStore_UpdateRemind(t *testing.T) {
+func TestMongo_UpdateRemind(t *testing.T) {
 	ctx := context.Background()
 
 	reminds := []Remind{
 		{
-			ID:             primitive.NewObjectID(),
+			ID:             NewID(),
 			DiscordUserID:  "discordUser",
 			PetName:        "pet",
 			Character:      "character",
@@ -83,7 +82,7 @@ func TestStore_UpdateRemind(t *testing.T) {
 			TimeoutRemind:  time.Time{},
 		},
 		{
-			ID:             primitive.NewObjectID(),
+			ID:             NewID(),
 			DiscordUserID:  "discordUser2",
 			PetName:        "pet2",
 			Character:      "character2",
@@ -93,7 +92,7 @@ func TestStore_UpdateRemind(t *testing.T) {
 			TimeoutRemind:  time.Time{},
 		},
 	}
-	s := createStore(t, reminds)
+	s := createMongo(t, reminds)
 
 	update := Remind{
 		ID:             reminds[0].ID,
@@ -116,12 +115,23 @@ func TestStore_UpdateRemind(t *testing.T) {
 	assert.Equal(t, update, got)
 }
 
-func TestStore_ListAllReminds(t *testing.T) {
+// The reminds are updated with `$set`, a field missing from the document would keep its previous value.
+func TestRemind_marshalZeroFields(t *testing.T) {
+	raw, err := bson.Marshal(Remind{})
+	require.NoError(t, err)
+
+	for _, key := range []string{"warnings", "paused", "pausedUntil"} {
+		_, err = bson.Raw(raw).LookupErr(key)
+		assert.NoError(t, err, key)
+	}
+}
+
+func TestMongo_ListAllReminds(t *testing.T) {
 	ctx := context.Background()
 
 	reminds := []Remind{
 		{
-			ID:             primitive.NewObjectID(),
+			ID:             NewID(),
 			DiscordUserID:  "discordUser",
 			PetName:        "pet",
 			Character:      "character",
@@ -131,7 +141,7 @@ func TestStore_ListAllReminds(t *testing.T) {
 			TimeoutRemind:  time.Time{},
 		},
 		{
-			ID:             primitive.NewObjectID(),
+			ID:             NewID(),
 			DiscordUserID:  "discordUser2",
 			PetName:        "pet2",
 			Character:      "character2",
@@ -141,7 +151,7 @@ func TestStore_ListAllReminds(t *testing.T) {
 			TimeoutRemind:  time.Time{},
 		},
 	}
-	s := createStore(t, reminds)
+	s := createMongo(t, reminds)
 
 	got, err := s.ListAllReminds(ctx)
 	require.NoError(t, err)
@@ -149,12 +159,12 @@ func TestStore_ListAllReminds(t *testing.T) {
 	assert.Equal(t, reminds, got)
 }
 
-func TestStore_ListRemindsByID(t *testing.T) {
+func TestMongo_ListRemindsByID(t *testing.T) {
 	ctx := context.Background()
 
 	reminds := []Remind{
 		{
-			ID:             primitive.NewObjectID(),
+			ID:             NewID(),
 			DiscordUserID:  "discordUser",
 			PetName:        "pet",
 			Character:      "character",
@@ -164,7 +174,7 @@ func TestStore_ListRemindsByID(t *testing.T) {
 			TimeoutRemind:  time.Time{},
 		},
 		{
-			ID:             primitive.NewObjectID(),
+			ID:             NewID(),
 			DiscordUserID:  "discordUser2",
 			PetName:        "pet2",
 			Character:      "character2",
@@ -174,7 +184,7 @@ func TestStore_ListRemindsByID(t *testing.T) {
 			TimeoutRemind:  time.Time{},
 		},
 	}
-	s := createStore(t, reminds)
+	s := createMongo(t, reminds)
 
 	got, err := s.ListRemindsByID(ctx, "discordUser")
 	require.NoError(t, err)
@@ -182,12 +192,12 @@ func TestStore_ListRemindsByID(t *testing.T) {
 	assert.Equal(t, []Remind{reminds[0]}, got)
 }
 
-func TestStore_RemoveRemind(t *testing.T) {
+func TestMongo_RemoveRemind(t *testing.T) {
 	ctx := context.Background()
 
 	reminds := []Remind{
 		{
-			ID:             primitive.NewObjectID(),
+			ID:             NewID(),
 			DiscordUserID:  "discordUser",
 			PetName:        "pet",
 			Character:      "character",
@@ -197,7 +207,7 @@ func TestStore_RemoveRemind(t *testing.T) {
 			TimeoutRemind:  time.Time{},
 		},
 		{
-			ID:             primitive.NewObjectID(),
+			ID:             NewID(),
 			DiscordUserID:  "discordUser2",
 			PetName:        "pet2",
 			Character:      "character2",
@@ -207,9 +217,9 @@ func TestStore_RemoveRemind(t *testing.T) {
 			TimeoutRemind:  time.Time{},
 		},
 	}
-	s := createStore(t, reminds)
+	s := createMongo(t, reminds)
 
-	err := s.RemoveRemind(ctx, reminds[1].ID.Hex())
+	err := s.RemoveRemind(ctx, reminds[1].ID.String())
 	require.NoError(t, err)
 
 	res, err := s.reminds.Find(ctx, bson.D{})
@@ -0,0 +1,123 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NotifyMode defines where the notifications of a user are delivered.
+type NotifyMode string
+
+// Notify modes.
+const (
+	NotifyChannel NotifyMode = "channel"
+	NotifyDM      NotifyMode = "dm"
+)
+
+// ParseNotifyMode parses a notify mode.
+func ParseNotifyMode(s string) (NotifyMode, error) {
+	switch mode := NotifyMode(s); mode {
+	case NotifyChannel, NotifyDM:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown notify mode %q", s)
+	}
+}
+
+// UserSettings represents the preferences of a Discord user.
+// Timezone is the IANA name of the timezone the times are displayed in, the default timezone is used when it is empty.
+// Locale is the language the messages are sent in, the locale of the guild is used when it is empty.
+// Warnings are the lead times before the end of the feeding window the user is warned at, greatest first.
+type UserSettings struct {
+	UserID   string          `bson:"_id"`
+	Notify   NotifyMode      `bson:"notify,omitempty"`
+	Timezone string          `bson:"timezone,omitempty"`
+	Locale   string          `bson:"locale,omitempty"`
+	Warnings []time.Duration `bson:"warnings,omitempty"`
+}
+
+// Location returns the timezone of the user, or def when the user has not set any valid one.
+func (s UserSettings) Location(def *time.Location) *time.Location {
+	if s.Timezone == "" {
+		return def
+	}
+
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return def
+	}
+
+	return loc
+}
+
+// DefaultUserSettings returns the settings of a user who has not set any preference.
+// Warnings are opt-in, none is sent by default.
+func DefaultUserSettings(userID string) UserSettings {
+	return UserSettings{
+		UserID: userID,
+		Notify: NotifyChannel,
+	}
+}
+
+// GetUserSettings gets the settings of the given user, the default settings are returned when none are stored.
+func (s *Mongo) GetUserSettings(ctx context.Context, userID string) (UserSettings, error) {
+	settings := DefaultUserSettings(userID)
+	if err := s.settings.FindOne(ctx, bson.D{{Key: "_id", Value: userID}}).Decode(&settings); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return DefaultUserSettings(userID), nil
+		}
+
+		return UserSettings{}, fmt.Errorf("find user settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// UpsertUserSettings creates or replaces the settings of a user.
+func (s *Mongo) UpsertUserSettings(ctx context.Context, settings UserSettings) error {
+	opts := options.Replace().SetUpsert(true)
+	if _, err := s.settings.ReplaceOne(ctx, bson.D{{Key: "_id", Value: settings.UserID}}, settings, opts); err != nil {
+		return fmt.Errorf("upsert user settings: %w", err)
+	}
+
+	return nil
+}
+
+// GuildSettings represents the configuration of a Discord guild.
+// ChannelID is the channel the notifications are sent to, the channel of each remind is used when it is empty.
+// Locale is the language the messages are sent in to the members who have not chosen one.
+type GuildSettings struct {
+	GuildID   string `bson:"_id"`
+	ChannelID string `bson:"channelId,omitempty"`
+	Locale    string `bson:"locale,omitempty"`
+}
+
+// GetGuildSettings gets the settings of the given guild, empty settings are returned when none are stored.
+func (s *Mongo) GetGuildSettings(ctx context.Context, guildID string) (GuildSettings, error) {
+	settings := GuildSettings{GuildID: guildID}
+	if err := s.guilds.FindOne(ctx, bson.D{{Key: "_id", Value: guildID}}).Decode(&settings); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return GuildSettings{GuildID: guildID}, nil
+		}
+
+		return GuildSettings{}, fmt.Errorf("find guild settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// UpsertGuildSettings creates or replaces the settings of a guild.
+func (s *Mongo) UpsertGuildSettings(ctx context.Context, settings GuildSettings) error {
+	opts := options.Replace().SetUpsert(true)
+	if _, err := s.guilds.ReplaceOne(ctx, bson.D{{Key: "_id", Value: settings.GuildID}}, settings, opts); err != nil {
+		return fmt.Errorf("upsert guild settings: %w", err)
+	}
+
+	return nil
+}
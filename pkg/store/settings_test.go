@@ -0,0 +1,56 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNotifyMode(t *testing.T) {
+	for _, mode := range []NotifyMode{NotifyChannel, NotifyDM} {
+		got, err := ParseNotifyMode(string(mode))
+		require.NoError(t, err)
+		assert.Equal(t, mode, got)
+	}
+
+	_, err := ParseNotifyMode("mp")
+	assert.Error(t, err)
+}
+
+func TestUserSettings_Location(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	tests := []struct {
+		desc     string
+		timezone string
+		want     *time.Location
+	}{
+		{
+			desc: "default",
+			want: time.UTC,
+		},
+		{
+			desc:     "user timezone",
+			timezone: "Europe/Paris",
+			want:     paris,
+		},
+		{
+			desc:     "invalid timezone",
+			timezone: "Europe/Pouet",
+			want:     time.UTC,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			settings := UserSettings{UserID: "1", Timezone: test.timezone}
+			assert.Equal(t, test.want.String(), settings.Location(time.UTC).String())
+		})
+	}
+}
@@ -2,288 +2,104 @@ package store
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
-	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
 )
 
 const (
-	petCollection    = "pets"
-	remindCollection = "reminds"
+	petCollection      = "pets"
+	remindCollection   = "reminds"
+	eventCollection    = "remind_events"
+	outboxCollection   = "outbox"
+	messageCollection  = "remind_messages"
+	settingsCollection = "user_settings"
+	guildCollection    = "guild_settings"
 )
 
-// Store represents the store.
-type Store struct {
-	client  *mongo.Client
-	pets    *mongo.Collection
-	reminds *mongo.Collection
+// Store is implemented by every storage backend.
+type Store interface {
+	Bootstrap(ctx context.Context, pets Pets) error
+	ListPets(ctx context.Context) (Pets, error)
+	GetPet(ctx context.Context, name string) (Pet, error)
+	CreateRemind(ctx context.Context, remind Remind) error
+	UpdateRemind(ctx context.Context, remind Remind) error
+	GetRemind(ctx context.Context, id string) (Remind, error)
+	RemoveRemind(ctx context.Context, id string) error
+	ListAllReminds(ctx context.Context) ([]Remind, error)
+	ListRemindsByID(ctx context.Context, id string) ([]Remind, error)
+	CreateRemindEvent(ctx context.Context, event RemindEvent) error
+	ListRemindEvents(ctx context.Context, remindID string, skip, limit int) ([]RemindEvent, error)
+	CreateOutboxMessage(ctx context.Context, msg OutboxMessage) error
+	UpdateOutboxMessage(ctx context.Context, msg OutboxMessage) error
+	ListPendingOutboxMessages(ctx context.Context) ([]OutboxMessage, error)
+	RequeueDeadOutboxMessages(ctx context.Context, at time.Time) (int, error)
+	PruneOutboxMessages(ctx context.Context, before time.Time) error
+	CreateRemindMessage(ctx context.Context, msg RemindMessage) error
+	GetRemindMessage(ctx context.Context, messageID string) (RemindMessage, error)
+	GetUserSettings(ctx context.Context, userID string) (UserSettings, error)
+	UpsertUserSettings(ctx context.Context, settings UserSettings) error
+	GetGuildSettings(ctx context.Context, guildID string) (GuildSettings, error)
+	UpsertGuildSettings(ctx context.Context, settings GuildSettings) error
 }
 
-// New creates a new Store.
-func New(client *mongo.Client, databaseName string) *Store {
-	return &Store{
-		client:  client,
-		pets:    client.Database(databaseName).Collection(petCollection),
-		reminds: client.Database(databaseName).Collection(remindCollection),
-	}
+// ID is an opaque identifier, shared by all the storage backends.
+type ID string
+
+// NewID generates a new unique ID.
+func NewID() ID {
+	return ID(primitive.NewObjectID().Hex())
 }
 
-// Bootstrap boostraps the database.
-func (s *Store) Bootstrap(ctx context.Context) error {
-	indexes := []mongo.IndexModel{
-		{
-			Keys: bson.D{
-				{Key: "name", Value: 1},
-			},
-			Options: options.Index().
-				SetName("_uniq_name").
-				SetUnique(true),
-		},
+// ParseID parses the given string as an ID.
+func ParseID(s string) (ID, error) {
+	if _, err := primitive.ObjectIDFromHex(s); err != nil {
+		return "", fmt.Errorf("invalid id %q: %w", s, err)
 	}
 
-	if _, err := s.pets.Indexes().CreateMany(ctx, indexes); err != nil {
-		return fmt.Errorf("create workspace indexes: %w", err)
-	}
+	return ID(s), nil
+}
 
-	if err := s.initData(ctx); err != nil {
-		return fmt.Errorf("init data: %w", err)
+// String returns the string representation of the ID.
+func (id ID) String() string {
+	return string(id)
+}
+
+// MarshalBSONValue marshals the ID as an ObjectID when possible,
+// so documents written before IDs were opaque can still be queried.
+func (id ID) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	objectID, err := primitive.ObjectIDFromHex(string(id))
+	if err != nil {
+		return bsontype.String, bsoncore.AppendString(nil, string(id)), nil
 	}
 
-	return nil
+	return bsontype.ObjectID, bsoncore.AppendObjectID(nil, objectID), nil
 }
 
-func (s *Store) initData(ctx context.Context) error {
-	p := pets()
-
-	for _, pet := range p {
-		pet.ID = primitive.NewObjectID()
-		if _, err := s.pets.InsertOne(ctx, pet); err != nil {
-			if isMongoDBDuplicateError(err) {
-				continue
-			}
+// UnmarshalBSONValue unmarshals an ObjectID or a string into the ID.
+func (id *ID) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	switch t {
+	case bsontype.ObjectID:
+		objectID, _, ok := bsoncore.ReadObjectID(data)
+		if !ok {
+			return errors.New("invalid ObjectID")
+		}
 
-			return fmt.Errorf("insert document: %w", err)
+		*id = ID(objectID.Hex())
+	case bsontype.String:
+		s, _, ok := bsoncore.ReadString(data)
+		if !ok {
+			return errors.New("invalid string")
 		}
+
+		*id = ID(s)
+	default:
+		return fmt.Errorf("cannot unmarshal %s into an ID", t)
 	}
 
 	return nil
 }
-
-func pets() Pets {
-	return Pets{
-		{
-			Name:            "Chacha",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 18 * time.Hour,
-			StatsMax:        map[string]int{"intelligence": 80, "pourcentage_resistance_neutre": 20, "agilite": 80, "vitalite": 80, "force": 80},
-		},
-		{
-			Name:            "Bwak_Air",
-			FoodMinDuration: 11 * time.Hour,
-			FoodMaxDuration: 36 * time.Hour,
-			StatsMax:        map[string]int{"agilite": 80, "pourcentage_resistance_neutre": 20, "vitalite": 80},
-		},
-		{
-			Name:            "Bwak_Terre",
-			FoodMinDuration: 11 * time.Hour,
-			FoodMaxDuration: 36 * time.Hour,
-			StatsMax:        map[string]int{"force": 80, "pourcentage_resistance_neutre": 20, "vitalite": 80},
-		},
-		{
-			Name:            "Bwak_Feu",
-			FoodMinDuration: 11 * time.Hour,
-			FoodMaxDuration: 36 * time.Hour,
-			StatsMax:        map[string]int{"intelligence": 80, "pourcentage_resistance_neutre": 20, "vitalite": 80},
-		},
-		{
-			Name:            "Bwak_Eau",
-			FoodMinDuration: 11 * time.Hour,
-			FoodMaxDuration: 36 * time.Hour,
-			StatsMax:        map[string]int{"chance": 80, "pourcentage_resistance_neutre": 20, "vitalite": 80},
-		},
-		{
-			Name:            "Bworky",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 72 * time.Hour,
-			StatsMax:        map[string]int{"pods": 1000},
-		},
-		{
-			Name:            "Chienchien_Noir",
-			FoodMinDuration: 11 * time.Hour,
-			FoodMaxDuration: 36 * time.Hour,
-			StatsMax:        map[string]int{"pourcentage_dommage": 40},
-		},
-		{
-			Name:            "Koalak_Sanguin",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 72 * time.Hour,
-			StatsMax:        map[string]int{"sagesse": 50},
-		},
-		{
-			Name:            "Nomoon",
-			FoodMinDuration: 24 * time.Hour,
-			FoodMaxDuration: 48 * time.Hour,
-			StatsMax:        map[string]int{"prospection": 80},
-		},
-		{
-			Name:            "Petit_Chacha_Blanc",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 36 * time.Hour,
-			StatsMax:        map[string]int{"initiative": 500},
-		},
-		{
-			Name:            "Peki",
-			FoodMinDuration: 3 * time.Hour,
-			FoodMaxDuration: 36 * time.Hour,
-			StatsMax:        map[string]int{"vitalite": 300},
-		},
-		{
-			Name:            "Vilain_Petit_Corbac",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 48 * time.Hour,
-			StatsMax:        map[string]int{"prospection": 40},
-		},
-		{
-			Name:            "Atouin",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 72 * time.Hour,
-			StatsMax:        map[string]int{"dommage": 10, "soin": 10},
-		},
-		{
-			Name:            "Wabbit",
-			FoodMinDuration: 24 * time.Hour,
-			FoodMaxDuration: 48 * time.Hour,
-			StatsMax:        map[string]int{"force": 80, "agilite": 80, "chance": 80, "sagesse": 27},
-		},
-		{
-			Name:            "Fotome",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 72 * time.Hour,
-			StatsMax:        map[string]int{"vitalite": 150},
-		},
-		{
-			Name:            "Croum",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 72 * time.Hour,
-			StatsMax:        map[string]int{"pourcentage_resistance_neutre": 20, "pourcentage_resistance_terre": 20, "pourcentage_resistance_eau": 20, "pourcentage_resistance_air": 20, "pourcentage_resistance_feu": 20},
-		},
-		{
-			Name:            "Dragoune_Rose",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 72 * time.Hour,
-			StatsMax:        map[string]int{"sagesse": 50},
-		},
-		{
-			Name:            "Willy_le_Relou",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 72 * time.Hour,
-			StatsMax:        map[string]int{"pourcentage_dommage": 50},
-		},
-		{
-			Name:            "Bebe_Pandawa",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 72 * time.Hour,
-			StatsMax:        map[string]int{"pourcentage_dommage": 50},
-		},
-		{
-			Name:            "Feanor",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 72 * time.Hour,
-			StatsMax:        map[string]int{"pourcentage_dommage": 50},
-		},
-		{
-			Name:            "Walk",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 72 * time.Hour,
-			StatsMax:        map[string]int{"pourcentage_dommage": 50},
-		},
-		{
-			Name:            "Mini_Wa",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 72 * time.Hour,
-			StatsMax:        map[string]int{"force": 80, "agilite": 80, "chance": 80, "intelligence": 80},
-		},
-		{
-			Name:            "Ross",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 72 * time.Hour,
-			StatsMax:        map[string]int{"soin": 6},
-		},
-		{
-			Name:            "Bilby",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 72 * time.Hour,
-			StatsMax:        map[string]int{"dommage": 10},
-		},
-		{
-			Name:            "Ecureuil_Chenapan",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 72 * time.Hour,
-			StatsMax:        map[string]int{"pourcentage_dommage": 50},
-		},
-		{
-			Name:            "Leopardo",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 72 * time.Hour,
-			StatsMax:        map[string]int{"pourcentage_dommage": 50},
-		},
-		{
-			Name:            "Chacha_Tigre",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 72 * time.Hour,
-			StatsMax:        map[string]int{"pourcentage_dommage": 50},
-		},
-		{
-			Name:            "Chacha_Angora",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 72 * time.Hour,
-			StatsMax:        map[string]int{"pourcentage_dommage": 50},
-		},
-		{
-			Name:            "Pioute_Bleu",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 72 * time.Hour,
-			StatsMax:        map[string]int{"chance": 80},
-		},
-		{
-			Name:            "Pioute_Jaune",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 72 * time.Hour,
-			StatsMax:        map[string]int{"agilite": 80},
-		},
-		{
-			Name:            "Pioute_Rouge",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 72 * time.Hour,
-			StatsMax:        map[string]int{"intelligence": 80},
-		},
-		{
-			Name:            "Pioute_Verte",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 72 * time.Hour,
-			StatsMax:        map[string]int{"force": 80},
-		},
-		{
-			Name:            "Pioute_Rose",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 72 * time.Hour,
-			StatsMax:        map[string]int{"soin": 10},
-		},
-		{
-			Name:            "Pioute_Violet",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 72 * time.Hour,
-			StatsMax:        map[string]int{"dommage": 10},
-		},
-		{
-			Name:            "Crocodaille",
-			FoodMinDuration: 5 * time.Hour,
-			FoodMaxDuration: 72 * time.Hour,
-			StatsMax:        map[string]int{"pourcentage_dommage": 50},
-		},
-	}
-}